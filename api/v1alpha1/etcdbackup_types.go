@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -45,15 +47,51 @@ const (
 	BackupPhaseFailed              BackupPhase = "Failed"
 )
 
+// ExecutionMode defines where the snapshot of a backup is taken and
+// uploaded
+// +kubebuilder:validation:Enum=InProcess;Job
+type ExecutionMode string
+
+const (
+	// ExecutionModeInProcess takes and uploads the snapshot in the operator
+	ExecutionModeInProcess ExecutionMode = "InProcess"
+
+	// ExecutionModeJob takes and uploads the snapshot in a Job on a
+	// control-plane node
+	ExecutionModeJob ExecutionMode = "Job"
+)
+
+// Condition types of backups
+const (
+	// BackupConditionReady is True once the backup completed and False
+	// while it runs or after it failed
+	BackupConditionReady = "Ready"
+
+	// BackupConditionValidated reports whether the backup passed the
+	// configuration and preflight checks and, when enabled, the
+	// validation of its uploaded snapshot
+	BackupConditionValidated = "Validated"
+
+	// BackupConditionUploaded reports whether the snapshot was stored
+	// according to the replication policy of the backup
+	BackupConditionUploaded = "Uploaded"
+
+	// BackupConditionVeleroSynced reports whether the Velero backup of a
+	// backup with Velero integration enabled was triggered
+	BackupConditionVeleroSynced = "VeleroSynced"
+)
+
 // StorageProvider defines the storage provider type
-// +kubebuilder:validation:Enum=S3;OSS;GCS;Azure
+// +kubebuilder:validation:Enum=S3;OSS;GCS;Azure;Filesystem;SFTP
 type StorageProvider string
 
 const (
-	StorageProviderS3    StorageProvider = "S3"
-	StorageProviderOSS   StorageProvider = "OSS"
-	StorageProviderGCS   StorageProvider = "GCS"
-	StorageProviderAzure StorageProvider = "Azure"
+	StorageProviderS3         StorageProvider = "S3"
+	StorageProviderOSS        StorageProvider = "OSS"
+	StorageProviderGCS        StorageProvider = "GCS"
+	StorageProviderAzure      StorageProvider = "Azure"
+	StorageProviderFilesystem StorageProvider = "Filesystem"
+	StorageProviderSFTP       StorageProvider = "SFTP"
 )
 
 // EtcdBackupSpec defines the desired state of EtcdBackup
@@ -74,9 +112,26 @@ type EtcdBackupSpec struct {
 	// +optional
 	EtcdCertificates *EtcdCertificates `json:"etcdCertificates,omitempty"`
 
-	// StorageLocation defines where to store the backup
-	// +kubebuilder:validation:Required
-	StorageLocation StorageLocation `json:"storageLocation"`
+	// StorageLocation defines where to store the backup. Either it or
+	// StorageLocationName must be set.
+	// +optional
+	StorageLocation *StorageLocation `json:"storageLocation,omitempty"`
+
+	// StorageLocationName is the name of the EtcdBackupStorageLocation, in
+	// the namespace of the backup, to store the backup in
+	// +optional
+	StorageLocationName string `json:"storageLocationName,omitempty"`
+
+	// Replicas are additional storage locations, in other providers or
+	// regions, that the snapshot and its manifest are replicated to
+	// +optional
+	Replicas []ReplicaLocation `json:"replicas,omitempty"`
+
+	// ReplicationPolicy defines which destinations must hold the snapshot
+	// before the backup counts as complete
+	// +kubebuilder:default=All
+	// +optional
+	ReplicationPolicy ReplicationPolicy `json:"replicationPolicy,omitempty"`
 
 	// Encryption configuration for backup encryption
 	// +optional
@@ -86,6 +141,12 @@ type EtcdBackupSpec struct {
 	// +optional
 	RetentionPolicy *RetentionPolicy `json:"retentionPolicy,omitempty"`
 
+	// LegalHold places a legal hold on the snapshot and manifest in every
+	// immutable storage destination, which prevents their deletion until
+	// the hold is released by clearing it, regardless of the retention
+	// +optional
+	LegalHold bool `json:"legalHold,omitempty"`
+
 	// Validation settings for backup validation
 	// +optional
 	Validation *ValidationConfig `json:"validation,omitempty"`
@@ -101,6 +162,123 @@ type EtcdBackupSpec struct {
 	// Hooks for pre/post backup scripts
 	// +optional
 	Hooks *BackupHooks `json:"hooks,omitempty"`
+
+	// BackoffLimit is the number of times a backup failing with a
+	// transient error, such as an etcd timeout, is retried before it fails
+	// for good
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// RetryPolicy defines the delays between the retries of a failing
+	// backup
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// ActiveDeadlineSeconds bounds the time the backup may run from its
+	// start, across retries, before it fails with reason DeadlineExceeded
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// Timeouts bound the phases of the backup
+	// +optional
+	Timeouts *BackupTimeouts `json:"timeouts,omitempty"`
+
+	// ExecutionMode selects whether the snapshot is taken and uploaded by
+	// the operator or by a short-lived Job on a control-plane node
+	// +kubebuilder:default=InProcess
+	// +optional
+	ExecutionMode ExecutionMode `json:"executionMode,omitempty"`
+
+	// Job configures the Job of the Job execution mode
+	// +optional
+	Job *JobExecution `json:"job,omitempty"`
+}
+
+// JobExecution configures the Job a backup in the Job execution mode takes
+// and uploads its snapshot in
+type JobExecution struct {
+	// ScratchSizeLimit bounds the emptyDir the snapshot is written to
+	// before it is uploaded
+	// +optional
+	ScratchSizeLimit *resource.Quantity `json:"scratchSizeLimit,omitempty"`
+
+	// Resources of the Job container
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Tolerations of the Job, added to the tolerations of the
+	// control-plane taints
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// JobReference names the Job of a backup
+type JobReference struct {
+	// Namespace of the Job
+	Namespace string `json:"namespace"`
+
+	// Name of the Job
+	Name string `json:"name"`
+}
+
+// BackupTimeouts bound the phases of a backup. A phase running longer
+// fails the backup with reason DeadlineExceeded. Phases without a timeout
+// are bounded by the active deadline only.
+type BackupTimeouts struct {
+	// Preflight bounds the validation of the configuration and the
+	// preflight checks, including waiting for an unavailable storage
+	// location
+	// +optional
+	Preflight *metav1.Duration `json:"preflight,omitempty"`
+
+	// Snapshot bounds taking the snapshot
+	// +optional
+	Snapshot *metav1.Duration `json:"snapshot,omitempty"`
+
+	// Upload bounds uploading the snapshot to every destination
+	// +optional
+	Upload *metav1.Duration `json:"upload,omitempty"`
+
+	// Validation bounds the verification and validation of the stored
+	// snapshot
+	// +optional
+	Validation *metav1.Duration `json:"validation,omitempty"`
+}
+
+// RetryPolicy defines the exponential backoff between the retries of a
+// failing backup
+type RetryPolicy struct {
+	// InitialBackoff is the delay before the first retry, doubled for
+	// every further retry. Defaults to 10s.
+	// +optional
+	InitialBackoff *metav1.Duration `json:"initialBackoff,omitempty"`
+
+	// MaxBackoff caps the delay between retries. Defaults to 5m.
+	// +optional
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+}
+
+// BackupFailure describes a failed attempt of a backup
+type BackupFailure struct {
+	// Attempt is the number of the attempt that failed, starting at 1
+	Attempt int32 `json:"attempt"`
+
+	// Phase the attempt failed in
+	Phase BackupPhase `json:"phase"`
+
+	// Reason is the machine-readable reason of the failure
+	Reason string `json:"reason"`
+
+	// Message describes the failure
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Time is when the attempt failed
+	Time metav1.Time `json:"time"`
 }
 
 // EtcdCertificates defines TLS certificates for etcd connection
@@ -120,11 +298,13 @@ type EtcdCertificates struct {
 
 // StorageLocation defines the storage backend configuration
 type StorageLocation struct {
-	// Provider specifies the storage provider (S3, OSS, GCS, Azure)
+	// Provider specifies the storage provider (S3, OSS, GCS, Azure, Filesystem, SFTP)
 	// +kubebuilder:validation:Required
 	Provider StorageProvider `json:"provider"`
 
-	// Bucket name
+	// Bucket name. For Azure this is the blob container, for Filesystem the
+	// absolute path of the mounted volume and for SFTP the root directory on
+	// the server.
 	// +kubebuilder:validation:Required
 	Bucket string `json:"bucket"`
 
@@ -132,19 +312,128 @@ type StorageLocation struct {
 	// +optional
 	Prefix string `json:"prefix,omitempty"`
 
-	// Region of the storage
-	// +kubebuilder:validation:Required
-	Region string `json:"region"`
+	// Region of the storage. Not used by the Azure, Filesystem and SFTP providers.
+	// +optional
+	Region string `json:"region,omitempty"`
 
-	// Endpoint for custom storage endpoint (e.g., MinIO)
+	// Endpoint for custom storage endpoint (e.g., MinIO), or the host:port of
+	// the SFTP server
 	// +optional
 	Endpoint string `json:"endpoint,omitempty"`
 
-	// CredentialsSecret is the name of the secret containing credentials
+	// CredentialsSecret is the name of the secret containing credentials.
+	// It may be omitted when the provider authenticates with a workload
+	// identity, such as an OSS RAM role or RRSA.
+	// +optional
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+
+	// Immutability stores snapshots write-once (WORM), so that they cannot
+	// be deleted or overwritten before the retention of the backup ends.
+	// Supported by the OSS (bucket retention policy) and Azure
+	// (version-level immutability) providers.
+	// +optional
+	Immutability *ImmutabilityConfig `json:"immutability,omitempty"`
+
+	// ServerSideEncryption configures the provider-native encryption of the
+	// stored objects, independently of the client-side Encryption of the
+	// backup. It is verified on the uploaded objects when the snapshot is
+	// validated. Supported by the OSS and GCS providers.
+	// +optional
+	ServerSideEncryption *ServerSideEncryption `json:"serverSideEncryption,omitempty"`
+}
+
+// ServerSideEncryptionMode defines the keys a storage provider encrypts
+// stored objects with
+// +kubebuilder:validation:Enum=SSE-S3;SSE-KMS;SSE-C
+type ServerSideEncryptionMode string
+
+const (
+	// ServerSideEncryptionSSES3 uses keys managed by the provider, such as
+	// OSS AES256 or Google-managed keys
+	ServerSideEncryptionSSES3 ServerSideEncryptionMode = "SSE-S3"
+	// ServerSideEncryptionSSEKMS uses a key of the key management service
+	// of the provider, such as OSS KMS or a GCS customer-managed key
+	ServerSideEncryptionSSEKMS ServerSideEncryptionMode = "SSE-KMS"
+	// ServerSideEncryptionSSEC uses a key supplied with every request, such
+	// as a GCS customer-supplied key
+	ServerSideEncryptionSSEC ServerSideEncryptionMode = "SSE-C"
+)
+
+// ServerSideEncryption defines the provider-native encryption of stored objects
+type ServerSideEncryption struct {
+	// Mode of the encryption. OSS does not support SSE-C.
+	// +kubebuilder:validation:Required
+	Mode ServerSideEncryptionMode `json:"mode"`
+
+	// KMSKeyID is the key used with SSE-KMS: a CMK ID for OSS and a Cloud
+	// KMS key resource name for GCS. OSS uses its default key when it is
+	// empty; GCS requires it.
+	// +optional
+	KMSKeyID string `json:"kmsKeyID,omitempty"`
+
+	// CustomerKeySecret is the name of the secret holding the base64
+	// encoded 256-bit AES key used with SSE-C under "sse-customer-key"
+	// +optional
+	CustomerKeySecret string `json:"customerKeySecret,omitempty"`
+}
+
+// ImmutabilityMode defines who may lift the retention of a stored snapshot
+// +kubebuilder:validation:Enum=Governance;Compliance
+type ImmutabilityMode string
+
+const (
+	// ImmutabilityModeGovernance lets principals with the permission to
+	// bypass retention shorten or remove it
+	ImmutabilityModeGovernance ImmutabilityMode = "Governance"
+	// ImmutabilityModeCompliance prevents anyone, including the account
+	// owner, from deleting the snapshot before the retention ends
+	ImmutabilityModeCompliance ImmutabilityMode = "Compliance"
+)
+
+// ImmutabilityConfig defines the write-once retention of the snapshots in a
+// storage location. Snapshots are retained until the start time of the
+// backup plus the MaxAge of its retention policy, which is required.
+type ImmutabilityConfig struct {
+	// Mode of the retention. OSS retention policies apply to the whole
+	// bucket and only support Compliance; the bucket must have a locked
+	// policy retaining objects long enough, which is not created for it.
+	// +kubebuilder:default=Governance
+	// +optional
+	Mode ImmutabilityMode `json:"mode,omitempty"`
+}
+
+// ReplicaLocation defines an additional storage location for a backup
+type ReplicaLocation struct {
+	// Name identifies the replica in the status. It must be unique within
+	// the backup and must not be "primary".
 	// +kubebuilder:validation:Required
-	CredentialsSecret string `json:"credentialsSecret"`
+	Name string `json:"name"`
+
+	// StorageLocation defines where to store the replica. Either it or
+	// StorageLocationName must be set.
+	// +optional
+	StorageLocation *StorageLocation `json:"storageLocation,omitempty"`
+
+	// StorageLocationName is the name of the EtcdBackupStorageLocation, in
+	// the namespace of the backup, to store the replica in
+	// +optional
+	StorageLocationName string `json:"storageLocationName,omitempty"`
 }
 
+// ReplicationPolicy defines when a replicated backup is complete
+// +kubebuilder:validation:Enum=All;Quorum;PrimaryAsync
+type ReplicationPolicy string
+
+const (
+	// ReplicationPolicyAll requires the primary and every replica
+	ReplicationPolicyAll ReplicationPolicy = "All"
+	// ReplicationPolicyQuorum requires a majority of all destinations
+	ReplicationPolicyQuorum ReplicationPolicy = "Quorum"
+	// ReplicationPolicyPrimaryAsync requires the primary only and copies to
+	// the replicas after the backup completed
+	ReplicationPolicyPrimaryAsync ReplicationPolicy = "PrimaryAsync"
+)
+
 // EncryptionConfig defines encryption settings
 type EncryptionConfig struct {
 	// Enabled specifies whether encryption is enabled
@@ -169,6 +458,37 @@ type RetentionPolicy struct {
 	// MaxAge is the maximum age of backups to retain (e.g., "720h")
 	// +optional
 	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+
+	// Tiering moves the snapshots of aging backups to colder storage
+	// classes. Rules apply in order of their After age.
+	// +optional
+	Tiering []TieringRule `json:"tiering,omitempty"`
+}
+
+// StorageTier is a provider-neutral storage class of stored snapshots
+// +kubebuilder:validation:Enum=InfrequentAccess;Archive
+type StorageTier string
+
+const (
+	// StorageTierInfrequentAccess is OSS IA and the Azure Cool tier.
+	// Snapshots stay readable right away.
+	StorageTierInfrequentAccess StorageTier = "InfrequentAccess"
+	// StorageTierArchive is OSS Archive and the Azure Archive tier.
+	// Snapshots must be rehydrated before a restore.
+	StorageTierArchive StorageTier = "Archive"
+)
+
+// TieringRule moves the snapshots of backups older than After to a colder
+// storage tier
+type TieringRule struct {
+	// After is the age of a backup, from its completion, after which its
+	// snapshots move to the tier (e.g., "168h")
+	// +kubebuilder:validation:Required
+	After metav1.Duration `json:"after"`
+
+	// Tier is the storage tier the snapshots move to
+	// +kubebuilder:validation:Required
+	Tier StorageTier `json:"tier"`
 }
 
 // ValidationConfig defines validation settings
@@ -204,27 +524,196 @@ type BackupHooks struct {
 	PostBackup []Hook `json:"postBackup,omitempty"`
 }
 
+// HookType is the kind of a hook
+type HookType string
+
+const (
+	// HookTypeExec runs a command in the containers of selected pods
+	HookTypeExec HookType = "Exec"
+	// HookTypeHTTP sends a request to a webhook
+	HookTypeHTTP HookType = "HTTP"
+)
+
+// HookErrorMode is what a failed hook does to the operation it runs in
+type HookErrorMode string
+
+const (
+	// HookErrorModeFail fails the operation
+	HookErrorModeFail HookErrorMode = "Fail"
+	// HookErrorModeContinue records the failure and carries on
+	HookErrorModeContinue HookErrorMode = "Continue"
+)
+
 // Hook defines a single hook configuration
 type Hook struct {
 	// Name of the hook
 	Name string `json:"name"`
 
 	// Type of hook (Exec, HTTP, etc.)
-	Type string `json:"type"`
+	// +kubebuilder:validation:Enum=Exec;HTTP
+	Type HookType `json:"type"`
+
+	// OnError is what a failure of the hook does to the operation. Fail
+	// fails it, Continue records the failure and carries on.
+	// +kubebuilder:validation:Enum=Fail;Continue
+	// +kubebuilder:default=Fail
+	// +optional
+	OnError HookErrorMode `json:"onError,omitempty"`
 
 	// Exec defines command execution hook
 	// +optional
 	Exec *ExecHook `json:"exec,omitempty"`
+
+	// HTTP defines webhook request hook
+	// +optional
+	HTTP *HTTPHook `json:"http,omitempty"`
 }
 
 // ExecHook defines command execution hook
 type ExecHook struct {
+	// PodSelector selects the running pods, in the namespace of the
+	// backup, to execute the command in. The command runs in every pod
+	// selected.
+	PodSelector *metav1.LabelSelector `json:"podSelector"`
+
+	// Container to execute the command in, the first container of the pod
+	// if empty
+	// +optional
+	Container string `json:"container,omitempty"`
+
 	// Command to execute
 	Command []string `json:"command"`
 
-	// Timeout for command execution
+	// Timeout for command execution, 30s if not set
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// HTTPHook defines webhook request hook
+type HTTPHook struct {
+	// URL of the webhook. Loopback, link-local and cloud metadata
+	// addresses are refused.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// Method of the request
+	// +kubebuilder:validation:Enum=GET;POST;PUT;PATCH;DELETE
+	// +kubebuilder:default=POST
+	// +optional
+	Method string `json:"method,omitempty"`
+
+	// Headers of the request
+	// +optional
+	Headers []HTTPHeader `json:"headers,omitempty"`
+
+	// Body is a Go template of the request body. It is executed with the
+	// Kind, Name, Namespace, Stage and Phase of the backup or restore, and
+	// the backup or restore itself as Object. A JSON object of the fields
+	// is sent if empty.
+	// +optional
+	Body string `json:"body,omitempty"`
+
+	// ExpectedStatusCodes are the status codes of a successful request,
+	// any 2xx code if empty
+	// +optional
+	ExpectedStatusCodes []int32 `json:"expectedStatusCodes,omitempty"`
+
+	// Timeout of each attempt of the request, 30s if not set
 	// +optional
 	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Retries of a request failing with a connection error, 429 or 5xx
+	// status, with exponential backoff
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	Retries int32 `json:"retries,omitempty"`
+}
+
+// HTTPHeader is a header of a webhook request
+type HTTPHeader struct {
+	// Name of the header
+	Name string `json:"name"`
+
+	// Value of the header
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// ValueFrom reads the value from a secret in the namespace of the
+	// backup or restore, such as a bearer token
+	// +optional
+	ValueFrom *SecretKeySelector `json:"valueFrom,omitempty"`
+}
+
+// SecretKeySelector selects a key of a secret in the namespace of the
+// object referencing it
+type SecretKeySelector struct {
+	// Name of the secret
+	Name string `json:"name"`
+
+	// Key of the secret
+	Key string `json:"key"`
+}
+
+// HookStage is the point of an operation a hook runs at
+type HookStage string
+
+const (
+	HookStagePreBackup   HookStage = "PreBackup"
+	HookStagePostBackup  HookStage = "PostBackup"
+	HookStagePreRestore  HookStage = "PreRestore"
+	HookStagePostRestore HookStage = "PostRestore"
+)
+
+// HookResult records one run of a hook
+type HookResult struct {
+	// Name of the hook
+	Name string `json:"name"`
+
+	// Stage the hook ran at
+	Stage HookStage `json:"stage"`
+
+	// Pod the command of an exec hook ran in, as namespace/name
+	// +optional
+	Pod string `json:"pod,omitempty"`
+
+	// Container the command of an exec hook ran in
+	// +optional
+	Container string `json:"container,omitempty"`
+
+	// Succeeded reports whether the hook succeeded
+	Succeeded bool `json:"succeeded"`
+
+	// ExitCode of the command of an exec hook, unset when the command did
+	// not run to completion
+	// +optional
+	ExitCode *int32 `json:"exitCode,omitempty"`
+
+	// StatusCode of the last response to the request of an HTTP hook
+	// +optional
+	StatusCode int32 `json:"statusCode,omitempty"`
+
+	// Attempts is the number of requests an HTTP hook sent
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// Output is the end of the combined standard output and error of the
+	// command of an exec hook. Response bodies of HTTP hooks are not
+	// recorded.
+	// +optional
+	Output string `json:"output,omitempty"`
+
+	// Message explains a failure of the hook
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// StartTime is when the hook started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the hook finished
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 }
 
 // EtcdBackupStatus defines the observed state of EtcdBackup
@@ -237,10 +726,31 @@ type EtcdBackupStatus struct {
 	// +optional
 	SnapshotSize int64 `json:"snapshotSize,omitempty"`
 
+	// ExpectedSnapshotSize is the size of the etcd database found by the
+	// preflight checks, which storage quotas count for the backup until its
+	// snapshot is taken
+	// +optional
+	ExpectedSnapshotSize int64 `json:"expectedSnapshotSize,omitempty"`
+
 	// SnapshotLocation is the full path where the snapshot is stored
 	// +optional
 	SnapshotLocation string `json:"snapshotLocation,omitempty"`
 
+	// Replicas reports the state of every storage destination of the
+	// backup, the primary location first
+	// +optional
+	Replicas []ReplicaStatus `json:"replicas,omitempty"`
+
+	// UploadSessions tracks the multipart uploads in progress so that an
+	// upload interrupted by an operator restart resumes where it stopped
+	// +optional
+	UploadSessions []UploadSession `json:"uploadSessions,omitempty"`
+
+	// StorageAttempts records the most recent attempts of storage
+	// operations, oldest first
+	// +optional
+	StorageAttempts []StorageAttempt `json:"storageAttempts,omitempty"`
+
 	// EtcdRevision is the etcd revision at the time of backup
 	// +optional
 	EtcdRevision int64 `json:"etcdRevision,omitempty"`
@@ -249,6 +759,35 @@ type EtcdBackupStatus struct {
 	// +optional
 	ValidationResult *ValidationResult `json:"validationResult,omitempty"`
 
+	// HookResults records the runs of the pre and post backup hooks, in
+	// the order they ran
+	// +optional
+	HookResults []HookResult `json:"hookResults,omitempty"`
+
+	// Attempts is the number of attempts of the backup, including the
+	// one running or waiting for its backoff
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// LastFailure describes the last failed attempt of the backup
+	// +optional
+	LastFailure *BackupFailure `json:"lastFailure,omitempty"`
+
+	// NextRetryTime is when the next attempt of a backup waiting for its
+	// backoff starts
+	// +optional
+	NextRetryTime *metav1.Time `json:"nextRetryTime,omitempty"`
+
+	// PhaseStartTime is when the backup entered its phase, or when the
+	// attempt retrying the phase starts
+	// +optional
+	PhaseStartTime *metav1.Time `json:"phaseStartTime,omitempty"`
+
+	// Job is the last Job the snapshot of a backup in the Job execution
+	// mode was taken and uploaded in
+	// +optional
+	Job *JobReference `json:"job,omitempty"`
+
 	// StartTime is when the backup started
 	// +optional
 	StartTime *metav1.Time `json:"startTime,omitempty"`
@@ -269,11 +808,133 @@ type EtcdBackupStatus struct {
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
+	// Reason is a machine-readable reason for a failed backup, such as
+	// StorageAuthFailed
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
 	// Message provides additional information about the current state
 	// +optional
 	Message string `json:"message,omitempty"`
 }
 
+// ReplicaPhase is the state of a backup in one storage destination
+type ReplicaPhase string
+
+const (
+	ReplicaPhasePending   ReplicaPhase = "Pending"
+	ReplicaPhaseCompleted ReplicaPhase = "Completed"
+	ReplicaPhaseFailed    ReplicaPhase = "Failed"
+)
+
+// ReplicaStatus contains the state of a backup in one storage destination
+type ReplicaStatus struct {
+	// Name of the destination, "primary" for the spec storage location
+	Name string `json:"name"`
+
+	// Provider of the destination
+	// +optional
+	Provider StorageProvider `json:"provider,omitempty"`
+
+	// Phase of the replication to this destination
+	Phase ReplicaPhase `json:"phase"`
+
+	// Location is the full path of the snapshot in this destination
+	// +optional
+	Location string `json:"location,omitempty"`
+
+	// ManifestLocation is the full path of the backup manifest in this destination
+	// +optional
+	ManifestLocation string `json:"manifestLocation,omitempty"`
+
+	// Hash is the SHA-256 of the snapshot stored in this destination
+	// +optional
+	Hash string `json:"hash,omitempty"`
+
+	// Reason is a machine-readable reason for a failed replication, such
+	// as StorageQuotaExceeded
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message provides additional information, such as the upload error
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// CompletionTime is when the snapshot was stored in this destination
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// RetainUntil is when the retention of the immutable snapshot in this
+	// destination ends
+	// +optional
+	RetainUntil *metav1.Time `json:"retainUntil,omitempty"`
+
+	// LegalHold reports whether a legal hold is placed on the snapshot in
+	// this destination
+	// +optional
+	LegalHold bool `json:"legalHold,omitempty"`
+
+	// Tier is the storage tier the snapshot in this destination was moved
+	// to, empty for the default storage class of the location
+	// +optional
+	Tier StorageTier `json:"tier,omitempty"`
+}
+
+// UploadSession is a multipart upload of a snapshot to a storage destination
+type UploadSession struct {
+	// Destination is the replica name, "primary" for the spec storage location
+	Destination string `json:"destination"`
+
+	// RemotePath is the full path of the object being uploaded
+	RemotePath string `json:"remotePath"`
+
+	// UploadID identifies the multipart upload in the storage backend
+	UploadID string `json:"uploadID"`
+
+	// PartSize is the size of every part but the last in bytes
+	PartSize int64 `json:"partSize"`
+
+	// CompletedParts lists the parts stored so far
+	// +optional
+	CompletedParts []CompletedPart `json:"completedParts,omitempty"`
+
+	// StartTime is when the upload was initiated
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+}
+
+// StorageAttempt is one attempt of an operation on a storage destination
+type StorageAttempt struct {
+	// Destination is the replica name, "primary" for the spec storage location
+	Destination string `json:"destination"`
+
+	// Operation attempted, such as Upload or Lock
+	Operation string `json:"operation"`
+
+	// Attempt number of the operation, starting at 1
+	Attempt int32 `json:"attempt"`
+
+	// Time is when the attempt started
+	Time metav1.Time `json:"time"`
+
+	// Result is Succeeded, or the class of the error: Retryable, Auth,
+	// NotFound, Quota or Permanent
+	Result string `json:"result"`
+
+	// Message is the error of a failed attempt
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// CompletedPart is a part of a multipart upload stored in the backend
+type CompletedPart struct {
+	// PartNumber of the part, starting at 1
+	PartNumber int32 `json:"partNumber"`
+
+	// ETag returned by the backend for the part
+	ETag string `json:"etag"`
+}
+
 // ValidationResult contains the results of backup validation
 type ValidationResult struct {
 	// Valid indicates whether the backup passed validation
@@ -292,6 +953,7 @@ type ValidationResult struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:shortName=etcdbkp
 // +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
 // +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.backupMode`
 // +kubebuilder:printcolumn:name="Size",type=string,JSONPath=`.status.snapshotSize`
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
@@ -0,0 +1,158 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StorageLocationConditionAvailable is the condition type reporting whether
+// the storage location passed its last access check
+const StorageLocationConditionAvailable = "Available"
+
+// UnavailableAction defines what backups against an unavailable storage
+// location do
+// +kubebuilder:validation:Enum=Wait;Fail
+type UnavailableAction string
+
+const (
+	// UnavailableActionWait keeps backups pending until the location is
+	// available again
+	UnavailableActionWait UnavailableAction = "Wait"
+	// UnavailableActionFail fails backups right away
+	UnavailableActionFail UnavailableAction = "Fail"
+)
+
+// EtcdBackupStorageLocationSpec defines the desired state of EtcdBackupStorageLocation
+type EtcdBackupStorageLocationSpec struct {
+	// StorageLocation is the storage backend configuration shared by the
+	// backups, schedules and restores referencing this location
+	StorageLocation `json:",inline"`
+
+	// ValidationFrequency is how often write, read, list and delete access
+	// to the location is checked
+	// +kubebuilder:default="5m"
+	// +optional
+	ValidationFrequency *metav1.Duration `json:"validationFrequency,omitempty"`
+
+	// UnavailableAction defines whether backups against the location wait
+	// while it is unavailable or fail right away
+	// +kubebuilder:default=Wait
+	// +optional
+	UnavailableAction UnavailableAction `json:"unavailableAction,omitempty"`
+
+	// Quota limits the backups each namespace or tenant stores in the
+	// bucket of the location, under any prefix, including backups that do
+	// not reference the location and configure the same bucket inline
+	// +optional
+	Quota *StorageQuota `json:"quota,omitempty"`
+}
+
+// StorageQuota limits the backups stored per namespace, or per tenant when
+// namespaces are grouped by a label. Backups over quota fail before their
+// snapshot is taken.
+type StorageQuota struct {
+	// MaxBytes is the maximum total size of the stored snapshots, counted
+	// once per destination holding them
+	// +optional
+	MaxBytes *resource.Quantity `json:"maxBytes,omitempty"`
+
+	// MaxBackups is the maximum number of stored backups
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxBackups *int32 `json:"maxBackups,omitempty"`
+
+	// TenantLabel is a namespace label whose value groups namespaces into
+	// tenants sharing the quota. Namespaces without the label have a quota
+	// of their own.
+	// +optional
+	TenantLabel string `json:"tenantLabel,omitempty"`
+}
+
+// StorageUsage is the storage used by the backups of a namespace or tenant
+type StorageUsage struct {
+	// Namespace of the backups, empty for a tenant
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Tenant is the value of the tenant label of the namespaces
+	// +optional
+	Tenant string `json:"tenant,omitempty"`
+
+	// Backups is the number of stored backups
+	Backups int32 `json:"backups"`
+
+	// Bytes is the total size of the stored snapshots
+	Bytes resource.Quantity `json:"bytes"`
+}
+
+// EtcdBackupStorageLocationStatus defines the observed state of EtcdBackupStorageLocation
+type EtcdBackupStorageLocationStatus struct {
+	// LastValidationTime is when access to the location was last checked
+	// +optional
+	LastValidationTime *metav1.Time `json:"lastValidationTime,omitempty"`
+
+	// LastAvailableTime is when the location last passed the access check
+	// +optional
+	LastAvailableTime *metav1.Time `json:"lastAvailableTime,omitempty"`
+
+	// Usage is the storage used in the bucket of the location per
+	// namespace, or per tenant with the tenant label of the quota,
+	// including backups in progress
+	// +optional
+	Usage []StorageUsage `json:"usage,omitempty"`
+
+	// Conditions represent the latest available observations of the location's state
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Message provides additional information about the current state
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=etcdbsl
+// +kubebuilder:printcolumn:name="Provider",type=string,JSONPath=`.spec.provider`
+// +kubebuilder:printcolumn:name="Bucket",type=string,JSONPath=`.spec.bucket`
+// +kubebuilder:printcolumn:name="Available",type=string,JSONPath=`.status.conditions[?(@.type=="Available")].status`
+// +kubebuilder:printcolumn:name="LastValidated",type="date",JSONPath=".status.lastValidationTime"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// EtcdBackupStorageLocation is the Schema for the etcdbackupstoragelocations API
+type EtcdBackupStorageLocation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EtcdBackupStorageLocationSpec   `json:"spec,omitempty"`
+	Status EtcdBackupStorageLocationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// EtcdBackupStorageLocationList contains a list of EtcdBackupStorageLocation
+type EtcdBackupStorageLocationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EtcdBackupStorageLocation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EtcdBackupStorageLocation{}, &EtcdBackupStorageLocationList{})
+}
@@ -31,16 +31,17 @@ const (
 )
 
 // RestorePhase defines the phase of restore
-// +kubebuilder:validation:Enum=Pending;Quiescing;Restoring;Validating;Completed;Failed
+// +kubebuilder:validation:Enum=Pending;Rehydrating;Quiescing;Restoring;Validating;Completed;Failed
 type RestorePhase string
 
 const (
-	RestorePhasePending    RestorePhase = "Pending"
-	RestorePhaseQuiescing  RestorePhase = "Quiescing"
-	RestorePhaseRestoring  RestorePhase = "Restoring"
-	RestorePhaseValidating RestorePhase = "Validating"
-	RestorePhaseCompleted  RestorePhase = "Completed"
-	RestorePhaseFailed     RestorePhase = "Failed"
+	RestorePhasePending     RestorePhase = "Pending"
+	RestorePhaseRehydrating RestorePhase = "Rehydrating"
+	RestorePhaseQuiescing   RestorePhase = "Quiescing"
+	RestorePhaseRestoring   RestorePhase = "Restoring"
+	RestorePhaseValidating  RestorePhase = "Validating"
+	RestorePhaseCompleted   RestorePhase = "Completed"
+	RestorePhaseFailed      RestorePhase = "Failed"
 )
 
 // EtcdRestoreSpec defines the desired state of EtcdRestore
@@ -53,6 +54,11 @@ type EtcdRestoreSpec struct {
 	// +optional
 	SnapshotLocation string `json:"snapshotLocation,omitempty"`
 
+	// StorageLocationName is the name of the EtcdBackupStorageLocation the
+	// SnapshotLocation is read from
+	// +optional
+	StorageLocationName string `json:"storageLocationName,omitempty"`
+
 	// RestoreMode specifies the restore mode
 	// +kubebuilder:validation:Required
 	RestoreMode RestoreMode `json:"restoreMode"`
@@ -85,6 +91,38 @@ type EtcdRestoreSpec struct {
 	// NamespaceFilter filters namespaces to restore (multi-tenant)
 	// +optional
 	NamespaceFilter []string `json:"namespaceFilter,omitempty"`
+
+	// ActiveDeadlineSeconds bounds the time the restore may run from its
+	// start before it fails with reason DeadlineExceeded
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// Timeouts bound the phases of the restore
+	// +optional
+	Timeouts *RestoreTimeouts `json:"timeouts,omitempty"`
+}
+
+// RestoreTimeouts bound the phases of a restore. A phase running longer
+// fails the restore with reason DeadlineExceeded. Phases without a timeout
+// are bounded by the active deadline only.
+type RestoreTimeouts struct {
+	// Rehydration bounds waiting for the rehydration of an archived
+	// snapshot
+	// +optional
+	Rehydration *metav1.Duration `json:"rehydration,omitempty"`
+
+	// Quiesce bounds running the pre-restore hooks
+	// +optional
+	Quiesce *metav1.Duration `json:"quiesce,omitempty"`
+
+	// Restore bounds restoring the snapshot
+	// +optional
+	Restore *metav1.Duration `json:"restore,omitempty"`
+
+	// Validation bounds validating the restored cluster
+	// +optional
+	Validation *metav1.Duration `json:"validation,omitempty"`
 }
 
 // EtcdClusterConfig defines etcd cluster configuration
@@ -136,10 +174,33 @@ type EtcdRestoreStatus struct {
 	// +optional
 	StartTime *metav1.Time `json:"startTime,omitempty"`
 
+	// SnapshotLocation is the location of the snapshot being restored
+	// +optional
+	SnapshotLocation string `json:"snapshotLocation,omitempty"`
+
+	// HookResults records the runs of the pre and post restore hooks, in
+	// the order they ran
+	// +optional
+	HookResults []HookResult `json:"hookResults,omitempty"`
+
+	// RehydrationRequestTime is when rehydration of the archived snapshot
+	// was requested
+	// +optional
+	RehydrationRequestTime *metav1.Time `json:"rehydrationRequestTime,omitempty"`
+
 	// CompletionTime is when the restore completed
 	// +optional
 	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 
+	// PhaseStartTime is when the restore entered its phase
+	// +optional
+	PhaseStartTime *metav1.Time `json:"phaseStartTime,omitempty"`
+
+	// Reason is a machine-readable reason for a failed restore, such as
+	// DeadlineExceeded
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
 	// Conditions represent the latest available observations of the restore's state
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
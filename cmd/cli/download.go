@@ -0,0 +1,175 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/download"
+	"github.com/etcdguardian/etcdguardian/pkg/replication"
+)
+
+func backupDownloadURLCmd() *cobra.Command {
+	var (
+		namespace   string
+		expiry      time.Duration
+		destination string
+		server      string
+		token       string
+		caFile      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "download-url [backup-name]",
+		Short: "Print a presigned download URL of a backup snapshot",
+		Long: `Print a time-limited URL that downloads the snapshot of a completed backup
+without storage credentials.
+
+With --server the URL is requested from the download endpoint of the operator,
+which checks that the user of --token may get the etcdbackups/download
+subresource of the EtcdBackup. The token must be issued for the etcdguardian-download audience, e.g. with
+"kubectl create token <service-account> --audience etcdguardian-download".
+Otherwise the URL is signed locally, which requires read access to the
+EtcdBackup and to the credentials secrets of its storage locations.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var signedURL string
+			var err error
+			if server != "" {
+				if token == "" {
+					return fmt.Errorf("--server requires a --token for the %s audience", download.Audience)
+				}
+				signedURL, err = requestDownloadURL(cmd.Context(), server, token, caFile, namespace, args[0], destination, expiry)
+			} else {
+				restConfig, configErr := config.GetConfig()
+				if configErr != nil {
+					return fmt.Errorf("failed to load kubeconfig: %w", configErr)
+				}
+				signedURL, err = presignDownloadURL(cmd.Context(), restConfig, namespace, args[0], destination, expiry)
+			}
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), signedURL)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "etcd-guardian-system", "Namespace")
+	cmd.Flags().DurationVar(&expiry, "expiry", download.DefaultExpiry, "How long the URL is valid, at most 168h")
+	cmd.Flags().StringVar(&destination, "destination", "", "Destination to download from (default: the first one that is not archived)")
+	cmd.Flags().StringVar(&server, "server", "", "URL of the download endpoint of the operator")
+	cmd.Flags().StringVar(&token, "token", "", "Bearer token for the download endpoint, issued for the etcdguardian-download audience")
+	cmd.Flags().StringVar(&caFile, "certificate-authority", "", "CA certificate of the download endpoint")
+
+	return cmd
+}
+
+// presignDownloadURL signs the URL with the storage credentials the
+// kubeconfig user can read
+func presignDownloadURL(ctx context.Context, restConfig *rest.Config, namespace, name, destination string, expiry time.Duration) (string, error) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return "", err
+	}
+	if err := etcdguardianv1alpha1.AddToScheme(scheme); err != nil {
+		return "", err
+	}
+	k8sClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return "", fmt.Errorf("failed to create client: %w", err)
+	}
+
+	backup := &etcdguardianv1alpha1.EtcdBackup{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, backup); err != nil {
+		return "", fmt.Errorf("failed to get backup %s/%s: %w", namespace, name, err)
+	}
+	return download.PresignURL(ctx, replication.NewReplicator(k8sClient, logr.Discard()), backup, destination, expiry)
+}
+
+// requestDownloadURL asks the download endpoint of the operator for the URL
+func requestDownloadURL(ctx context.Context, server, token, caFile, namespace, name, destination string, expiry time.Duration) (string, error) {
+	query := url.Values{"expiry": []string{expiry.String()}}
+	if destination != "" {
+		query.Set("destination", destination)
+	}
+	endpoint := strings.TrimSuffix(server, "/") + download.Path(namespace, name) + "?" + query.Encode()
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	if caFile != "" {
+		ca, err := os.ReadFile(caFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read certificate authority: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return "", fmt.Errorf("no certificates in %s", caFile)
+		}
+		httpClient.Transport = &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}}
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to request download URL: %w", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		failure := struct {
+			Error string `json:"error"`
+		}{}
+		if json.Unmarshal(body, &failure) == nil && failure.Error != "" {
+			return "", fmt.Errorf("download endpoint returned %s: %s", response.Status, failure.Error)
+		}
+		return "", fmt.Errorf("download endpoint returned %s", response.Status)
+	}
+
+	result := download.Response{}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("invalid response: %w", err)
+	}
+	return result.URL, nil
+}
@@ -57,307 +57,21 @@ func backupCmd() *cobra.Command {
 	cmd.AddCommand(backupCreateCmd())
 	cmd.AddCommand(backupListCmd())
 	cmd.AddCommand(backupDeleteCmd())
+	cmd.AddCommand(backupDownloadURLCmd())
 
 	return cmd
 }
 
 func backupCreateCmd() *cobra.Command {
 	var (
-		name         string
-		namespace    string
-		mode         string
-		bucket       string
-		provider     string
-		region       string
-		credSecret   string
-		schedule     string
-	)
-
-	cmd := &cobra.Command{
-		Use:   "create",
-		Short: "Create a new etcd backup",
-		Long:  "Create a new etcd backup with specified configuration",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			// TODO: Implement backup creation using Kubernetes client
-			cmd.Printf("Creating backup: %s\n", name)
-			cmd.Printf("  Mode: %s\n", mode)
-			cmd.Printf("  Storage: %s/%s\n", provider, bucket)
-			return nil
-		},
-	}
-
-	cmd.Flags().StringVarP(&name, "name", "n", "", "Backup name (required)")
-	cmd.Flags().StringVar(&namespace, "namespace", "etcd-guardian-system", "Namespace")
-	cmd.Flags().StringVar(&mode, "mode", "Full", "Backup mode: Full or Incremental")
-	cmd.Flags().StringVar(&bucket, "bucket", "", "Storage bucket (required)")
-	cmd.Flags().StringVar(&provider, "provider", "S3", "Storage provider: S3, OSS, GCS, Azure")
-	cmd.Flags().StringVar(&region, "region", "", "Storage region (required)")
-	cmd.Flags().StringVar(&credSecret, "credentials-secret", "", "Credentials secret name (required)")
-	cmd.Flags().StringVar(&schedule, "schedule", "", "Cron schedule for periodic backups")
-
-	cmd.MarkFlagRequired("name")
-	cmd.MarkFlagRequired("bucket")
-	cmd.MarkFlagRequired("region")
-	cmd.MarkFlagRequired("credentials-secret")
-
-	return cmd
-}
-
-func backupListCmd() *cobra.Command {
-	var namespace string
-
-	cmd := &cobra.Command{
-		Use:   "list",
-		Short: "List etcd backups",
-		Long:  "List all etcd backups in the specified namespace",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			// TODO: Implement backup listing
-			cmd.Printf("Listing backups in namespace: %s\n", namespace)
-			return nil
-		},
-	}
-
-	cmd.Flags().StringVarP(&namespace, "namespace", "n", "etcd-guardian-system", "Namespace")
-
-	return cmd
-}
-
-func backupDeleteCmd() *cobra.Command {
-	var namespace string
-
-	cmd := &cobra.Command{
-		Use:   "delete [backup-name]",
-		Short: "Delete an etcd backup",
-		Long:  "Delete the specified etcd backup resource",
-		Args:  cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			// TODO: Implement backup deletion
-			cmd.Printf("Deleting backup: %s\n", args[0])
-			return nil
-		},
-	}
-
-	cmd.Flags().StringVarP(&namespace, "namespace", "n", "etcd-guardian-system", "Namespace")
-
-	return cmd
-}
-
-func restoreCmd() *cobra.Command {
-	var (
-		backupName string
+		name       string
 		namespace  string
-		endpoints  []string
-		dataDir    string
-	)
-
-	cmd := &cobra.Command{
-		Use:   "restore",
-		Short: "Restore etcd from backup",
-		Long:  "Restore etcd cluster from a previous backup",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			// TODO: Implement restore operation
-			cmd.Printf("Restoring from backup: %s\n", backupName)
-			cmd.Printf("  Target endpoints: %v\n", endpoints)
-			return nil
-		},
-	}
-
-	cmd.Flags().StringVar(&backupName, "backup", "", "Backup name to restore from (required)")
-	cmd.Flags().StringVarP(&namespace, "namespace", "n", "etcd-guardian-system", "Namespace")
-	cmd.Flags().StringSliceVar(&endpoints, "endpoints", nil, "etcd endpoints (required)")
-	cmd.Flags().StringVar(&dataDir, "data-dir", "/var/lib/etcd", "etcd data directory")
-
-	cmd.MarkFlagRequired("backup")
-	cmd.MarkFlagRequired("endpoints")
-
-	return cmd
-}
-
-func listCmd() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "list",
-		Short: "List resources",
-		Long:  "List EtcdGuardian resources (backups, restores, schedules)",
-	}
-
-	cmd.AddCommand(listBackupsCmd())
-	cmd.AddCommand(listRestoresCmd())
-	cmd.AddCommand(listSchedulesCmd())
-
-	return cmd
-}
-
-func listBackupsCmd() *cobra.Command {
-	var namespace string
-
-	cmd := &cobra.Command{
-		Use:   "backups",
-		Short: "List all backups",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			// TODO: Implement
-			return nil
-		},
-	}
-
-	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (empty for all)")
-
-	return cmd
-}
-
-func listRestoresCmd() *cobra.Command {
-	var namespace string
-
-	cmd := &cobra.Command{
-		Use:   "restores",
-		Short: "List all restores",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			// TODO: Implement
-			return nil
-		},
-	}
-
-	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (empty for all)")
-
-	return cmd
-}
-
-func listSchedulesCmd() *cobra.Command {
-	var namespace string
-
-	cmd := &cobra.Command{
-		Use:   "schedules",
-		Short: "List all backup schedules",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			// TODO: Implement
-			return nil
-		},
-	}
-
-	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (empty for all)")
-
-	return cmd
-}
-
-func veleroCmd() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "velero",
-		Short: "Velero integration commands",
-		Long:  "Manage Velero integration and installation",
-	}
-
-	cmd.AddCommand(veleroInstallCmd())
-
-	return cmd
-}
-
-func veleroInstallCmd() *cobra.Command {
-	var (
-		provider   string
+		mode       string
 		bucket     string
+		provider   string
 		region     string
-		accessKey  string
-		secretKey  string
-		enableEtcd bool
-	)
-
-	cmd := &cobra.Command{
-		Use:   "install",
-		Short: "Install Velero with EtcdGuardian integration",
-		Long:  "Automatically install and configure Velero with etcd integration",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			// TODO: Implement Velero installation
-			cmd.Printf("Installing Velero with provider: %s\n", provider)
-			cmd.Printf("  Bucket: %s\n", bucket)
-			cmd.Printf("  Etcd integration: %v\n", enableEtcd)
-			return nil
-		},
-	}
-
-	cmd.Flags().StringVar(&provider, "storage-provider", "oss", "Storage provider: s3, oss, gcs, azure")
-	cmd.Flags().StringVar(&bucket, "bucket", "", "Storage bucket (required)")
-	cmd.Flags().StringVar(&region, "region", "", "Storage region (required)")
-	cmd.Flags().StringVar(&accessKey, "access-key-id", "", "Access key ID")
-	cmd.Flags().StringVar(&secretKey, "secret-access-key", "", "Secret access key")
-	cmd.Flags().BoolVar(&enableEtcd, "enable-etcd-integration", true, "Enable etcd integration")
-
-	cmd.MarkFlagRequired("bucket")
-	cmd.MarkFlagRequired("region")
-
-	return cmd
-}
-/*
-Copyright 2026 EtcdGuardian Authors.
-
-Licensed under the Apache License, Version 2.0 (the "License");
-you may not use this file except in compliance with the License.
-You may obtain a copy of the License at
-
-    http://www.apache.org/licenses/LICENSE-2.0
-
-Unless required by applicable law or agreed to in writing, software
-distributed under the License is distributed on an "AS IS" BASIS,
-WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-See the License for the specific language governing permissions and
-limitations under the License.
-*/
-
-package main
-
-import (
-	"os"
-
-	"github.com/spf13/cobra"
-)
-
-var (
-	version = "0.1.0"
-)
-
-func main() {
-	rootCmd := &cobra.Command{
-		Use:   "etcdguardian",
-		Short: "EtcdGuardian CLI - Manage etcd backup and restore operations",
-		Long: `EtcdGuardian is a comprehensive tool for etcd backup, restore, and disaster recovery.
-It provides advanced features like incremental backups, multi-tenant isolation,
-and seamless integration with Velero.`,
-		Version: version,
-	}
-
-	// Add subcommands
-	rootCmd.AddCommand(backupCmd())
-	rootCmd.AddCommand(restoreCmd())
-	rootCmd.AddCommand(listCmd())
-	rootCmd.AddCommand(veleroCmd())
-
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
-	}
-}
-
-func backupCmd() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "backup",
-		Short: "Manage etcd backups",
-		Long:  "Create, list, and manage etcd backup operations",
-	}
-
-	cmd.AddCommand(backupCreateCmd())
-	cmd.AddCommand(backupListCmd())
-	cmd.AddCommand(backupDeleteCmd())
-
-	return cmd
-}
-
-func backupCreateCmd() *cobra.Command {
-	var (
-		name         string
-		namespace    string
-		mode         string
-		bucket       string
-		provider     string
-		region       string
-		credSecret   string
-		schedule     string
+		credSecret string
+		schedule   string
 	)
 
 	cmd := &cobra.Command{
@@ -377,7 +91,7 @@ func backupCreateCmd() *cobra.Command {
 	cmd.Flags().StringVar(&namespace, "namespace", "etcd-guardian-system", "Namespace")
 	cmd.Flags().StringVar(&mode, "mode", "Full", "Backup mode: Full or Incremental")
 	cmd.Flags().StringVar(&bucket, "bucket", "", "Storage bucket (required)")
-	cmd.Flags().StringVar(&provider, "provider", "S3", "Storage provider: S3, OSS, GCS, Azure")
+	cmd.Flags().StringVar(&provider, "provider", "S3", "Storage provider: S3, OSS, GCS, Azure, Filesystem, SFTP")
 	cmd.Flags().StringVar(&region, "region", "", "Storage region (required)")
 	cmd.Flags().StringVar(&credSecret, "credentials-secret", "", "Credentials secret name (required)")
 	cmd.Flags().StringVar(&schedule, "schedule", "", "Cron schedule for periodic backups")
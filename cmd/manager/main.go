@@ -3,12 +3,17 @@ package main
 import (
 	"flag"
 	"os"
+	"strings"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
@@ -16,6 +21,10 @@ import (
 
 	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
 	"github.com/etcdguardian/etcdguardian/controllers"
+	"github.com/etcdguardian/etcdguardian/pkg/backupjob"
+	"github.com/etcdguardian/etcdguardian/pkg/download"
+	"github.com/etcdguardian/etcdguardian/pkg/hooks"
+	"github.com/etcdguardian/etcdguardian/pkg/replication"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -34,12 +43,67 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var snapshotDir string
+	var uploadGracePeriod time.Duration
+	var catalogSyncInterval time.Duration
+	var etcdPollInterval time.Duration
+	var etcdPollMaxEndpoints int
+	var quotaMaxBytes string
+	var quotaMaxBackups int
+	var quotaTenantLabel string
+	var quotaUsageConfigMap string
+	var quotaUsageNamespace string
+	var downloadAddr string
+	var downloadCertFile string
+	var downloadKeyFile string
+	var jobImage string
+	var jobNamespace string
+	var jobServiceAccount string
+	var jobHostNetwork bool
+	var runBackup string
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&snapshotDir, "snapshot-dir", os.TempDir(),
+		"The directory local snapshots are kept in until they are uploaded, "+
+			"and copied replicas are downloaded to. "+
+			"Use a persistent volume to resume uploads interrupted by a restart.")
+	flag.DurationVar(&uploadGracePeriod, "orphaned-upload-grace-period", 24*time.Hour,
+		"How long a multipart upload no backup tracks is kept before it is aborted.")
+	flag.DurationVar(&catalogSyncInterval, "catalog-sync-interval", time.Minute,
+		"How often backups found in storage locations are synced into the cluster. Zero disables the sync.")
+	flag.DurationVar(&etcdPollInterval, "etcd-poll-interval", time.Minute,
+		"How often the database size and revision of the etcd endpoints of backups are exported as metrics. Zero disables the poll.")
+	flag.IntVar(&etcdPollMaxEndpoints, "etcd-poll-max-endpoints", 20,
+		"The number of etcd endpoints polled at most, bounding the series exported for them.")
+	flag.StringVar(&quotaMaxBytes, "quota-max-bytes", "",
+		"The total size of snapshots every namespace or tenant may store, such as 100Gi. Empty for no limit.")
+	flag.IntVar(&quotaMaxBackups, "quota-max-backups", 0,
+		"The number of backups every namespace or tenant may store. Zero for no limit.")
+	flag.StringVar(&quotaTenantLabel, "quota-tenant-label", "",
+		"The namespace label whose value groups namespaces into tenants sharing the operator-wide quota.")
+	flag.StringVar(&quotaUsageConfigMap, "quota-usage-configmap", "etcdguardian-storage-usage",
+		"The ConfigMap the storage used per namespace or tenant is reported in. Empty disables the report.")
+	flag.StringVar(&quotaUsageNamespace, "quota-usage-namespace", os.Getenv("POD_NAMESPACE"),
+		"The namespace of the storage usage ConfigMap, by default the namespace of the operator.")
+	flag.StringVar(&downloadAddr, "download-bind-address", "",
+		"The address the endpoint serving presigned snapshot download URLs binds to. Empty disables the endpoint.")
+	flag.StringVar(&downloadCertFile, "download-tls-cert-file", "",
+		"The TLS certificate of the download endpoint, required when it is enabled.")
+	flag.StringVar(&downloadKeyFile, "download-tls-key-file", "", "The TLS key of the download endpoint, required when it is enabled.")
+	flag.StringVar(&jobImage, "job-image", "",
+		"The operator image the Jobs of backups in the Job execution mode run. Empty disables the Job execution mode.")
+	flag.StringVar(&jobNamespace, "job-namespace", os.Getenv("POD_NAMESPACE"),
+		"The namespace Jobs of backups are created in, by default the namespace of the operator.")
+	flag.StringVar(&jobServiceAccount, "job-service-account", "",
+		"The service account Jobs of backups run as, which must be allowed to update the status of backups.")
+	flag.BoolVar(&jobHostNetwork, "job-host-network", false,
+		"Run Jobs of backups in the network of their node, to reach etcd members listening on the loopback address only.")
+	flag.StringVar(&runBackup, "run-backup", "",
+		"Take and upload the snapshot of the backup <namespace>/<name> and exit, as the Job of a backup does.")
 
 	opts := zap.Options{
 		Development: true,
@@ -49,6 +113,32 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if runBackup != "" {
+		os.Exit(runBackupJob(runBackup, snapshotDir))
+	}
+
+	if downloadAddr != "" && (downloadCertFile == "" || downloadKeyFile == "") {
+		setupLog.Error(nil, "--download-bind-address requires --download-tls-cert-file and --download-tls-key-file")
+		os.Exit(1)
+	}
+
+	var operatorQuota *etcdguardianv1alpha1.StorageQuota
+	if quotaMaxBytes != "" || quotaMaxBackups > 0 {
+		operatorQuota = &etcdguardianv1alpha1.StorageQuota{TenantLabel: quotaTenantLabel}
+		if quotaMaxBytes != "" {
+			maxBytes, err := resource.ParseQuantity(quotaMaxBytes)
+			if err != nil {
+				setupLog.Error(err, "invalid --quota-max-bytes")
+				os.Exit(1)
+			}
+			operatorQuota.MaxBytes = &maxBytes
+		}
+		if quotaMaxBackups > 0 {
+			maxBackups := int32(quotaMaxBackups)
+			operatorQuota.MaxBackups = &maxBackups
+		}
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
@@ -66,21 +156,116 @@ func main() {
 		os.Exit(1)
 	}
 
+	hookExecutor, err := hooks.NewPodExecutor(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create hook executor")
+		os.Exit(1)
+	}
+
 	// Setup EtcdBackup controller
 	if err = (&controllers.EtcdBackupReconciler{
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		Log:          ctrl.Log.WithName("controllers").WithName("EtcdBackup"),
+		Recorder:     mgr.GetEventRecorderFor("etcdbackup-controller"),
+		SnapshotDir:  snapshotDir,
+		Quota:        operatorQuota,
+		HookExecutor: hookExecutor,
+		JobOptions: backupjob.Options{
+			Namespace:          jobNamespace,
+			Image:              jobImage,
+			ServiceAccountName: jobServiceAccount,
+			Env:                backupjob.ForwardedEnv(),
+			HostNetwork:        jobHostNetwork,
+		},
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "EtcdBackup")
+		os.Exit(1)
+	}
+
+	// Setup EtcdBackupStorageLocation controller
+	if err = (&controllers.EtcdBackupStorageLocationReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
-		Log:    ctrl.Log.WithName("controllers").WithName("EtcdBackup"),
+		Log:    ctrl.Log.WithName("controllers").WithName("EtcdBackupStorageLocation"),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "EtcdBackup")
+		setupLog.Error(err, "unable to create controller", "controller", "EtcdBackupStorageLocation")
+		os.Exit(1)
+	}
+
+	// Setup cleanup of orphaned multipart uploads
+	if err = (&controllers.UploadCleaner{
+		Client:      mgr.GetClient(),
+		Log:         ctrl.Log.WithName("controllers").WithName("UploadCleaner"),
+		Interval:    time.Hour,
+		GracePeriod: uploadGracePeriod,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to set up upload cleanup")
 		os.Exit(1)
 	}
 
+	// Setup sync of backups from storage locations
+	if catalogSyncInterval > 0 {
+		if err = (&controllers.CatalogSyncer{
+			Client:   mgr.GetClient(),
+			Log:      ctrl.Log.WithName("controllers").WithName("CatalogSyncer"),
+			Recorder: mgr.GetEventRecorderFor("catalog-syncer"),
+			Interval: catalogSyncInterval,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to set up catalog sync")
+			os.Exit(1)
+		}
+	}
+
+	// Setup export of the database size and revision of etcd
+	if etcdPollInterval > 0 {
+		if err = (&controllers.EtcdHealthPoller{
+			Client:       mgr.GetClient(),
+			Log:          ctrl.Log.WithName("controllers").WithName("EtcdHealthPoller"),
+			Interval:     etcdPollInterval,
+			MaxEndpoints: etcdPollMaxEndpoints,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to set up etcd health poller")
+			os.Exit(1)
+		}
+	}
+
+	// Setup report of the storage used per namespace or tenant
+	if quotaUsageConfigMap != "" && quotaUsageNamespace != "" {
+		if err = (&controllers.QuotaReporter{
+			Client:    mgr.GetClient(),
+			Log:       ctrl.Log.WithName("controllers").WithName("QuotaReporter"),
+			Interval:  time.Minute,
+			Quota:     operatorQuota,
+			ConfigMap: client.ObjectKey{Namespace: quotaUsageNamespace, Name: quotaUsageConfigMap},
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to set up storage usage report")
+			os.Exit(1)
+		}
+	}
+
+	// Setup the endpoint serving presigned snapshot download URLs
+	if downloadAddr != "" {
+		downloadLog := ctrl.Log.WithName("download")
+		if err = mgr.Add(&download.Server{
+			Client:     mgr.GetClient(),
+			Log:        downloadLog,
+			Addr:       downloadAddr,
+			CertFile:   downloadCertFile,
+			KeyFile:    downloadKeyFile,
+			Replicator: replication.NewReplicator(mgr.GetClient(), downloadLog),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up download endpoint")
+			os.Exit(1)
+		}
+	}
+
 	// Setup EtcdRestore controller
 	if err = (&controllers.EtcdRestoreReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		Log:    ctrl.Log.WithName("controllers").WithName("EtcdRestore"),
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		Log:          ctrl.Log.WithName("controllers").WithName("EtcdRestore"),
+		HookExecutor: hookExecutor,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "EtcdRestore")
 		os.Exit(1)
@@ -113,3 +298,30 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// runBackupJob takes and uploads the snapshot of a backup inside its Job and
+// returns the exit code of the Job. The error is written as the termination
+// message the controller fails the attempt with.
+func runBackupJob(backup, snapshotDir string) int {
+	log := ctrl.Log.WithName("backupjob")
+	namespace, name, ok := strings.Cut(backup, "/")
+	if !ok || namespace == "" || name == "" {
+		log.Error(nil, "invalid --run-backup, expected <namespace>/<name>", "backup", backup)
+		return 1
+	}
+
+	k8sClient, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		log.Error(err, "unable to create client")
+		backupjob.WriteTerminationMessage(err)
+		return 1
+	}
+
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+	if err := backupjob.Run(ctrl.SetupSignalHandler(), k8sClient, log, key, os.Getenv(backupjob.JobNameEnv), snapshotDir); err != nil {
+		log.Error(err, "backup failed", "backup", backup)
+		backupjob.WriteTerminationMessage(err)
+		return 1
+	}
+	return 0
+}
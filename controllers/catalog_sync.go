@@ -0,0 +1,94 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/catalog"
+)
+
+// CatalogSyncer periodically recreates read-only EtcdBackups for the
+// snapshots in every EtcdBackupStorageLocation that have no backup in the
+// cluster, so that a new cluster can restore from an existing bucket
+type CatalogSyncer struct {
+	client.Client
+	Log      logr.Logger
+	Recorder record.EventRecorder
+
+	// Interval between sync runs
+	Interval time.Duration
+}
+
+// +kubebuilder:rbac:groups=etcdguardian.io,resources=etcdbackups,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=etcdguardian.io,resources=etcdbackups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=etcdguardian.io,resources=etcdbackupstoragelocations,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Start runs the sync loop until the context is cancelled
+func (c *CatalogSyncer) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		c.sync(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection makes only the leader create backups
+func (c *CatalogSyncer) NeedLeaderElection() bool {
+	return true
+}
+
+// sync recreates the missing backups of all storage locations
+func (c *CatalogSyncer) sync(ctx context.Context) {
+	locations := &etcdguardianv1alpha1.EtcdBackupStorageLocationList{}
+	if err := c.List(ctx, locations); err != nil {
+		c.Log.Error(err, "Failed to list storage locations")
+		return
+	}
+
+	syncer := catalog.NewSyncer(c.Client, c.Recorder, c.Log)
+	for i := range locations.Items {
+		location := &locations.Items[i]
+		created, err := syncer.Sync(ctx, location)
+		if err != nil {
+			c.Log.Error(err, "Failed to sync backups from storage location", "storageLocation", client.ObjectKeyFromObject(location))
+		}
+		if created > 0 {
+			c.Log.Info("Synced backups from storage location", "storageLocation", client.ObjectKeyFromObject(location), "count", created)
+		}
+	}
+}
+
+// SetupWithManager adds the sync loop to the Manager.
+func (c *CatalogSyncer) SetupWithManager(mgr ctrl.Manager) error {
+	return mgr.Add(c)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// reasonDeadlineExceeded is the reason of backups and restores that ran
+// past their active deadline or the timeout of a phase
+const reasonDeadlineExceeded = "DeadlineExceeded"
+
+// operationDeadline returns when a backup or restore fails for running too
+// long: the earliest of its active deadline, counted from its start, and
+// the timeout of its phase, counted from the start of the phase. The
+// message describes the deadline. A zero time means there is none.
+func operationDeadline(start *metav1.Time, activeDeadlineSeconds *int64, phaseStart *metav1.Time, timeoutName string, timeout *metav1.Duration) (time.Time, string) {
+	var deadline time.Time
+	message := ""
+	if start != nil && activeDeadlineSeconds != nil {
+		active := time.Duration(*activeDeadlineSeconds) * time.Second
+		deadline = start.Add(active)
+		message = fmt.Sprintf("Active deadline of %s exceeded", active)
+	}
+
+	// Operations started before phase start times were recorded count
+	// their phase from their start
+	if phaseStart == nil {
+		phaseStart = start
+	}
+	if phaseStart != nil && timeout != nil {
+		if phaseDeadline := phaseStart.Add(timeout.Duration); deadline.IsZero() || phaseDeadline.Before(deadline) {
+			deadline = phaseDeadline
+			message = fmt.Sprintf("%s timeout of %s exceeded", timeoutName, timeout.Duration)
+		}
+	}
+	return deadline, message
+}
+
+// deadlineContext returns a context cancelled at deadline, or only with its
+// parent for a zero deadline
+func deadlineContext(ctx context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+	if deadline.IsZero() {
+		return context.WithCancel(ctx)
+	}
+	return context.WithDeadline(ctx, deadline)
+}
+
+// requeueByDeadline makes sure a backup or restore is reconciled again once
+// its deadline passes, also when it waits for something it is not notified
+// of
+func requeueByDeadline(result ctrl.Result, deadline time.Time) ctrl.Result {
+	if deadline.IsZero() || (result.Requeue && result.RequeueAfter == 0) {
+		return result
+	}
+	if until := max(time.Until(deadline), time.Second); result.RequeueAfter == 0 || until < result.RequeueAfter {
+		result.RequeueAfter = until
+	}
+	return result
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+)
+
+// ago returns the time a duration before now
+func ago(d time.Duration) *metav1.Time {
+	return &metav1.Time{Time: time.Now().Add(-d)}
+}
+
+// seconds returns an active deadline
+func seconds(s int64) *int64 {
+	return &s
+}
+
+func TestEtcdBackupReconciler_FailsPastDeadline(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		phase     etcdguardianv1alpha1.BackupPhase
+		deadline  *int64
+		timeouts  *etcdguardianv1alpha1.BackupTimeouts
+		message   string
+		condition string
+	}{
+		{
+			name:      "active deadline",
+			phase:     etcdguardianv1alpha1.BackupPhaseSnapshotting,
+			deadline:  seconds(60),
+			message:   "Active deadline of 1m0s exceeded",
+			condition: etcdguardianv1alpha1.BackupConditionUploaded,
+		},
+		{
+			name:      "upload timeout",
+			phase:     etcdguardianv1alpha1.BackupPhaseSnapshotting,
+			timeouts:  &etcdguardianv1alpha1.BackupTimeouts{Upload: &metav1.Duration{Duration: time.Minute}},
+			message:   "Upload timeout of 1m0s exceeded",
+			condition: etcdguardianv1alpha1.BackupConditionUploaded,
+		},
+		{
+			name:      "preflight timeout",
+			phase:     etcdguardianv1alpha1.BackupPhasePending,
+			deadline:  seconds(3600),
+			timeouts:  &etcdguardianv1alpha1.BackupTimeouts{Preflight: &metav1.Duration{Duration: time.Minute}},
+			message:   "Preflight timeout of 1m0s exceeded",
+			condition: etcdguardianv1alpha1.BackupConditionValidated,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = clientgoscheme.AddToScheme(scheme)
+			_ = etcdguardianv1alpha1.AddToScheme(scheme)
+
+			backup := &etcdguardianv1alpha1.EtcdBackup{
+				ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default", Finalizers: []string{backupFinalizer}},
+				Spec: etcdguardianv1alpha1.EtcdBackupSpec{
+					BackupMode:            etcdguardianv1alpha1.BackupModeFull,
+					ActiveDeadlineSeconds: tc.deadline,
+					Timeouts:              tc.timeouts,
+					StorageLocation: &etcdguardianv1alpha1.StorageLocation{
+						Provider: etcdguardianv1alpha1.StorageProviderFilesystem,
+						Bucket:   t.TempDir(),
+					},
+				},
+				Status: etcdguardianv1alpha1.EtcdBackupStatus{
+					Phase:          tc.phase,
+					StartTime:      ago(time.Hour),
+					PhaseStartTime: ago(5 * time.Minute),
+					Attempts:       1,
+				},
+			}
+			k8sClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(backup).
+				WithStatusSubresource(&etcdguardianv1alpha1.EtcdBackup{}).
+				Build()
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &EtcdBackupReconciler{
+				Client:   k8sClient,
+				Log:      logr.Discard(),
+				Scheme:   scheme,
+				Recorder: recorder,
+			}
+
+			key := client.ObjectKeyFromObject(backup)
+			result, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+			if err == nil {
+				t.Error("Expected the failed backup to return an error")
+			}
+			if result.Requeue || result.RequeueAfter != 0 {
+				t.Errorf("Expected the failed backup not to be requeued, got %+v", result)
+			}
+			if err := k8sClient.Get(context.Background(), key, backup); err != nil {
+				t.Fatalf("Failed to get backup: %v", err)
+			}
+
+			if backup.Status.Phase != etcdguardianv1alpha1.BackupPhaseFailed || backup.Status.Reason != reasonDeadlineExceeded || backup.Status.Message != tc.message {
+				t.Errorf("Expected the backup to fail with %s: %q, got %s with %s: %q",
+					reasonDeadlineExceeded, tc.message, backup.Status.Phase, backup.Status.Reason, backup.Status.Message)
+			}
+			if backup.Status.NextRetryTime != nil {
+				t.Errorf("Expected a deadline not to be retried, got a retry at %s", backup.Status.NextRetryTime)
+			}
+			for _, conditionType := range []string{etcdguardianv1alpha1.BackupConditionReady, tc.condition} {
+				condition := meta.FindStatusCondition(backup.Status.Conditions, conditionType)
+				if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != reasonDeadlineExceeded {
+					t.Errorf("Expected condition %s to be False with reason %s, got %+v", conditionType, reasonDeadlineExceeded, condition)
+				}
+			}
+
+			select {
+			case event := <-recorder.Events:
+				if want := "Warning " + reasonDeadlineExceeded + " " + tc.message; event != want {
+					t.Errorf("Expected event %q, got %q", want, event)
+				}
+			default:
+				t.Error("Expected a warning event")
+			}
+		})
+	}
+}
+
+func TestEtcdRestoreReconciler_FailsPastDeadline(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		phase    etcdguardianv1alpha1.RestorePhase
+		deadline *int64
+		timeouts *etcdguardianv1alpha1.RestoreTimeouts
+		message  string
+	}{
+		{
+			name:     "active deadline",
+			phase:    etcdguardianv1alpha1.RestorePhaseRehydrating,
+			deadline: seconds(60),
+			message:  "Active deadline of 1m0s exceeded",
+		},
+		{
+			name:     "quiesce timeout",
+			phase:    etcdguardianv1alpha1.RestorePhaseQuiescing,
+			deadline: seconds(3600),
+			timeouts: &etcdguardianv1alpha1.RestoreTimeouts{Quiesce: &metav1.Duration{Duration: time.Minute}},
+			message:  "Quiesce timeout of 1m0s exceeded",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = clientgoscheme.AddToScheme(scheme)
+			_ = etcdguardianv1alpha1.AddToScheme(scheme)
+
+			restore := &etcdguardianv1alpha1.EtcdRestore{
+				ObjectMeta: metav1.ObjectMeta{Name: "recover", Namespace: "default"},
+				Spec: etcdguardianv1alpha1.EtcdRestoreSpec{
+					BackupName:            "nightly",
+					RestoreMode:           etcdguardianv1alpha1.RestoreModeFull,
+					EtcdCluster:           etcdguardianv1alpha1.EtcdClusterConfig{Endpoints: []string{"https://etcd-0:2379"}, DataDir: "/var/lib/etcd"},
+					ActiveDeadlineSeconds: tc.deadline,
+					Timeouts:              tc.timeouts,
+				},
+				Status: etcdguardianv1alpha1.EtcdRestoreStatus{
+					Phase:          tc.phase,
+					StartTime:      ago(time.Hour),
+					PhaseStartTime: ago(5 * time.Minute),
+				},
+			}
+			k8sClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(restore).
+				WithStatusSubresource(&etcdguardianv1alpha1.EtcdRestore{}).
+				Build()
+			reconciler := &EtcdRestoreReconciler{
+				Client:       k8sClient,
+				Log:          logr.Discard(),
+				Scheme:       scheme,
+				HookExecutor: &recordingExecutor{},
+			}
+
+			key := client.ObjectKeyFromObject(restore)
+			result, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+			if err == nil || !strings.Contains(err.Error(), tc.message) {
+				t.Errorf("Expected the failed restore to return %q, got %v", tc.message, err)
+			}
+			if result.Requeue || result.RequeueAfter != 0 {
+				t.Errorf("Expected the failed restore not to be requeued, got %+v", result)
+			}
+			if err := k8sClient.Get(context.Background(), key, restore); err != nil {
+				t.Fatalf("Failed to get restore: %v", err)
+			}
+
+			if restore.Status.Phase != etcdguardianv1alpha1.RestorePhaseFailed || restore.Status.Reason != reasonDeadlineExceeded || restore.Status.Message != tc.message {
+				t.Errorf("Expected the restore to fail with %s: %q, got %s with %s: %q",
+					reasonDeadlineExceeded, tc.message, restore.Status.Phase, restore.Status.Reason, restore.Status.Message)
+			}
+			if restore.Status.CompletionTime == nil {
+				t.Error("Expected the failed restore to record its completion time")
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/catalog"
+	"github.com/etcdguardian/etcdguardian/pkg/metrics"
+	"github.com/etcdguardian/etcdguardian/pkg/preflight"
+)
+
+// etcdPollTimeout bounds the poll of one etcd endpoint
+const etcdPollTimeout = 10 * time.Second
+
+// EtcdHealthPoller periodically exports the database size and revision of
+// the etcd endpoints backups are taken from. Each endpoint is polled with
+// the certificates of the newest backup naming it.
+type EtcdHealthPoller struct {
+	client.Client
+	Log logr.Logger
+
+	// Interval between polls
+	Interval time.Duration
+
+	// MaxEndpoints bounds the number of endpoints polled, and so the
+	// number of series exported
+	MaxEndpoints int
+
+	// exported are the endpoints the last poll exported series for
+	exported map[string]bool
+}
+
+// etcdTarget is an endpoint to poll and the backup whose certificates it
+// is polled with
+type etcdTarget struct {
+	endpoint string
+	backup   *etcdguardianv1alpha1.EtcdBackup
+}
+
+// +kubebuilder:rbac:groups=etcdguardian.io,resources=etcdbackups,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Start runs the poll loop until the context is cancelled
+func (p *EtcdHealthPoller) Start(ctx context.Context) error {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.poll(ctx); err != nil {
+			p.Log.Error(err, "Failed to poll etcd endpoints")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection makes only the leader export the etcd metrics
+func (p *EtcdHealthPoller) NeedLeaderElection() bool {
+	return true
+}
+
+// poll exports the metrics of every endpoint, dropping the series of
+// endpoints that are no longer polled or failed to answer
+func (p *EtcdHealthPoller) poll(ctx context.Context) error {
+	targets, err := p.targets(ctx)
+	if err != nil {
+		return err
+	}
+
+	exported := map[string]bool{}
+	for _, target := range targets {
+		if err := p.pollEndpoint(ctx, target); err != nil {
+			p.Log.Error(err, "Failed to poll etcd endpoint", "endpoint", target.endpoint)
+			continue
+		}
+		exported[target.endpoint] = true
+	}
+	for endpoint := range p.exported {
+		if !exported[endpoint] {
+			metrics.DeleteEtcdEndpoint(endpoint)
+		}
+	}
+	p.exported = exported
+	return nil
+}
+
+// targets returns the endpoints named by backups, in order, each with the
+// newest backup naming it
+func (p *EtcdHealthPoller) targets(ctx context.Context) ([]etcdTarget, error) {
+	backups := &etcdguardianv1alpha1.EtcdBackupList{}
+	if err := p.List(ctx, backups); err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	newest := map[string]*etcdguardianv1alpha1.EtcdBackup{}
+	for i := range backups.Items {
+		backup := &backups.Items[i]
+		// Synced backups may name the endpoints of other clusters
+		if catalog.IsSynced(backup) {
+			continue
+		}
+		for _, endpoint := range backup.Spec.EtcdEndpoints {
+			if current, ok := newest[endpoint]; !ok || current.CreationTimestamp.Before(&backup.CreationTimestamp) {
+				newest[endpoint] = backup
+			}
+		}
+	}
+
+	targets := make([]etcdTarget, 0, len(newest))
+	for endpoint, backup := range newest {
+		targets = append(targets, etcdTarget{endpoint: endpoint, backup: backup})
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].endpoint < targets[j].endpoint })
+	if p.MaxEndpoints > 0 && len(targets) > p.MaxEndpoints {
+		p.Log.Info("Too many etcd endpoints, polling the first ones only", "endpoints", len(targets), "maxEndpoints", p.MaxEndpoints)
+		targets = targets[:p.MaxEndpoints]
+	}
+	return targets, nil
+}
+
+// pollEndpoint exports the database size and revision of one endpoint
+func (p *EtcdHealthPoller) pollEndpoint(ctx context.Context, target etcdTarget) error {
+	ctx, cancel := context.WithTimeout(ctx, etcdPollTimeout)
+	defer cancel()
+
+	tlsConfig, err := preflight.EtcdTLSConfig(ctx, p.Client, target.backup.Namespace, target.backup.Spec.EtcdCertificates)
+	if err != nil {
+		return err
+	}
+	dbSize, revision, err := preflight.MemberStatus(ctx, target.endpoint, tlsConfig)
+	if err != nil {
+		return err
+	}
+	metrics.SetEtcdDBSize(target.endpoint, dbSize)
+	metrics.SetEtcdRevision(target.endpoint, revision)
+	return nil
+}
+
+// SetupWithManager adds the poll loop to the Manager.
+func (p *EtcdHealthPoller) SetupWithManager(mgr ctrl.Manager) error {
+	return mgr.Add(p)
+}
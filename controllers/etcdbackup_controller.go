@@ -19,19 +19,35 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"os"
+	"slices"
 	"time"
 
 	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/backupjob"
+	"github.com/etcdguardian/etcdguardian/pkg/catalog"
+	"github.com/etcdguardian/etcdguardian/pkg/hooks"
+	"github.com/etcdguardian/etcdguardian/pkg/metrics"
+	"github.com/etcdguardian/etcdguardian/pkg/preflight"
+	"github.com/etcdguardian/etcdguardian/pkg/quota"
+	"github.com/etcdguardian/etcdguardian/pkg/replication"
 	"github.com/etcdguardian/etcdguardian/pkg/snapshot"
 	"github.com/etcdguardian/etcdguardian/pkg/storage"
+	"github.com/etcdguardian/etcdguardian/pkg/tiering"
 	"github.com/etcdguardian/etcdguardian/pkg/validation"
 )
 
@@ -39,18 +55,94 @@ const (
 	backupFinalizer = "etcdguardian.io/finalizer"
 )
 
+// Defaults of the retries of backups failing with transient errors
+const (
+	defaultBackoffLimit   = 3
+	defaultInitialBackoff = 10 * time.Second
+	defaultMaxBackoff     = 5 * time.Minute
+)
+
+// Machine-readable reasons of failed backups. Storage failures report the
+// reason of their error class instead, such as StorageAuthFailed, and
+// failed preflight checks the reason of the check, such as EtcdUnreachable.
+const (
+	reasonInvalidConfig              = "InvalidConfig"
+	reasonStorageLocationNotFound    = "StorageLocationNotFound"
+	reasonStorageLocationUnavailable = "StorageLocationUnavailable"
+	reasonQuotaExceeded              = "QuotaExceeded"
+	reasonSnapshotFailed             = "SnapshotFailed"
+	reasonReplicationFailed          = "ReplicationFailed"
+	reasonValidationFailed           = "ValidationFailed"
+	reasonHookFailed                 = "HookFailed"
+	reasonLegalHoldFailed            = "LegalHoldFailed"
+	reasonTieringFailed              = "TieringFailed"
+	reasonJobFailed                  = "JobFailed"
+	reasonTombstoneFailed            = "TombstoneFailed"
+)
+
+// Reasons of the events and conditions of backups making progress
+const (
+	reasonStarted          = "Started"
+	reasonInProgress       = "InProgress"
+	reasonChecksPassed     = "ChecksPassed"
+	reasonPrepared         = "Prepared"
+	reasonSnapshotTaken    = "SnapshotTaken"
+	reasonSnapshotLost     = "SnapshotLost"
+	reasonUploaded         = "Uploaded"
+	reasonSnapshotVerified = "SnapshotVerified"
+	reasonSnapshotValid    = "SnapshotValid"
+	reasonVeleroSkipped    = "VeleroSkipped"
+	reasonNotImplemented   = "NotImplemented"
+	reasonCompleted        = "Completed"
+	reasonRetrying         = "Retrying"
+	reasonJobCreated       = "JobCreated"
+)
+
+// retryableReasons are the reasons of failures that are likely transient,
+// which fail backups only once their backoff limit is exhausted
+var retryableReasons = map[string]bool{
+	reasonSnapshotFailed:                 true,
+	reasonReplicationFailed:              true,
+	reasonJobFailed:                      true,
+	storage.ErrorClassRetryable.Reason(): true,
+	preflight.ReasonEtcdUnreachable:      true,
+	preflight.ReasonStorageAccessFailed:  true,
+}
+
 // EtcdBackupReconciler reconciles a EtcdBackup object
 type EtcdBackupReconciler struct {
 	client.Client
 	Log    logr.Logger
 	Scheme *runtime.Scheme
+
+	// Recorder records the events of phase transitions and failures
+	Recorder record.EventRecorder
+
+	// SnapshotDir is the directory local snapshots are kept in until they
+	// are uploaded
+	SnapshotDir string
+
+	// Quota is the operator-wide storage quota of every namespace or
+	// tenant, nil for none
+	Quota *etcdguardianv1alpha1.StorageQuota
+
+	// HookExecutor runs the commands of exec hooks in pods
+	HookExecutor hooks.Executor
+
+	// JobOptions configure the Jobs of backups in the Job execution mode,
+	// which is disabled without an image
+	JobOptions backupjob.Options
 }
 
 // +kubebuilder:rbac:groups=etcdguardian.io,resources=etcdbackups,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=etcdguardian.io,resources=etcdbackups/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=etcdguardian.io,resources=etcdbackups/finalizers,verbs=update
+// +kubebuilder:rbac:groups=etcdguardian.io,resources=etcdbackupstoragelocations,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop
 func (r *EtcdBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -81,24 +173,72 @@ func (r *EtcdBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return r.handleDeletion(ctx, backup)
 	}
 
+	// Backups synced from storage locations are read-only, apart from
+	// their deletion
+	if catalog.IsSynced(backup) {
+		return ctrl.Result{}, nil
+	}
+
+	// Copy completed backups to replicas deferred by the PrimaryAsync policy
+	if backup.Status.Phase == etcdguardianv1alpha1.BackupPhaseCompleted && replication.HasPendingReplicas(backup) {
+		return r.replicateAsync(ctx, backup)
+	}
+
 	// Check if backup is already completed or failed
 	if backup.Status.Phase == etcdguardianv1alpha1.BackupPhaseCompleted ||
 		backup.Status.Phase == etcdguardianv1alpha1.BackupPhaseFailed {
+		// Place or release legal holds toggled on the stored snapshots
+		if replication.LegalHoldPending(backup) {
+			return r.syncLegalHold(ctx, backup)
+		}
+		// Move aging snapshots to colder storage tiers
+		if tiering.Pending(backup, time.Now()) {
+			return r.applyTiering(ctx, backup)
+		}
+		if _, next := tiering.DueTier(backup, time.Now()); !next.IsZero() {
+			return ctrl.Result{RequeueAfter: time.Until(next)}, nil
+		}
 		return ctrl.Result{}, nil
 	}
 
 	// Initialize status if it's pending
 	if backup.Status.Phase == "" {
-		backup.Status.Phase = etcdguardianv1alpha1.BackupPhasePending
 		backup.Status.StartTime = &metav1.Time{Time: time.Now()}
-		if err := r.Status().Update(ctx, backup); err != nil {
-			log.Error(err, "Failed to update backup status")
-			return ctrl.Result{}, err
+		backup.Status.Attempts = 1
+		return r.updatePhase(ctx, backup, etcdguardianv1alpha1.BackupPhasePending, reasonStarted, "Backup started")
+	}
+
+	// Fail backups past their active deadline or the timeout of their
+	// phase
+	deadline, exceeded := backupDeadline(backup)
+	if !deadline.IsZero() && !time.Now().Before(deadline) {
+		return r.updateStatusFailed(ctx, backup, reasonDeadlineExceeded, exceeded)
+	}
+
+	// Wait out the backoff of a retried backup, which status updates
+	// would otherwise cut short
+	if next := backup.Status.NextRetryTime; next != nil {
+		if wait := time.Until(next.Time); wait > 0 {
+			return requeueByDeadline(ctrl.Result{RequeueAfter: wait}, deadline), nil
 		}
+	}
+
+	// Cancel the work of the phase at the deadline, releasing the
+	// connections and goroutines of hung snapshot streams or uploads
+	phaseCtx, cancel := deadlineContext(ctx, deadline)
+	defer cancel()
+	result, err := r.runPhase(phaseCtx, backup)
+	if phaseCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		// The status updates of the phase may have failed with its
+		// context; fail the backup with a live one
+		log.Info("Backup ran past its deadline", "deadline", deadline)
 		return ctrl.Result{Requeue: true}, nil
 	}
+	return requeueByDeadline(result, deadline), err
+}
 
-	// Execute backup phases
+// runPhase runs the step of the phase of a backup
+func (r *EtcdBackupReconciler) runPhase(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup) (ctrl.Result, error) {
 	switch backup.Status.Phase {
 	case etcdguardianv1alpha1.BackupPhasePending:
 		return r.validateConfig(ctx, backup)
@@ -119,35 +259,181 @@ func (r *EtcdBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	return ctrl.Result{}, nil
 }
 
+// backupDeadline returns when a backup fails for running too long, zero for
+// never, and the message to fail it with
+func backupDeadline(backup *etcdguardianv1alpha1.EtcdBackup) (time.Time, string) {
+	var name string
+	var timeout *metav1.Duration
+	if timeouts := backup.Spec.Timeouts; timeouts != nil {
+		switch backup.Status.Phase {
+		case etcdguardianv1alpha1.BackupPhasePending:
+			name, timeout = "Preflight", timeouts.Preflight
+		case etcdguardianv1alpha1.BackupPhasePreparing:
+			name, timeout = "Snapshot", timeouts.Snapshot
+		case etcdguardianv1alpha1.BackupPhaseSnapshotting:
+			name, timeout = "Upload", timeouts.Upload
+		case etcdguardianv1alpha1.BackupPhaseUploading:
+			name, timeout = "Validation", timeouts.Validation
+		}
+	}
+	return operationDeadline(backup.Status.StartTime, backup.Spec.ActiveDeadlineSeconds, backup.Status.PhaseStartTime, name, timeout)
+}
+
 // validateConfig validates the backup configuration
 func (r *EtcdBackupReconciler) validateConfig(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup) (ctrl.Result, error) {
 	log := r.Log.WithValues("etcdbackup", client.ObjectKeyFromObject(backup))
 	log.Info("Validating backup configuration")
 
 	// Validate storage location
-	if backup.Spec.StorageLocation.Bucket == "" {
-		return r.updateStatusFailed(ctx, backup, "Storage bucket is required")
+	if err := replication.ValidateStorageLocation(backup); err != nil {
+		return r.updateStatusFailed(ctx, backup, reasonInvalidConfig, fmt.Sprintf("Invalid storage location: %v", err))
 	}
-
-	// Validate credentials secret exists
-	secret := &client.ObjectKey{
-		Name:      backup.Spec.StorageLocation.CredentialsSecret,
-		Namespace: backup.Namespace,
+	if err := replication.ValidateReplicas(backup); err != nil {
+		return r.updateStatusFailed(ctx, backup, reasonInvalidConfig, fmt.Sprintf("Invalid replicas: %v", err))
 	}
-	if err := r.Get(ctx, *secret, &client.Object{}); err != nil {
+	targets, err := replication.Targets(ctx, r.Client, backup)
+	if err != nil {
 		if errors.IsNotFound(err) {
-			return r.updateStatusFailed(ctx, backup, fmt.Sprintf("Credentials secret %s not found", backup.Spec.StorageLocation.CredentialsSecret))
+			return r.updateStatusFailed(ctx, backup, reasonStorageLocationNotFound, err.Error())
 		}
 		return ctrl.Result{}, err
 	}
+	if err := replication.ValidateImmutability(backup, targets); err != nil {
+		return r.updateStatusFailed(ctx, backup, reasonInvalidConfig, fmt.Sprintf("Invalid immutability settings: %v", err))
+	}
+	if err := replication.ValidateServerSideEncryption(targets); err != nil {
+		return r.updateStatusFailed(ctx, backup, reasonInvalidConfig, fmt.Sprintf("Invalid server-side encryption settings: %v", err))
+	}
+	if err := tiering.Validate(backup, targets); err != nil {
+		return r.updateStatusFailed(ctx, backup, reasonInvalidConfig, fmt.Sprintf("Invalid tiering rules: %v", err))
+	}
+	if backup.Spec.ExecutionMode == etcdguardianv1alpha1.ExecutionModeJob {
+		if r.JobOptions.Image == "" {
+			return r.updateStatusFailed(ctx, backup, reasonInvalidConfig, "Job execution mode is disabled: the operator has no Job image configured")
+		}
+		if err := backupjob.ValidateTargets(targets); err != nil {
+			return r.updateStatusFailed(ctx, backup, reasonInvalidConfig, fmt.Sprintf("Invalid destinations: %v", err))
+		}
+	}
 
-	// Move to next phase
-	backup.Status.Phase = etcdguardianv1alpha1.BackupPhaseValidating
-	if err := r.Status().Update(ctx, backup); err != nil {
+	// Wait for or fail on referenced storage locations that failed their
+	// last access check. Locations not checked yet are used right away.
+	for _, target := range targets {
+		ref := target.LocationRef
+		if ref == nil || !meta.IsStatusConditionFalse(ref.Status.Conditions, etcdguardianv1alpha1.StorageLocationConditionAvailable) {
+			continue
+		}
+		message := fmt.Sprintf("Storage location %s of destination %s is unavailable: %s", ref.Name, target.Name, ref.Status.Message)
+		if ref.Spec.UnavailableAction == etcdguardianv1alpha1.UnavailableActionFail {
+			return r.updateStatusFailed(ctx, backup, reasonStorageLocationUnavailable, message)
+		}
+
+		log.Info("Waiting for storage location to become available", "storageLocation", ref.Name)
+		backup.Status.Reason = reasonStorageLocationUnavailable
+		backup.Status.Message = message
+		if err := r.Status().Update(ctx, backup); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: validationFrequency(ref)}, nil
+	}
+
+	// Check credentials, etcd, bucket access and scratch space
+	dbSize, err := preflight.NewChecker(r.Client, r.SnapshotDir).Run(ctx, backup, targets)
+	if err != nil {
+		if failed, ok := err.(*preflight.Error); ok {
+			return r.updateStatusFailed(ctx, backup, failed.Reason, fmt.Sprintf("Preflight check failed: %v", failed.Err))
+		}
 		return ctrl.Result{}, err
 	}
+	backup.Status.ExpectedSnapshotSize = dbSize
 
-	return ctrl.Result{Requeue: true}, nil
+	// Check storage quotas, with the expected size of the snapshot, before
+	// taking it
+	exceeded, err := r.checkQuotas(ctx, backup, targets)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if exceeded != "" {
+		return r.updateStatusFailed(ctx, backup, reasonQuotaExceeded, exceeded)
+	}
+
+	// Move to next phase
+	backup.Status.Reason = ""
+	backup.Status.Message = ""
+	message := "Configuration and preflight checks passed"
+	setBackupCondition(backup, etcdguardianv1alpha1.BackupConditionValidated, metav1.ConditionTrue, reasonChecksPassed, message)
+	return r.updatePhase(ctx, backup, etcdguardianv1alpha1.BackupPhaseValidating, reasonChecksPassed, message)
+}
+
+// checkQuotas checks the operator-wide quota and the quotas of the storage
+// locations in the buckets a backup is stored into against the storage its
+// namespace or tenant uses, including the backups in progress and the
+// expected size of the backup. Location quotas apply to every backup
+// storing into their bucket, whether it references the location or not. It
+// returns the message of the first quota exceeded, empty when the backup
+// fits.
+func (r *EtcdBackupReconciler) checkQuotas(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup, targets []replication.Target) (string, error) {
+	type check struct {
+		scope   string
+		quota   *etcdguardianv1alpha1.StorageQuota
+		include func(replication.Target) bool
+	}
+
+	checks := []check{}
+	if r.Quota != nil {
+		checks = append(checks, check{scope: "Operator-wide", quota: r.Quota})
+	}
+	locations := &etcdguardianv1alpha1.EtcdBackupStorageLocationList{}
+	if err := r.List(ctx, locations); err != nil {
+		return "", fmt.Errorf("failed to list storage locations: %w", err)
+	}
+	for i := range locations.Items {
+		ref := &locations.Items[i]
+		if ref.Spec.Quota == nil {
+			continue
+		}
+		location := ref.Spec.StorageLocation
+		include := func(target replication.Target) bool {
+			return quota.SameBucket(target.Location, location)
+		}
+		if !slices.ContainsFunc(targets, include) {
+			continue
+		}
+		checks = append(checks, check{
+			scope:   fmt.Sprintf("Storage location %s/%s", ref.Namespace, ref.Name),
+			quota:   ref.Spec.Quota,
+			include: include,
+		})
+	}
+	if len(checks) == 0 {
+		return "", nil
+	}
+
+	backups := &etcdguardianv1alpha1.EtcdBackupList{}
+	if err := r.List(ctx, backups); err != nil {
+		return "", fmt.Errorf("failed to list backups: %w", err)
+	}
+	others := slices.DeleteFunc(backups.Items, func(other etcdguardianv1alpha1.EtcdBackup) bool {
+		return other.UID == backup.UID
+	})
+	for _, check := range checks {
+		grouper, err := quota.NewGrouper(ctx, r.Client, check.quota)
+		if err != nil {
+			return "", err
+		}
+		usage := quota.Compute(ctx, r.Client, grouper, others, check.include)
+		request := quota.Usage{Backups: 1}
+		for _, target := range targets {
+			if check.include == nil || check.include(target) {
+				request.Bytes += backup.Status.ExpectedSnapshotSize
+			}
+		}
+		group := grouper.Group(backup.Namespace)
+		if err := quota.Check(check.quota, group, usage[group], request); err != nil {
+			return fmt.Sprintf("%s %v", check.scope, err), nil
+		}
+	}
+	return "", nil
 }
 
 // prepareBackup prepares the backup environment
@@ -155,23 +441,29 @@ func (r *EtcdBackupReconciler) prepareBackup(ctx context.Context, backup *etcdgu
 	log := r.Log.WithValues("etcdbackup", client.ObjectKeyFromObject(backup))
 	log.Info("Preparing backup")
 
-	// TODO: Execute pre-backup hooks if defined
-
-	backup.Status.Phase = etcdguardianv1alpha1.BackupPhasePreparing
-	if err := r.Status().Update(ctx, backup); err != nil {
-		return ctrl.Result{}, err
+	// Run pre-backup hooks, such as pausing the workloads the snapshot
+	// should be consistent with
+	if backup.Spec.Hooks != nil && len(backup.Spec.Hooks.PreBackup) > 0 {
+		results, err := hooks.NewRunner(r.Client, r.HookExecutor, log).Run(ctx, backup, etcdguardianv1alpha1.HookStagePreBackup, backup.Spec.Hooks.PreBackup)
+		backup.Status.HookResults = append(backup.Status.HookResults, results...)
+		if err != nil {
+			return r.updateStatusFailed(ctx, backup, reasonHookFailed, err.Error())
+		}
 	}
 
-	return ctrl.Result{Requeue: true}, nil
+	return r.updatePhase(ctx, backup, etcdguardianv1alpha1.BackupPhasePreparing, reasonPrepared, "Backup prepared")
 }
 
 // takeSnapshot performs the etcd snapshot
 func (r *EtcdBackupReconciler) takeSnapshot(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup) (ctrl.Result, error) {
 	log := r.Log.WithValues("etcdbackup", client.ObjectKeyFromObject(backup))
+	if backup.Spec.ExecutionMode == etcdguardianv1alpha1.ExecutionModeJob {
+		return r.runSnapshotJob(ctx, backup)
+	}
 	log.Info("Taking etcd snapshot")
 
 	// Create snapshot engine
-	snapshotEngine := snapshot.NewSnapshotEngine(log)
+	snapshotEngine := snapshot.NewSnapshotEngine(log).WithDir(r.SnapshotDir)
 
 	// Perform snapshot based on backup mode
 	var snapshotPath string
@@ -186,45 +478,154 @@ func (r *EtcdBackupReconciler) takeSnapshot(ctx context.Context, backup *etcdgua
 	}
 
 	if err != nil {
-		return r.updateStatusFailed(ctx, backup, fmt.Sprintf("Failed to take snapshot: %v", err))
+		return r.updateStatusFailed(ctx, backup, reasonSnapshotFailed, fmt.Sprintf("Failed to take snapshot: %v", err))
+	}
+
+	// Resume what the pre-backup hooks paused as soon as the snapshot is
+	// taken, rather than for the whole upload
+	if err := r.runPostBackupHooks(ctx, backup); err != nil {
+		return r.updateStatusFailed(ctx, backup, reasonHookFailed, err.Error())
 	}
 
 	// Update status with snapshot info
 	backup.Status.SnapshotSize = snapshotSize
 	backup.Status.EtcdRevision = etcdRevision
 	backup.Status.SnapshotLocation = snapshotPath
-	backup.Status.Phase = etcdguardianv1alpha1.BackupPhaseSnapshotting
-	if err := r.Status().Update(ctx, backup); err != nil {
-		return ctrl.Result{}, err
+	return r.updatePhase(ctx, backup, etcdguardianv1alpha1.BackupPhaseSnapshotting, reasonSnapshotTaken,
+		fmt.Sprintf("Snapshot of %d bytes taken at etcd revision %d", snapshotSize, etcdRevision))
+}
+
+// runSnapshotJob takes and uploads the snapshot of a backup in the Job
+// execution mode in a Job on a control-plane node, one per attempt. The Job
+// records the snapshot and its destinations in the backup status; once it
+// completed, the backup moves on to validating the stored snapshot.
+func (r *EtcdBackupReconciler) runSnapshotJob(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup) (ctrl.Result, error) {
+	log := r.Log.WithValues("etcdbackup", client.ObjectKeyFromObject(backup))
+
+	// Record the Job before creating it, so that the Job finds itself in
+	// the status. Every attempt takes a new snapshot, which replaces what
+	// earlier attempts stored.
+	ref := etcdguardianv1alpha1.JobReference{Namespace: r.JobOptions.Namespace, Name: backupjob.Name(backup)}
+	if backup.Status.Job == nil || *backup.Status.Job != ref {
+		backup.Status.Job = &ref
+		backup.Status.Replicas = nil
+		backup.Status.SnapshotLocation = ""
+		backup.Status.Message = fmt.Sprintf("Taking snapshot in Job %s/%s", ref.Namespace, ref.Name)
+		if err := r.Status().Update(ctx, backup); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
 	}
 
-	return ctrl.Result{Requeue: true}, nil
+	job := &batchv1.Job{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, job); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		log.Info("Creating snapshot Job", "job", ref.Name, "namespace", ref.Namespace)
+		if err := r.Create(ctx, backupjob.NewJob(backup, r.JobOptions)); err != nil && !errors.IsAlreadyExists(err) {
+			return ctrl.Result{}, err
+		}
+		r.Recorder.Eventf(backup, corev1.EventTypeNormal, reasonJobCreated, "Created Job %s/%s", ref.Namespace, ref.Name)
+		return ctrl.Result{}, nil
+	}
+
+	// Running Jobs wake the backup up when they finish
+	finished, succeeded := backupjob.Finished(job)
+	if !finished {
+		return ctrl.Result{}, nil
+	}
+	if !succeeded {
+		// Failed destinations fail the backup with the reason of their
+		// error class, as uploads by the operator do
+		reason := replication.FailureReason(backup)
+		if reason == "" {
+			reason = reasonJobFailed
+		}
+		message := backupjob.FailureMessage(ctx, r.Client, job)
+		return r.updateStatusFailed(ctx, backup, reason, fmt.Sprintf("Job %s/%s failed: %s", ref.Namespace, ref.Name, message))
+	}
+
+	// The Job records its results before it completes, but the backup may
+	// be observed before them
+	if backup.Status.SnapshotLocation == "" {
+		return ctrl.Result{RequeueAfter: time.Second}, nil
+	}
+
+	if err := r.runPostBackupHooks(ctx, backup); err != nil {
+		return r.updateStatusFailed(ctx, backup, reasonHookFailed, err.Error())
+	}
+
+	message := fmt.Sprintf("Snapshot of %d bytes taken at etcd revision %d and uploaded to %s by Job %s/%s",
+		backup.Status.SnapshotSize, backup.Status.EtcdRevision, backup.Status.SnapshotLocation, ref.Namespace, ref.Name)
+	backup.Status.Message = ""
+	setBackupCondition(backup, etcdguardianv1alpha1.BackupConditionUploaded, metav1.ConditionTrue, reasonUploaded, message)
+	result, err := r.updatePhase(ctx, backup, etcdguardianv1alpha1.BackupPhaseUploading, reasonUploaded, message)
+	if err == nil {
+		r.deleteJob(ctx, backup)
+	}
+	return result, err
 }
 
-// uploadSnapshot uploads the snapshot to storage
+// deleteJob deletes the Job of a backup in the Job execution mode and its
+// pod. Jobs left behind are removed by their TTL.
+func (r *EtcdBackupReconciler) deleteJob(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup) {
+	ref := backup.Status.Job
+	if ref == nil {
+		return
+	}
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: ref.Namespace, Name: ref.Name}}
+	if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+		r.Log.Error(err, "Failed to delete snapshot Job", "etcdbackup", client.ObjectKeyFromObject(backup), "job", ref.Name)
+	}
+}
+
+// newReplicator returns a replicator that does not block the reconcile on
+// long storage retries: only short operations are retried in place, and
+// transfers failing with a transient error are retried by requeueing
+func newReplicator(k8sClient client.Client, log logr.Logger) *replication.Replicator {
+	return replication.NewReplicator(k8sClient, log).WithRetryPolicy(storage.ReconcileRetryPolicy)
+}
+
+// uploadSnapshot uploads the snapshot and its manifest to the storage
+// location and its replicas
 func (r *EtcdBackupReconciler) uploadSnapshot(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup) (ctrl.Result, error) {
 	log := r.Log.WithValues("etcdbackup", client.ObjectKeyFromObject(backup))
 	log.Info("Uploading snapshot to storage")
 
-	// Create storage backend
-	storageBackend, err := storage.NewStorage(backup.Spec.StorageLocation.Provider, backup.Spec.StorageLocation, r.Client, backup.Namespace)
-	if err != nil {
-		return r.updateStatusFailed(ctx, backup, fmt.Sprintf("Failed to create storage backend: %v", err))
+	// The local snapshot is lost when the operator restarts without a
+	// persistent snapshot directory. Take it again; upload sessions of the
+	// lost snapshot are replaced when the new one is uploaded.
+	if _, err := os.Stat(backup.Status.SnapshotLocation); os.IsNotExist(err) {
+		log.Info("Local snapshot is gone, taking it again", "path", backup.Status.SnapshotLocation)
+		backup.Status.Replicas = nil
+		return r.updatePhase(ctx, backup, etcdguardianv1alpha1.BackupPhasePreparing, reasonSnapshotLost, "Local snapshot is gone, taking it again")
+	}
+
+	// A transfer failing with a transient error is retried by the next
+	// attempt of the backup once its backoff has passed; other errors fail
+	// the backup with the reason of their class
+	replicator := newReplicator(r.Client, log)
+	if err := replicator.Replicate(ctx, backup, backup.Status.SnapshotLocation); err != nil {
+		return r.updateStatusFailed(ctx, backup, reasonReplicationFailed, fmt.Sprintf("Failed to upload snapshot: %v", err))
 	}
 
-	// Upload snapshot
-	location, err := storageBackend.Upload(ctx, backup.Status.SnapshotLocation, backup)
+	complete, err := replication.Evaluate(backup)
 	if err != nil {
-		return r.updateStatusFailed(ctx, backup, fmt.Sprintf("Failed to upload snapshot: %v", err))
+		return r.updateStatusFailed(ctx, backup, replicationFailureReason(backup), fmt.Sprintf("Failed to upload snapshot: %v", err))
+	}
+	if !complete {
+		return r.updateStatusFailed(ctx, backup, reasonReplicationFailed, "Failed to upload snapshot: replication policy not met")
 	}
 
-	backup.Status.SnapshotLocation = location
-	backup.Status.Phase = etcdguardianv1alpha1.BackupPhaseUploading
-	if err := r.Status().Update(ctx, backup); err != nil {
-		return ctrl.Result{}, err
+	if err := os.Remove(backup.Status.SnapshotLocation); err != nil {
+		log.Error(err, "Failed to remove local snapshot", "path", backup.Status.SnapshotLocation)
 	}
 
-	return ctrl.Result{Requeue: true}, nil
+	backup.Status.SnapshotLocation = replication.SnapshotLocation(backup)
+	message := fmt.Sprintf("Snapshot uploaded to %s", backup.Status.SnapshotLocation)
+	setBackupCondition(backup, etcdguardianv1alpha1.BackupConditionUploaded, metav1.ConditionTrue, reasonUploaded, message)
+	return r.updatePhase(ctx, backup, etcdguardianv1alpha1.BackupPhaseUploading, reasonUploaded, message)
 }
 
 // validateSnapshot validates the uploaded snapshot
@@ -232,11 +633,20 @@ func (r *EtcdBackupReconciler) validateSnapshot(ctx context.Context, backup *etc
 	log := r.Log.WithValues("etcdbackup", client.ObjectKeyFromObject(backup))
 	log.Info("Validating snapshot")
 
+	// A bucket policy or default that overrides the requested encryption
+	// must not leave snapshots stored unencrypted
+	replicator := newReplicator(r.Client, log)
+	if err := replicator.VerifyServerSideEncryption(ctx, backup); err != nil {
+		return r.updateStatusFailed(ctx, backup, replicationFailureReason(backup), err.Error())
+	}
+
+	message := "Stored snapshot verified"
 	if backup.Spec.Validation != nil && backup.Spec.Validation.Enabled {
 		validator := validation.NewValidator(log)
 		result, err := validator.ValidateSnapshot(ctx, backup.Status.SnapshotLocation)
 		if err != nil {
-			return r.updateStatusFailed(ctx, backup, fmt.Sprintf("Failed to validate snapshot: %v", err))
+			metrics.IncValidationFailures("ValidationError")
+			return r.updateStatusFailed(ctx, backup, reasonValidationFailed, fmt.Sprintf("Failed to validate snapshot: %v", err))
 		}
 
 		backup.Status.ValidationResult = &etcdguardianv1alpha1.ValidationResult{
@@ -246,34 +656,30 @@ func (r *EtcdBackupReconciler) validateSnapshot(ctx context.Context, backup *etc
 		}
 
 		if !result.Valid {
-			return r.updateStatusFailed(ctx, backup, "Snapshot validation failed")
+			metrics.IncValidationFailures("SnapshotInvalid")
+			return r.updateStatusFailed(ctx, backup, reasonValidationFailed, "Snapshot validation failed")
 		}
+		message = "Stored snapshot verified and passed validation"
+		setBackupCondition(backup, etcdguardianv1alpha1.BackupConditionValidated, metav1.ConditionTrue, reasonSnapshotValid, "Snapshot passed validation")
 	}
 
-	backup.Status.Phase = etcdguardianv1alpha1.BackupPhaseValidatingSnapshot
-	if err := r.Status().Update(ctx, backup); err != nil {
-		return ctrl.Result{}, err
-	}
-
-	return ctrl.Result{Requeue: true}, nil
+	return r.updatePhase(ctx, backup, etcdguardianv1alpha1.BackupPhaseValidatingSnapshot, reasonSnapshotVerified, message)
 }
 
 // triggerVelero triggers Velero backup if enabled
 func (r *EtcdBackupReconciler) triggerVelero(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup) (ctrl.Result, error) {
 	log := r.Log.WithValues("etcdbackup", client.ObjectKeyFromObject(backup))
 
+	message := "Velero integration is disabled"
 	if backup.Spec.VeleroIntegration != nil && backup.Spec.VeleroIntegration.Enabled {
 		log.Info("Triggering Velero backup")
 		// TODO: Implement Velero integration
 		// For now, just mark as completed
+		message = "Velero integration is not implemented yet"
+		setBackupCondition(backup, etcdguardianv1alpha1.BackupConditionVeleroSynced, metav1.ConditionUnknown, reasonNotImplemented, message)
 	}
 
-	backup.Status.Phase = etcdguardianv1alpha1.BackupPhaseTriggeringVelero
-	if err := r.Status().Update(ctx, backup); err != nil {
-		return ctrl.Result{}, err
-	}
-
-	return ctrl.Result{Requeue: true}, nil
+	return r.updatePhase(ctx, backup, etcdguardianv1alpha1.BackupPhaseTriggeringVelero, reasonVeleroSkipped, message)
 }
 
 // completeBackup marks the backup as completed
@@ -281,36 +687,332 @@ func (r *EtcdBackupReconciler) completeBackup(ctx context.Context, backup *etcdg
 	log := r.Log.WithValues("etcdbackup", client.ObjectKeyFromObject(backup))
 	log.Info("Backup completed successfully")
 
-	backup.Status.Phase = etcdguardianv1alpha1.BackupPhaseCompleted
 	backup.Status.CompletionTime = &metav1.Time{Time: time.Now()}
 	backup.Status.Message = "Backup completed successfully"
+	setBackupCondition(backup, etcdguardianv1alpha1.BackupConditionReady, metav1.ConditionTrue, reasonCompleted, backup.Status.Message)
+
+	if _, err := r.updatePhase(ctx, backup, etcdguardianv1alpha1.BackupPhaseCompleted, reasonCompleted, backup.Status.Message); err != nil {
+		return ctrl.Result{}, err
+	}
+	recordBackupMetrics(backup)
+	metrics.RecordBackupSize(backup.Name, string(backup.Spec.BackupMode), backup.Status.SnapshotSize)
+	return ctrl.Result{}, nil
+}
+
+// replicateAsync copies a completed backup to the replicas it was not yet
+// copied to. Copies get a single attempt per reconcile: replicas that fail
+// with a transient error are retried after a backoff until they used up the
+// attempts of the default retry policy, other failures are reported in the
+// status and not retried.
+func (r *EtcdBackupReconciler) replicateAsync(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup) (ctrl.Result, error) {
+	log := r.Log.WithValues("etcdbackup", client.ObjectKeyFromObject(backup))
+	log.Info("Replicating backup asynchronously")
+
+	pending := map[string]bool{}
+	for _, status := range backup.Status.Replicas {
+		if status.Phase == etcdguardianv1alpha1.ReplicaPhasePending {
+			pending[status.Name] = true
+		}
+	}
+
+	var requeueAfter time.Duration
+	retry := func(failed int) bool {
+		if failed >= storage.DefaultRetryPolicy.MaxAttempts {
+			return false
+		}
+		requeueAfter = max(requeueAfter, storage.DefaultRetryPolicy.Backoff(failed))
+		return true
+	}
+
+	// The snapshot is downloaded to the snapshot directory for copying
+	replicator := newReplicator(r.Client, log).WithScratchDir(r.SnapshotDir)
+	err := preflight.NewChecker(r.Client, r.SnapshotDir).CheckScratchSpace(backup.Status.SnapshotSize)
+	if err == nil {
+		err = replicator.ReplicateAsync(ctx, backup)
+	}
+	if err != nil {
+		// The snapshot could not be downloaded from the destination
+		// holding it, so no replica was attempted
+		reason := storage.Classify(err).Reason()
+		if check, ok := err.(*preflight.Error); ok {
+			reason = check.Reason
+		}
+		failed := 0
+		for _, status := range backup.Status.Replicas {
+			failed += replication.FailedAttempts(backup, status.Name, storage.OperationDownload)
+		}
+		if storage.Classify(err).Retryable() && retry(failed) {
+			log.Info("Failed to replicate backup, retrying", "backoff", requeueAfter, "error", err.Error())
+		} else {
+			log.Error(err, "Failed to replicate backup")
+			r.Recorder.Event(backup, corev1.EventTypeWarning, reasonReplicationFailed, fmt.Sprintf("Failed to replicate backup: %v", err))
+			for i := range backup.Status.Replicas {
+				if backup.Status.Replicas[i].Phase == etcdguardianv1alpha1.ReplicaPhasePending {
+					backup.Status.Replicas[i].Phase = etcdguardianv1alpha1.ReplicaPhaseFailed
+					backup.Status.Replicas[i].Reason = reason
+					backup.Status.Replicas[i].Message = err.Error()
+				}
+			}
+		}
+	}
+	for i := range backup.Status.Replicas {
+		status := &backup.Status.Replicas[i]
+		if !pending[status.Name] || status.Phase != etcdguardianv1alpha1.ReplicaPhaseFailed ||
+			status.Reason != storage.ErrorClassRetryable.Reason() {
+			continue
+		}
+		if retry(replication.FailedAttempts(backup, status.Name, storage.OperationUpload)) {
+			log.Info("Failed to copy backup to replica, retrying", "destination", status.Name, "error", status.Message)
+			status.Phase = etcdguardianv1alpha1.ReplicaPhasePending
+		}
+	}
+	if err := replicator.VerifyServerSideEncryption(ctx, backup); err != nil {
+		log.Error(err, "Replica failed the server-side encryption check")
+	}
 
 	if err := r.Status().Update(ctx, backup); err != nil {
 		return ctrl.Result{}, err
 	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
 
-	return ctrl.Result{}, nil
+// syncLegalHold places or releases the legal hold of the stored snapshots
+// to match the backup spec. Destinations that fail are retried.
+func (r *EtcdBackupReconciler) syncLegalHold(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup) (ctrl.Result, error) {
+	log := r.Log.WithValues("etcdbackup", client.ObjectKeyFromObject(backup))
+	log.Info("Updating legal hold", "legalHold", backup.Spec.LegalHold)
+
+	replicator := newReplicator(r.Client, log)
+	holdErr := replicator.SyncLegalHold(ctx, backup)
+	if holdErr != nil {
+		r.Recorder.Event(backup, corev1.EventTypeWarning, reasonLegalHoldFailed, holdErr.Error())
+	}
+	if err := r.Status().Update(ctx, backup); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, holdErr
+}
+
+// applyTiering moves the stored snapshots to the tier their tiering rules
+// require. Destinations that fail are retried.
+func (r *EtcdBackupReconciler) applyTiering(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup) (ctrl.Result, error) {
+	log := r.Log.WithValues("etcdbackup", client.ObjectKeyFromObject(backup))
+	log.Info("Moving snapshots to colder storage tier")
+
+	mover := tiering.NewMover(newReplicator(r.Client, log), log)
+	tierErr := mover.Apply(ctx, backup, time.Now())
+	if tierErr != nil {
+		r.Recorder.Event(backup, corev1.EventTypeWarning, reasonTieringFailed, tierErr.Error())
+	}
+	if err := r.Status().Update(ctx, backup); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, tierErr
+}
+
+// replicationFailureReason returns the reason of the failed destinations of
+// a backup
+func replicationFailureReason(backup *etcdguardianv1alpha1.EtcdBackup) string {
+	if reason := replication.FailureReason(backup); reason != "" {
+		return reason
+	}
+	return reasonReplicationFailed
 }
 
-// updateStatusFailed updates the backup status to failed
-func (r *EtcdBackupReconciler) updateStatusFailed(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup, message string) (ctrl.Result, error) {
+// updatePhase moves a backup to its next phase and records an event for the
+// transition
+func (r *EtcdBackupReconciler) updatePhase(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup, phase etcdguardianv1alpha1.BackupPhase, reason, message string) (ctrl.Result, error) {
+	backup.Status.Phase = phase
+	backup.Status.PhaseStartTime = &metav1.Time{Time: time.Now()}
+	backup.Status.NextRetryTime = nil
+	if phase != etcdguardianv1alpha1.BackupPhaseCompleted {
+		setBackupCondition(backup, etcdguardianv1alpha1.BackupConditionReady, metav1.ConditionFalse, reasonInProgress, message)
+	}
+	if err := r.Status().Update(ctx, backup); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Event(backup, corev1.EventTypeNormal, reason, message)
+
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// setBackupCondition sets a condition of a backup for its current generation
+func setBackupCondition(backup *etcdguardianv1alpha1.EtcdBackup, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&backup.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: backup.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// failedCondition returns the condition of the step a backup fails in from
+// its phase, empty for steps without one
+func failedCondition(phase etcdguardianv1alpha1.BackupPhase) string {
+	switch phase {
+	case etcdguardianv1alpha1.BackupPhasePending, etcdguardianv1alpha1.BackupPhaseUploading:
+		return etcdguardianv1alpha1.BackupConditionValidated
+	case etcdguardianv1alpha1.BackupPhaseSnapshotting:
+		return etcdguardianv1alpha1.BackupConditionUploaded
+	}
+	return ""
+}
+
+// updateStatusFailed updates the backup status to failed with a
+// machine-readable reason. Transient failures are retried until the
+// backoff limit of the backup is exhausted.
+func (r *EtcdBackupReconciler) updateStatusFailed(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup, reason, message string) (ctrl.Result, error) {
+	// The Job of the failed attempt must not record results anymore
+	if backup.Status.Phase == etcdguardianv1alpha1.BackupPhasePreparing {
+		r.deleteJob(ctx, backup)
+	}
+
+	// Backups started before attempts were counted are on their first
+	backup.Status.Attempts = max(backup.Status.Attempts, 1)
+	backup.Status.LastFailure = &etcdguardianv1alpha1.BackupFailure{
+		Attempt: backup.Status.Attempts,
+		Phase:   backup.Status.Phase,
+		Reason:  reason,
+		Message: message,
+		Time:    metav1.Now(),
+	}
+	// Failures of phases cancelled at their deadline are not retried; the
+	// backup fails with reason DeadlineExceeded instead
+	if retryableReasons[reason] && ctx.Err() == nil && backup.Status.Attempts <= backoffLimit(backup) {
+		return r.retry(ctx, backup, reason, message)
+	}
+
+	// A backup failing after its pre-backup hooks ran still runs its
+	// post-backup hooks, so that what they paused is resumed
+	if hooks.StageRan(backup.Status.HookResults, etcdguardianv1alpha1.HookStagePreBackup) {
+		if err := r.runPostBackupHooks(ctx, backup); err != nil {
+			r.Log.Error(err, "Post-backup hooks of failed backup failed", "etcdbackup", client.ObjectKeyFromObject(backup))
+		}
+	}
+
+	if conditionType := failedCondition(backup.Status.Phase); conditionType != "" {
+		setBackupCondition(backup, conditionType, metav1.ConditionFalse, reason, message)
+	}
+	setBackupCondition(backup, etcdguardianv1alpha1.BackupConditionReady, metav1.ConditionFalse, reason, message)
 	backup.Status.Phase = etcdguardianv1alpha1.BackupPhaseFailed
 	backup.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+	backup.Status.Reason = reason
 	backup.Status.Message = message
 
 	if err := r.Status().Update(ctx, backup); err != nil {
 		return ctrl.Result{}, err
 	}
+	r.Recorder.Event(backup, corev1.EventTypeWarning, reason, message)
+	recordBackupMetrics(backup)
 
 	return ctrl.Result{}, fmt.Errorf("%s", message)
 }
 
+// retry schedules the next attempt of a backup that failed with a transient
+// error. The attempt resumes from the phase the backup failed in once a
+// backoff growing exponentially with the attempts has passed.
+func (r *EtcdBackupReconciler) retry(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup, reason, message string) (ctrl.Result, error) {
+	log := r.Log.WithValues("etcdbackup", client.ObjectKeyFromObject(backup))
+
+	// Resume what the pre-backup hooks paused for the backoff, and pause
+	// it again in the next attempt
+	if hooks.StageRan(backup.Status.HookResults, etcdguardianv1alpha1.HookStagePreBackup) &&
+		!hooks.StageRanAfter(backup.Status.HookResults, etcdguardianv1alpha1.HookStagePostBackup, etcdguardianv1alpha1.HookStagePreBackup) {
+		if err := r.runPostBackupHooks(ctx, backup); err != nil {
+			log.Error(err, "Post-backup hooks of retried backup failed")
+		}
+		backup.Status.Phase = etcdguardianv1alpha1.BackupPhaseValidating
+	}
+
+	backoff := retryBackoff(backup)
+	next := metav1.NewTime(time.Now().Add(backoff))
+	backup.Status.NextRetryTime = &next
+	backup.Status.PhaseStartTime = &next
+	backup.Status.Attempts++
+	backup.Status.Message = fmt.Sprintf("Attempt %d failed, retrying in %s: %s", backup.Status.Attempts-1, backoff, message)
+	if conditionType := failedCondition(backup.Status.LastFailure.Phase); conditionType != "" {
+		setBackupCondition(backup, conditionType, metav1.ConditionFalse, reason, message)
+	}
+	setBackupCondition(backup, etcdguardianv1alpha1.BackupConditionReady, metav1.ConditionFalse, reasonRetrying, backup.Status.Message)
+
+	if err := r.Status().Update(ctx, backup); err != nil {
+		return ctrl.Result{}, err
+	}
+	log.Info("Backup failed, retrying", "reason", reason, "attempt", backup.Status.Attempts, "backoff", backoff)
+	r.Recorder.Event(backup, corev1.EventTypeWarning, reason, backup.Status.Message)
+
+	return ctrl.Result{RequeueAfter: backoff}, nil
+}
+
+// backoffLimit returns the number of retries of a backup
+func backoffLimit(backup *etcdguardianv1alpha1.EtcdBackup) int32 {
+	if backup.Spec.BackoffLimit != nil {
+		return *backup.Spec.BackoffLimit
+	}
+	return defaultBackoffLimit
+}
+
+// retryBackoff returns the delay before the next attempt of a backup, the
+// initial backoff doubled for every attempt that failed before
+func retryBackoff(backup *etcdguardianv1alpha1.EtcdBackup) time.Duration {
+	initial, maxBackoff := defaultInitialBackoff, defaultMaxBackoff
+	if policy := backup.Spec.RetryPolicy; policy != nil {
+		if policy.InitialBackoff != nil {
+			initial = policy.InitialBackoff.Duration
+		}
+		if policy.MaxBackoff != nil {
+			maxBackoff = policy.MaxBackoff.Duration
+		}
+	}
+
+	backoff := initial
+	for i := int32(1); i < backup.Status.Attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
+}
+
+// recordBackupMetrics records the outcome and duration of a backup that
+// completed or failed
+func recordBackupMetrics(backup *etcdguardianv1alpha1.EtcdBackup) {
+	status := string(backup.Status.Phase)
+	metrics.IncBackupTotal(status)
+	if backup.Status.StartTime != nil && backup.Status.CompletionTime != nil {
+		duration := backup.Status.CompletionTime.Sub(backup.Status.StartTime.Time)
+		metrics.RecordBackupDuration(string(backup.Spec.BackupMode), status, duration.Seconds())
+	}
+}
+
+// runPostBackupHooks runs the post-backup hooks of a backup unless they ran
+// since its pre-backup hooks, recording their results in its status
+func (r *EtcdBackupReconciler) runPostBackupHooks(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup) error {
+	if backup.Spec.Hooks == nil || len(backup.Spec.Hooks.PostBackup) == 0 ||
+		hooks.StageRanAfter(backup.Status.HookResults, etcdguardianv1alpha1.HookStagePostBackup, etcdguardianv1alpha1.HookStagePreBackup) {
+		return nil
+	}
+	log := r.Log.WithValues("etcdbackup", client.ObjectKeyFromObject(backup))
+	results, err := hooks.NewRunner(r.Client, r.HookExecutor, log).Run(ctx, backup, etcdguardianv1alpha1.HookStagePostBackup, backup.Spec.Hooks.PostBackup)
+	backup.Status.HookResults = append(backup.Status.HookResults, results...)
+	return err
+}
+
 // handleDeletion handles the deletion of a backup
 func (r *EtcdBackupReconciler) handleDeletion(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup) (ctrl.Result, error) {
 	log := r.Log.WithValues("etcdbackup", client.ObjectKeyFromObject(backup))
 
 	if controllerutil.ContainsFinalizer(backup, backupFinalizer) {
-		// TODO: Clean up snapshot from storage if needed
+		// Keep the stored snapshots, which may be under retention, but
+		// stop the catalog sync from bringing the backup back
+		if err := catalog.Tombstone(ctx, r.Client, backup); err != nil {
+			log.Error(err, "Failed to mark stored snapshots as deleted")
+			r.Recorder.Eventf(backup, corev1.EventTypeWarning, reasonTombstoneFailed, "Failed to mark stored snapshots as deleted: %v", err)
+			return ctrl.Result{}, err
+		}
+		metrics.DeleteBackupSize(backup.Name)
+		if backup.Status.Phase == etcdguardianv1alpha1.BackupPhasePreparing {
+			r.deleteJob(ctx, backup)
+		}
 
 		log.Info("Removing finalizer")
 		controllerutil.RemoveFinalizer(backup, backupFinalizer)
@@ -322,9 +1024,47 @@ func (r *EtcdBackupReconciler) handleDeletion(ctx context.Context, backup *etcdg
 	return ctrl.Result{}, nil
 }
 
+// pendingBackupsForLocation returns the pending backups in the namespace of
+// a storage location that reference it, so that backups waiting for the
+// location start as soon as it becomes available
+func (r *EtcdBackupReconciler) pendingBackupsForLocation(ctx context.Context, obj client.Object) []reconcile.Request {
+	backups := &etcdguardianv1alpha1.EtcdBackupList{}
+	if err := r.List(ctx, backups, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "Failed to list backups", "storageLocation", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, backup := range backups.Items {
+		if backup.Status.Phase != etcdguardianv1alpha1.BackupPhasePending {
+			continue
+		}
+		references := backup.Spec.StorageLocationName == obj.GetName()
+		for _, replica := range backup.Spec.Replicas {
+			references = references || replica.StorageLocationName == obj.GetName()
+		}
+		if references {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&backup)})
+		}
+	}
+	return requests
+}
+
+// backupForJob returns the backup a snapshot Job runs for, so that the
+// backup moves on as soon as the Job finishes
+func (r *EtcdBackupReconciler) backupForJob(_ context.Context, obj client.Object) []reconcile.Request {
+	key, ok := backupjob.BackupKey(obj)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: key}}
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *EtcdBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&etcdguardianv1alpha1.EtcdBackup{}).
+		Watches(&etcdguardianv1alpha1.EtcdBackupStorageLocation{}, handler.EnqueueRequestsFromMapFunc(r.pendingBackupsForLocation)).
+		Watches(&batchv1.Job{}, handler.EnqueueRequestsFromMapFunc(r.backupForJob)).
 		Complete(r)
 }
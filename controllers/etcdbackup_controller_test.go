@@ -0,0 +1,443 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/replication"
+	"github.com/etcdguardian/etcdguardian/pkg/storage"
+)
+
+func TestMain(m *testing.M) {
+	storage.EnableMemoryStorage()
+	os.Exit(m.Run())
+}
+
+// newAsyncBackup returns a completed backup stored in its Filesystem
+// primary and still to be copied to a Memory replica
+func newAsyncBackup(t *testing.T) *etcdguardianv1alpha1.EtcdBackup {
+	t.Helper()
+	backup := &etcdguardianv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default", Finalizers: []string{backupFinalizer}},
+		Spec: etcdguardianv1alpha1.EtcdBackupSpec{
+			BackupMode:        etcdguardianv1alpha1.BackupModeFull,
+			ReplicationPolicy: etcdguardianv1alpha1.ReplicationPolicyPrimaryAsync,
+			StorageLocation: &etcdguardianv1alpha1.StorageLocation{
+				Provider: etcdguardianv1alpha1.StorageProviderFilesystem,
+				Bucket:   t.TempDir(),
+			},
+			Replicas: []etcdguardianv1alpha1.ReplicaLocation{{
+				Name:            "dr-west",
+				StorageLocation: &etcdguardianv1alpha1.StorageLocation{Provider: storage.ProviderMemory, Bucket: "dr-west"},
+			}},
+		},
+	}
+
+	snapshotPath := filepath.Join(t.TempDir(), "etcd-snapshot.db")
+	if err := os.WriteFile(snapshotPath, []byte("snapshot data"), 0o600); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+	if err := replication.NewReplicator(nil, logr.Discard()).Replicate(context.Background(), backup, snapshotPath); err != nil {
+		t.Fatalf("Replicate failed: %v", err)
+	}
+	backup.Status.Phase = etcdguardianv1alpha1.BackupPhaseCompleted
+	return backup
+}
+
+// reconcileAsyncBackup reconciles a backup in a fake cluster until it is
+// not requeued anymore and returns the number of reconciles
+func reconcileAsyncBackup(t *testing.T, backup *etcdguardianv1alpha1.EtcdBackup) int {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = etcdguardianv1alpha1.AddToScheme(scheme)
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(backup).
+		WithStatusSubresource(&etcdguardianv1alpha1.EtcdBackup{}).
+		Build()
+	reconciler := &EtcdBackupReconciler{
+		Client:   k8sClient,
+		Log:      logr.Discard(),
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(100),
+	}
+
+	key := client.ObjectKeyFromObject(backup)
+	for i := 1; i <= 10; i++ {
+		result, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		if err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+		if err := k8sClient.Get(context.Background(), key, backup); err != nil {
+			t.Fatalf("Failed to get backup: %v", err)
+		}
+		if result.RequeueAfter == 0 {
+			return i
+		}
+		if result.RequeueAfter > storage.DefaultRetryPolicy.MaxBackoff {
+			t.Fatalf("Expected a requeue within the backoff of the retry policy, got %s", result.RequeueAfter)
+		}
+	}
+	t.Fatalf("Expected the replication to settle, got %+v", backup.Status.Replicas)
+	return 0
+}
+
+func TestEtcdBackupReconciler_ReplicateAsyncRetriesTransientErrors(t *testing.T) {
+	t.Cleanup(storage.ResetMemoryStorage)
+	backup := newAsyncBackup(t)
+	storage.MemoryFaults("dr-west").Add(storage.Fault{
+		Operations: []storage.FaultOperation{storage.FaultPut},
+		Err:        &storage.InjectedError{Class: storage.ErrorClassRetryable},
+		Times:      2,
+	})
+
+	if reconciles := reconcileAsyncBackup(t, backup); reconciles != 3 {
+		t.Errorf("Expected the copy to succeed on the third reconcile, got %d", reconciles)
+	}
+	if replica := backup.Status.Replicas[1]; replica.Phase != etcdguardianv1alpha1.ReplicaPhaseCompleted {
+		t.Errorf("Expected the replica to be copied, got %+v", replica)
+	}
+	if failed := replication.FailedAttempts(backup, "dr-west", storage.OperationUpload); failed != 2 {
+		t.Errorf("Expected 2 failed uploads, got %d", failed)
+	}
+}
+
+func TestEtcdBackupReconciler_ReplicateAsyncGivesUp(t *testing.T) {
+	t.Cleanup(storage.ResetMemoryStorage)
+	backup := newAsyncBackup(t)
+	storage.MemoryFaults("dr-west").Add(storage.Fault{
+		Operations: []storage.FaultOperation{storage.FaultPut},
+		Err:        &storage.InjectedError{Class: storage.ErrorClassRetryable},
+	})
+
+	if reconciles := reconcileAsyncBackup(t, backup); reconciles != storage.DefaultRetryPolicy.MaxAttempts {
+		t.Errorf("Expected %d reconciles, got %d", storage.DefaultRetryPolicy.MaxAttempts, reconciles)
+	}
+	replica := backup.Status.Replicas[1]
+	if replica.Phase != etcdguardianv1alpha1.ReplicaPhaseFailed || replica.Reason != storage.ErrorClassRetryable.Reason() {
+		t.Errorf("Expected the replica to fail once its attempts are used up, got %+v", replica)
+	}
+}
+
+func TestEtcdBackupReconciler_CheckQuotas(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = etcdguardianv1alpha1.AddToScheme(scheme)
+	ctx := context.Background()
+
+	inBucket := func(bucket, prefix string) *etcdguardianv1alpha1.StorageLocation {
+		return &etcdguardianv1alpha1.StorageLocation{Provider: etcdguardianv1alpha1.StorageProviderOSS, Bucket: bucket, Prefix: prefix}
+	}
+	newBackup := func(name string, phase etcdguardianv1alpha1.BackupPhase, expected int64, location *etcdguardianv1alpha1.StorageLocation) *etcdguardianv1alpha1.EtcdBackup {
+		return &etcdguardianv1alpha1.EtcdBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a", UID: types.UID(name)},
+			Spec:       etcdguardianv1alpha1.EtcdBackupSpec{StorageLocation: location},
+			Status:     etcdguardianv1alpha1.EtcdBackupStatus{Phase: phase, ExpectedSnapshotSize: expected},
+		}
+	}
+	maxBytes := resource.MustParse("100")
+	// The quota of a location in another namespace applies to the backups
+	// storing into its bucket under any prefix, without referencing it
+	location := &etcdguardianv1alpha1.EtcdBackupStorageLocation{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "etcdguardian-system"},
+		Spec: etcdguardianv1alpha1.EtcdBackupStorageLocationSpec{
+			StorageLocation: *inBucket("shared", "etcd"),
+			Quota:           &etcdguardianv1alpha1.StorageQuota{MaxBytes: &maxBytes},
+		},
+	}
+	uploading := newBackup("uploading", etcdguardianv1alpha1.BackupPhaseUploading, 60, inBucket("shared", "team-a"))
+
+	reconciler := &EtcdBackupReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(location, uploading).Build(),
+		Log:    logr.Discard(),
+		Scheme: scheme,
+	}
+	check := func(backup *etcdguardianv1alpha1.EtcdBackup) string {
+		t.Helper()
+		targets, err := replication.Targets(ctx, reconciler.Client, backup)
+		if err != nil {
+			t.Fatalf("Targets failed: %v", err)
+		}
+		exceeded, err := reconciler.checkQuotas(ctx, backup, targets)
+		if err != nil {
+			t.Fatalf("checkQuotas failed: %v", err)
+		}
+		return exceeded
+	}
+
+	if exceeded := check(newBackup("nightly", etcdguardianv1alpha1.BackupPhasePending, 30, inBucket("shared", "other"))); exceeded != "" {
+		t.Errorf("Expected the backup to fit next to the one in progress, got %q", exceeded)
+	}
+	exceeded := check(newBackup("nightly", etcdguardianv1alpha1.BackupPhasePending, 50, inBucket("shared", "other")))
+	if !strings.Contains(exceeded, "Storage location etcdguardian-system/shared") {
+		t.Errorf("Expected the expected size not to fit next to the backup in progress, got %q", exceeded)
+	}
+	if exceeded := check(newBackup("nightly", etcdguardianv1alpha1.BackupPhasePending, 50, inBucket("private", "other"))); exceeded != "" {
+		t.Errorf("Expected the quota not to apply to other buckets, got %q", exceeded)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	duration := func(d time.Duration) *metav1.Duration {
+		return &metav1.Duration{Duration: d}
+	}
+	for _, tc := range []struct {
+		name     string
+		policy   *etcdguardianv1alpha1.RetryPolicy
+		attempts int32
+		expected time.Duration
+	}{
+		{name: "first attempt", attempts: 1, expected: defaultInitialBackoff},
+		{name: "attempts not counted", attempts: 0, expected: defaultInitialBackoff},
+		{name: "doubled per attempt", attempts: 3, expected: 4 * defaultInitialBackoff},
+		{name: "capped", attempts: 10, expected: defaultMaxBackoff},
+		{name: "custom initial", policy: &etcdguardianv1alpha1.RetryPolicy{InitialBackoff: duration(time.Second)}, attempts: 2, expected: 2 * time.Second},
+		{name: "custom max", policy: &etcdguardianv1alpha1.RetryPolicy{MaxBackoff: duration(15 * time.Second)}, attempts: 2, expected: 15 * time.Second},
+		{name: "initial above max", policy: &etcdguardianv1alpha1.RetryPolicy{InitialBackoff: duration(time.Minute), MaxBackoff: duration(30 * time.Second)}, attempts: 1, expected: 30 * time.Second},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			backup := &etcdguardianv1alpha1.EtcdBackup{
+				Spec:   etcdguardianv1alpha1.EtcdBackupSpec{RetryPolicy: tc.policy},
+				Status: etcdguardianv1alpha1.EtcdBackupStatus{Attempts: tc.attempts},
+			}
+			if backoff := retryBackoff(backup); backoff != tc.expected {
+				t.Errorf("Expected a backoff of %s, got %s", tc.expected, backoff)
+			}
+		})
+	}
+}
+
+func TestEtcdBackupReconciler_RetriesTransientFailures(t *testing.T) {
+	t.Cleanup(storage.ResetMemoryStorage)
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = etcdguardianv1alpha1.AddToScheme(scheme)
+	ctx := context.Background()
+
+	snapshotPath := filepath.Join(t.TempDir(), "etcd-snapshot.db")
+	if err := os.WriteFile(snapshotPath, []byte("snapshot data"), 0o600); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+	backoffLimit := int32(1)
+	backup := &etcdguardianv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default", Finalizers: []string{backupFinalizer}},
+		Spec: etcdguardianv1alpha1.EtcdBackupSpec{
+			BackupMode:      etcdguardianv1alpha1.BackupModeFull,
+			StorageLocation: &etcdguardianv1alpha1.StorageLocation{Provider: storage.ProviderMemory, Bucket: "primary"},
+			BackoffLimit:    &backoffLimit,
+			RetryPolicy:     &etcdguardianv1alpha1.RetryPolicy{InitialBackoff: &metav1.Duration{Duration: time.Minute}},
+		},
+		Status: etcdguardianv1alpha1.EtcdBackupStatus{
+			Phase:            etcdguardianv1alpha1.BackupPhaseSnapshotting,
+			StartTime:        &metav1.Time{Time: time.Now()},
+			Attempts:         1,
+			SnapshotLocation: snapshotPath,
+		},
+	}
+	storage.MemoryFaults("primary").Add(storage.Fault{
+		Operations: []storage.FaultOperation{storage.FaultPut},
+		Err:        &storage.InjectedError{Class: storage.ErrorClassRetryable},
+	})
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(backup).
+		WithStatusSubresource(&etcdguardianv1alpha1.EtcdBackup{}).
+		Build()
+	reconciler := &EtcdBackupReconciler{
+		Client:   k8sClient,
+		Log:      logr.Discard(),
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(100),
+	}
+	key := client.ObjectKeyFromObject(backup)
+
+	// The first failure is retried from the failed phase after the backoff
+	result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatalf("Expected a transient failure to be retried, got %v", err)
+	}
+	if result.RequeueAfter != time.Minute {
+		t.Errorf("Expected a requeue after the initial backoff, got %+v", result)
+	}
+	if err := k8sClient.Get(ctx, key, backup); err != nil {
+		t.Fatalf("Failed to get backup: %v", err)
+	}
+	if backup.Status.Phase != etcdguardianv1alpha1.BackupPhaseSnapshotting || backup.Status.Attempts != 2 || backup.Status.NextRetryTime == nil {
+		t.Errorf("Expected the second attempt to be scheduled in the failed phase, got %+v", backup.Status)
+	}
+	if failure := backup.Status.LastFailure; failure == nil || failure.Attempt != 1 || failure.Reason != storage.ErrorClassRetryable.Reason() {
+		t.Errorf("Expected the failure of the first attempt to be recorded, got %+v", failure)
+	}
+
+	// Reconciles within the backoff wait for it
+	result, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	if err != nil || result.RequeueAfter <= 0 || result.RequeueAfter > time.Minute {
+		t.Errorf("Expected the backoff to be waited out, got %+v, %v", result, err)
+	}
+
+	// The failure of the last attempt allowed by the limit is terminal
+	backup.Status.NextRetryTime = &metav1.Time{Time: time.Now().Add(-time.Second)}
+	if err := k8sClient.Status().Update(ctx, backup); err != nil {
+		t.Fatalf("Failed to update backup: %v", err)
+	}
+	result, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	if err == nil || result.RequeueAfter != 0 {
+		t.Errorf("Expected the backup to fail once its backoff limit is exhausted, got %+v, %v", result, err)
+	}
+	if err := k8sClient.Get(ctx, key, backup); err != nil {
+		t.Fatalf("Failed to get backup: %v", err)
+	}
+	if backup.Status.Phase != etcdguardianv1alpha1.BackupPhaseFailed || backup.Status.Reason != storage.ErrorClassRetryable.Reason() || backup.Status.Attempts != 2 {
+		t.Errorf("Expected the second attempt to fail the backup, got %+v", backup.Status)
+	}
+}
+
+// drainEvents returns the events recorded so far
+func drainEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestEtcdBackupReconciler_RecordsConditionsAndEvents(t *testing.T) {
+	t.Cleanup(storage.ResetMemoryStorage)
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = etcdguardianv1alpha1.AddToScheme(scheme)
+	ctx := context.Background()
+
+	snapshotPath := filepath.Join(t.TempDir(), "etcd-snapshot.db")
+	if err := os.WriteFile(snapshotPath, []byte("snapshot data"), 0o600); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+	uploaded := &etcdguardianv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default", Generation: 2, Finalizers: []string{backupFinalizer}},
+		Spec: etcdguardianv1alpha1.EtcdBackupSpec{
+			BackupMode:      etcdguardianv1alpha1.BackupModeFull,
+			StorageLocation: &etcdguardianv1alpha1.StorageLocation{Provider: storage.ProviderMemory, Bucket: "primary"},
+		},
+		Status: etcdguardianv1alpha1.EtcdBackupStatus{
+			Phase:            etcdguardianv1alpha1.BackupPhaseSnapshotting,
+			StartTime:        &metav1.Time{Time: time.Now()},
+			Attempts:         1,
+			SnapshotLocation: snapshotPath,
+		},
+	}
+	// Backups without a storage location fail their validation
+	invalid := &etcdguardianv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "invalid", Namespace: "default", Generation: 1, Finalizers: []string{backupFinalizer}},
+		Spec:       etcdguardianv1alpha1.EtcdBackupSpec{BackupMode: etcdguardianv1alpha1.BackupModeFull},
+	}
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(uploaded, invalid).
+		WithStatusSubresource(&etcdguardianv1alpha1.EtcdBackup{}).
+		Build()
+	recorder := record.NewFakeRecorder(100)
+	reconciler := &EtcdBackupReconciler{
+		Client:   k8sClient,
+		Log:      logr.Discard(),
+		Scheme:   scheme,
+		Recorder: recorder,
+	}
+	reconcile := func(backup *etcdguardianv1alpha1.EtcdBackup, phase etcdguardianv1alpha1.BackupPhase) {
+		t.Helper()
+		key := client.ObjectKeyFromObject(backup)
+		for i := 0; i < 10 && backup.Status.Phase != phase; i++ {
+			_, _ = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+			if err := k8sClient.Get(ctx, key, backup); err != nil {
+				t.Fatalf("Failed to get backup: %v", err)
+			}
+		}
+		if backup.Status.Phase != phase {
+			t.Fatalf("Expected the backup to reach %s, got %+v", phase, backup.Status)
+		}
+	}
+	expectConditions := func(backup *etcdguardianv1alpha1.EtcdBackup, expected map[string]metav1.Condition) {
+		t.Helper()
+		for conditionType, want := range expected {
+			condition := meta.FindStatusCondition(backup.Status.Conditions, conditionType)
+			if condition == nil || condition.Status != want.Status || condition.Reason != want.Reason || condition.ObservedGeneration != backup.Generation {
+				t.Errorf("Expected condition %s to be %s with reason %s for generation %d, got %+v",
+					conditionType, want.Status, want.Reason, backup.Generation, condition)
+			}
+		}
+	}
+	expectEvents := func(expected ...string) {
+		t.Helper()
+		events := drainEvents(recorder)
+		if len(events) != len(expected) {
+			t.Fatalf("Expected events %q, got %q", expected, events)
+		}
+		for i, prefix := range expected {
+			if !strings.HasPrefix(events[i], prefix+" ") {
+				t.Errorf("Expected event %d to be %q, got %q", i, prefix, events[i])
+			}
+		}
+	}
+
+	// Every phase transition records a normal event, and completion makes
+	// the backup Ready
+	reconcile(uploaded, etcdguardianv1alpha1.BackupPhaseCompleted)
+	expectEvents(
+		"Normal "+reasonUploaded,
+		"Normal "+reasonSnapshotVerified,
+		"Normal "+reasonVeleroSkipped,
+		"Normal "+reasonCompleted,
+	)
+	expectConditions(uploaded, map[string]metav1.Condition{
+		etcdguardianv1alpha1.BackupConditionReady:    {Status: metav1.ConditionTrue, Reason: reasonCompleted},
+		etcdguardianv1alpha1.BackupConditionUploaded: {Status: metav1.ConditionTrue, Reason: reasonUploaded},
+	})
+
+	// A failure records a warning event and fails the condition of its step
+	reconcile(invalid, etcdguardianv1alpha1.BackupPhaseFailed)
+	expectEvents("Normal "+reasonStarted, "Warning "+reasonInvalidConfig)
+	expectConditions(invalid, map[string]metav1.Condition{
+		etcdguardianv1alpha1.BackupConditionReady:     {Status: metav1.ConditionFalse, Reason: reasonInvalidConfig},
+		etcdguardianv1alpha1.BackupConditionValidated: {Status: metav1.ConditionFalse, Reason: reasonInvalidConfig},
+	})
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/quota"
+	"github.com/etcdguardian/etcdguardian/pkg/replication"
+	"github.com/etcdguardian/etcdguardian/pkg/storage"
+)
+
+const (
+	// defaultValidationFrequency applies to storage locations that do not
+	// set their ValidationFrequency
+	defaultValidationFrequency = 5 * time.Minute
+
+	// accessCheckTimeout limits a single access check of a storage location
+	accessCheckTimeout = 2 * time.Minute
+
+	// reasonAccessChecked is the reason of an available storage location
+	reasonAccessChecked = "AccessChecked"
+)
+
+// EtcdBackupStorageLocationReconciler periodically checks access to
+// EtcdBackupStorageLocations and reports the result in their Available
+// condition
+type EtcdBackupStorageLocationReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=etcdguardian.io,resources=etcdbackupstoragelocations,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=etcdguardian.io,resources=etcdbackupstoragelocations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=etcdguardian.io,resources=etcdbackups,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// Reconcile checks write, read, list and delete access to a storage
+// location once its validation frequency has passed or its spec changed,
+// and reports the storage used in it per namespace or tenant
+func (r *EtcdBackupStorageLocationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("etcdbackupstoragelocation", req.NamespacedName)
+
+	location := &etcdguardianv1alpha1.EtcdBackupStorageLocation{}
+	if err := r.Get(ctx, req.NamespacedName, location); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get EtcdBackupStorageLocation")
+		return ctrl.Result{}, err
+	}
+
+	frequency := validationFrequency(location)
+	available := meta.FindStatusCondition(location.Status.Conditions, etcdguardianv1alpha1.StorageLocationConditionAvailable)
+	if available != nil && available.ObservedGeneration == location.Generation && location.Status.LastValidationTime != nil {
+		if next := time.Until(location.Status.LastValidationTime.Add(frequency)); next > 0 {
+			return ctrl.Result{RequeueAfter: next}, nil
+		}
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, accessCheckTimeout)
+	defer cancel()
+	store, err := storage.NewObjectStore(location.Spec.Provider, location.Spec.StorageLocation, r.Client, location.Namespace)
+	if err == nil {
+		err = storage.CheckAccess(checkCtx, store, location.Spec.StorageLocation)
+	}
+
+	now := metav1.Now()
+	location.Status.LastValidationTime = &now
+	condition := metav1.Condition{
+		Type:               etcdguardianv1alpha1.StorageLocationConditionAvailable,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: location.Generation,
+		Reason:             reasonAccessChecked,
+		Message:            "Write, read, list and delete access succeeded",
+	}
+	if err != nil {
+		log.Error(err, "Storage location is unavailable")
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = storage.Classify(err).Reason()
+		condition.Message = err.Error()
+	} else {
+		location.Status.LastAvailableTime = &now
+	}
+	meta.SetStatusCondition(&location.Status.Conditions, condition)
+	location.Status.Message = condition.Message
+
+	if usage, err := r.storageUsage(ctx, location); err != nil {
+		log.Error(err, "Failed to compute storage usage")
+	} else {
+		location.Status.Usage = usage
+	}
+
+	if err := r.Status().Update(ctx, location); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: frequency}, nil
+}
+
+// storageUsage returns the storage used in the bucket of a
+// location by the backups of every namespace, grouped by the tenant label
+// of its quota if any
+func (r *EtcdBackupStorageLocationReconciler) storageUsage(ctx context.Context, location *etcdguardianv1alpha1.EtcdBackupStorageLocation) ([]etcdguardianv1alpha1.StorageUsage, error) {
+	locationQuota := &etcdguardianv1alpha1.StorageQuota{}
+	if location.Spec.Quota != nil {
+		locationQuota = location.Spec.Quota
+	}
+	grouper, err := quota.NewGrouper(ctx, r.Client, locationQuota)
+	if err != nil {
+		return nil, err
+	}
+
+	backups := &etcdguardianv1alpha1.EtcdBackupList{}
+	if err := r.List(ctx, backups); err != nil {
+		return nil, err
+	}
+	usage := quota.Compute(ctx, r.Client, grouper, backups.Items, func(target replication.Target) bool {
+		return quota.SameBucket(target.Location, location.Spec.StorageLocation)
+	})
+	return quota.Report(usage), nil
+}
+
+// validationFrequency returns how often access to a storage location is checked
+func validationFrequency(location *etcdguardianv1alpha1.EtcdBackupStorageLocation) time.Duration {
+	if location.Spec.ValidationFrequency != nil && location.Spec.ValidationFrequency.Duration > 0 {
+		return location.Spec.ValidationFrequency.Duration
+	}
+	return defaultValidationFrequency
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *EtcdBackupStorageLocationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&etcdguardianv1alpha1.EtcdBackupStorageLocation{}).
+		Complete(r)
+}
@@ -18,13 +18,30 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/hooks"
+	"github.com/etcdguardian/etcdguardian/pkg/metrics"
+	"github.com/etcdguardian/etcdguardian/pkg/tiering"
+)
+
+// rehydrationPollInterval is how often a restore checks whether its
+// archived snapshot was rehydrated, which takes hours
+const rehydrationPollInterval = 5 * time.Minute
+
+// Machine-readable reasons of failed restores
+const (
+	reasonBackupNotFound     = "BackupNotFound"
+	reasonBackupNotCompleted = "BackupNotCompleted"
 )
 
 // EtcdRestoreReconciler reconciles a EtcdRestore object
@@ -32,21 +49,222 @@ type EtcdRestoreReconciler struct {
 	client.Client
 	Log    logr.Logger
 	Scheme *runtime.Scheme
+
+	// HookExecutor runs the commands of exec hooks in pods
+	HookExecutor hooks.Executor
 }
 
 // +kubebuilder:rbac:groups=etcdguardian.io,resources=etcdrestores,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=etcdguardian.io,resources=etcdrestores/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=etcdguardian.io,resources=etcdrestores/finalizers,verbs=update
+// +kubebuilder:rbac:groups=etcdguardian.io,resources=etcdbackups,verbs=get;list;watch
+// +kubebuilder:rbac:groups=etcdguardian.io,resources=etcdbackupstoragelocations,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop
 func (r *EtcdRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := r.Log.WithValues("etcdrestore", req.NamespacedName)
 	log.Info("Reconciling EtcdRestore")
 
-	// TODO: Implement restore logic
+	restore := &etcdguardianv1alpha1.EtcdRestore{}
+	if err := r.Get(ctx, req.NamespacedName, restore); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get EtcdRestore")
+		return ctrl.Result{}, err
+	}
+
+	switch restore.Status.Phase {
+	case etcdguardianv1alpha1.RestorePhaseCompleted, etcdguardianv1alpha1.RestorePhaseFailed:
+		return ctrl.Result{}, nil
+	case "":
+		restore.Status.StartTime = &metav1.Time{Time: time.Now()}
+		setRestorePhase(restore, etcdguardianv1alpha1.RestorePhasePending)
+		if err := r.Status().Update(ctx, restore); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// Fail restores past their active deadline or the timeout of their
+	// phase
+	deadline, exceeded := restoreDeadline(restore)
+	if !deadline.IsZero() && !time.Now().Before(deadline) {
+		return r.updateStatusFailed(ctx, restore, reasonDeadlineExceeded, exceeded)
+	}
+
+	phaseCtx, cancel := deadlineContext(ctx, deadline)
+	defer cancel()
+	result, err := r.runPhase(phaseCtx, restore)
+	if phaseCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		// The status updates of the phase may have failed with its
+		// context; fail the restore with a live one
+		log.Info("Restore ran past its deadline", "deadline", deadline)
+		return ctrl.Result{Requeue: true}, nil
+	}
+	return requeueByDeadline(result, deadline), err
+}
+
+// runPhase runs the step of the phase of a restore
+func (r *EtcdRestoreReconciler) runPhase(ctx context.Context, restore *etcdguardianv1alpha1.EtcdRestore) (ctrl.Result, error) {
+	switch restore.Status.Phase {
+	case etcdguardianv1alpha1.RestorePhasePending, etcdguardianv1alpha1.RestorePhaseRehydrating:
+		return r.rehydrateSnapshot(ctx, restore)
+	case etcdguardianv1alpha1.RestorePhaseQuiescing:
+		return r.quiesce(ctx, restore)
+	case etcdguardianv1alpha1.RestorePhaseRestoring:
+		// TODO: Implement restore and validation, then run the post-restore
+		// hooks with runPostRestoreHooks and complete the restore. Until then
+		// restores fail rather than hang in the Restoring phase.
+		return r.updateStatusFailed(ctx, restore, reasonNotImplemented, "Restoring snapshots is not implemented yet")
+	}
 	return ctrl.Result{}, nil
 }
 
+// restoreDeadline returns when a restore fails for running too long, zero
+// for never, and the message to fail it with
+func restoreDeadline(restore *etcdguardianv1alpha1.EtcdRestore) (time.Time, string) {
+	var name string
+	var timeout *metav1.Duration
+	if timeouts := restore.Spec.Timeouts; timeouts != nil {
+		switch restore.Status.Phase {
+		case etcdguardianv1alpha1.RestorePhaseRehydrating:
+			name, timeout = "Rehydration", timeouts.Rehydration
+		case etcdguardianv1alpha1.RestorePhaseQuiescing:
+			name, timeout = "Quiesce", timeouts.Quiesce
+		case etcdguardianv1alpha1.RestorePhaseRestoring:
+			name, timeout = "Restore", timeouts.Restore
+		case etcdguardianv1alpha1.RestorePhaseValidating:
+			name, timeout = "Validation", timeouts.Validation
+		}
+	}
+	return operationDeadline(restore.Status.StartTime, restore.Spec.ActiveDeadlineSeconds, restore.Status.PhaseStartTime, name, timeout)
+}
+
+// setRestorePhase moves a restore to a phase
+func setRestorePhase(restore *etcdguardianv1alpha1.EtcdRestore, phase etcdguardianv1alpha1.RestorePhase) {
+	restore.Status.Phase = phase
+	restore.Status.PhaseStartTime = &metav1.Time{Time: time.Now()}
+}
+
+// quiesce runs the pre-restore hooks, such as pausing deploy pipelines
+// that would write to the cluster being restored
+func (r *EtcdRestoreReconciler) quiesce(ctx context.Context, restore *etcdguardianv1alpha1.EtcdRestore) (ctrl.Result, error) {
+	log := r.Log.WithValues("etcdrestore", client.ObjectKeyFromObject(restore))
+
+	if len(restore.Spec.PreRestoreHooks) > 0 {
+		results, err := hooks.NewRunner(r.Client, r.HookExecutor, log).Run(ctx, restore, etcdguardianv1alpha1.HookStagePreRestore, restore.Spec.PreRestoreHooks)
+		restore.Status.HookResults = append(restore.Status.HookResults, results...)
+		if err != nil {
+			return r.updateStatusFailed(ctx, restore, reasonHookFailed, err.Error())
+		}
+	}
+
+	setRestorePhase(restore, etcdguardianv1alpha1.RestorePhaseRestoring)
+	if err := r.Status().Update(ctx, restore); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// runPostRestoreHooks runs the post-restore hooks of a restore unless they
+// ran already, recording their results in its status
+func (r *EtcdRestoreReconciler) runPostRestoreHooks(ctx context.Context, restore *etcdguardianv1alpha1.EtcdRestore) error {
+	if len(restore.Spec.PostRestoreHooks) == 0 || hooks.StageRan(restore.Status.HookResults, etcdguardianv1alpha1.HookStagePostRestore) {
+		return nil
+	}
+	log := r.Log.WithValues("etcdrestore", client.ObjectKeyFromObject(restore))
+	results, err := hooks.NewRunner(r.Client, r.HookExecutor, log).Run(ctx, restore, etcdguardianv1alpha1.HookStagePostRestore, restore.Spec.PostRestoreHooks)
+	restore.Status.HookResults = append(restore.Status.HookResults, results...)
+	return err
+}
+
+// rehydrateSnapshot makes the snapshot of the backup readable. Snapshots
+// moved to an archive tier are rehydrated first, which keeps the restore in
+// the Rehydrating phase until the storage provider is done.
+func (r *EtcdRestoreReconciler) rehydrateSnapshot(ctx context.Context, restore *etcdguardianv1alpha1.EtcdRestore) (ctrl.Result, error) {
+	log := r.Log.WithValues("etcdrestore", client.ObjectKeyFromObject(restore))
+
+	location := restore.Spec.SnapshotLocation
+	if location == "" {
+		backup := &etcdguardianv1alpha1.EtcdBackup{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: restore.Namespace, Name: restore.Spec.BackupName}, backup); err != nil {
+			if errors.IsNotFound(err) {
+				return r.updateStatusFailed(ctx, restore, reasonBackupNotFound, fmt.Sprintf("Backup %s not found", restore.Spec.BackupName))
+			}
+			return ctrl.Result{}, err
+		}
+		if backup.Status.Phase != etcdguardianv1alpha1.BackupPhaseCompleted {
+			return r.updateStatusFailed(ctx, restore, reasonBackupNotCompleted, fmt.Sprintf("Backup %s is not completed", backup.Name))
+		}
+
+		mover := tiering.NewMover(newReplicator(r.Client, log), log)
+		snapshotLocation, ready, err := mover.Rehydrate(ctx, backup)
+		if err != nil {
+			log.Error(err, "Failed to rehydrate snapshot")
+			return ctrl.Result{}, err
+		}
+		if !ready {
+			if restore.Status.Phase != etcdguardianv1alpha1.RestorePhaseRehydrating {
+				log.Info("Requested rehydration of archived snapshot", "location", snapshotLocation)
+				setRestorePhase(restore, etcdguardianv1alpha1.RestorePhaseRehydrating)
+				restore.Status.RehydrationRequestTime = &metav1.Time{Time: time.Now()}
+			}
+			restore.Status.SnapshotLocation = snapshotLocation
+			restore.Status.Message = fmt.Sprintf("Waiting for rehydration of archived snapshot %s", snapshotLocation)
+			if err := r.Status().Update(ctx, restore); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: rehydrationPollInterval}, nil
+		}
+		location = snapshotLocation
+	}
+
+	setRestorePhase(restore, etcdguardianv1alpha1.RestorePhaseQuiescing)
+	restore.Status.SnapshotLocation = location
+	restore.Status.Message = ""
+	if err := r.Status().Update(ctx, restore); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// updateStatusFailed updates the restore status to failed with a
+// machine-readable reason
+func (r *EtcdRestoreReconciler) updateStatusFailed(ctx context.Context, restore *etcdguardianv1alpha1.EtcdRestore, reason, message string) (ctrl.Result, error) {
+	// A restore failing after its pre-restore hooks ran still runs its
+	// post-restore hooks, so that what they paused is resumed
+	if hooks.StageRan(restore.Status.HookResults, etcdguardianv1alpha1.HookStagePreRestore) {
+		if err := r.runPostRestoreHooks(ctx, restore); err != nil {
+			r.Log.Error(err, "Post-restore hooks of failed restore failed", "etcdrestore", client.ObjectKeyFromObject(restore))
+		}
+	}
+
+	restore.Status.Phase = etcdguardianv1alpha1.RestorePhaseFailed
+	restore.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+	restore.Status.Reason = reason
+	restore.Status.Errors = append(restore.Status.Errors, message)
+	restore.Status.Message = message
+
+	if err := r.Status().Update(ctx, restore); err != nil {
+		return ctrl.Result{}, err
+	}
+	recordRestoreMetrics(restore)
+	return ctrl.Result{}, fmt.Errorf("%s", message)
+}
+
+// recordRestoreMetrics records the outcome of a restore that completed or
+// failed, and the duration of a completed one
+func recordRestoreMetrics(restore *etcdguardianv1alpha1.EtcdRestore) {
+	metrics.IncRestoreTotal(string(restore.Status.Phase))
+	if restore.Status.Phase != etcdguardianv1alpha1.RestorePhaseCompleted || restore.Status.StartTime == nil || restore.Status.CompletionTime == nil {
+		return
+	}
+	duration := restore.Status.CompletionTime.Sub(restore.Status.StartTime.Time)
+	metrics.RecordRestoreDuration(string(restore.Spec.RestoreMode), duration.Seconds())
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *EtcdRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -0,0 +1,169 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/metrics"
+	"github.com/etcdguardian/etcdguardian/pkg/replication"
+)
+
+// recordingExecutor records the commands of exec hooks
+type recordingExecutor struct {
+	calls []string
+}
+
+func (e *recordingExecutor) Exec(_ context.Context, namespace, pod, _ string, command []string, _, _ io.Writer) error {
+	e.calls = append(e.calls, fmt.Sprintf("%s/%s: %s", namespace, pod, strings.Join(command, " ")))
+	return nil
+}
+
+// newCompletedBackup returns a completed backup whose snapshot is stored in
+// a Filesystem location
+func newCompletedBackup(t *testing.T) *etcdguardianv1alpha1.EtcdBackup {
+	t.Helper()
+	backup := &etcdguardianv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+		Spec: etcdguardianv1alpha1.EtcdBackupSpec{
+			BackupMode: etcdguardianv1alpha1.BackupModeFull,
+			StorageLocation: &etcdguardianv1alpha1.StorageLocation{
+				Provider: etcdguardianv1alpha1.StorageProviderFilesystem,
+				Bucket:   t.TempDir(),
+			},
+		},
+	}
+
+	snapshotPath := filepath.Join(t.TempDir(), "etcd-snapshot.db")
+	if err := os.WriteFile(snapshotPath, []byte("snapshot data"), 0o600); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+	if err := replication.NewReplicator(nil, logr.Discard()).Replicate(context.Background(), backup, snapshotPath); err != nil {
+		t.Fatalf("Replicate failed: %v", err)
+	}
+	backup.Status.Phase = etcdguardianv1alpha1.BackupPhaseCompleted
+	return backup
+}
+
+func TestEtcdRestoreReconciler_FailsUntilRestoreIsImplemented(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = etcdguardianv1alpha1.AddToScheme(scheme)
+
+	backup := newCompletedBackup(t)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "deployer", Namespace: "default", Labels: map[string]string{"app": "deployer"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "deployer", Image: "deployer"}}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	hook := func(name, command string) etcdguardianv1alpha1.Hook {
+		return etcdguardianv1alpha1.Hook{
+			Name: name,
+			Type: etcdguardianv1alpha1.HookTypeExec,
+			Exec: &etcdguardianv1alpha1.ExecHook{
+				PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "deployer"}},
+				Command:     []string{command},
+			},
+		}
+	}
+	restore := &etcdguardianv1alpha1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{Name: "recover", Namespace: "default"},
+		Spec: etcdguardianv1alpha1.EtcdRestoreSpec{
+			BackupName:       backup.Name,
+			RestoreMode:      etcdguardianv1alpha1.RestoreModeFull,
+			EtcdCluster:      etcdguardianv1alpha1.EtcdClusterConfig{Endpoints: []string{"https://etcd-0:2379"}, DataDir: "/var/lib/etcd"},
+			PreRestoreHooks:  []etcdguardianv1alpha1.Hook{hook("pause", "pause")},
+			PostRestoreHooks: []etcdguardianv1alpha1.Hook{hook("resume", "resume")},
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(backup, pod, restore).
+		WithStatusSubresource(&etcdguardianv1alpha1.EtcdBackup{}, &etcdguardianv1alpha1.EtcdRestore{}).
+		Build()
+	executor := &recordingExecutor{}
+	reconciler := &EtcdRestoreReconciler{
+		Client:       k8sClient,
+		Log:          logr.Discard(),
+		Scheme:       scheme,
+		HookExecutor: executor,
+	}
+
+	completed := testutil.ToFloat64(metrics.RestoreTotal.WithLabelValues(string(etcdguardianv1alpha1.RestorePhaseCompleted)))
+	failed := testutil.ToFloat64(metrics.RestoreTotal.WithLabelValues(string(etcdguardianv1alpha1.RestorePhaseFailed)))
+	key := client.ObjectKeyFromObject(restore)
+	phases := []etcdguardianv1alpha1.RestorePhase{}
+	for i := 0; i < 10; i++ {
+		result, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		if getErr := k8sClient.Get(context.Background(), key, restore); getErr != nil {
+			t.Fatalf("Failed to get restore: %v", getErr)
+		}
+		phases = append(phases, restore.Status.Phase)
+		if restore.Status.Phase == etcdguardianv1alpha1.RestorePhaseFailed {
+			if err == nil {
+				t.Error("Expected the failed restore to return an error")
+			}
+			break
+		}
+		if err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+		if !result.Requeue && result.RequeueAfter == 0 {
+			break
+		}
+	}
+
+	expected := []etcdguardianv1alpha1.RestorePhase{
+		etcdguardianv1alpha1.RestorePhasePending,
+		etcdguardianv1alpha1.RestorePhaseQuiescing,
+		etcdguardianv1alpha1.RestorePhaseRestoring,
+		etcdguardianv1alpha1.RestorePhaseFailed,
+	}
+	if fmt.Sprint(phases) != fmt.Sprint(expected) {
+		t.Fatalf("Expected the phases %v, got %v", expected, phases)
+	}
+	if restore.Status.Reason != reasonNotImplemented || restore.Status.CompletionTime == nil {
+		t.Errorf("Expected the restore to fail as not implemented, got %+v", restore.Status)
+	}
+	if calls := strings.Join(executor.calls, "; "); calls != "default/deployer: pause; default/deployer: resume" {
+		t.Errorf("Expected the post-restore hooks to resume what the pre-restore hooks paused, got %s", calls)
+	}
+	if value := testutil.ToFloat64(metrics.RestoreTotal.WithLabelValues(string(etcdguardianv1alpha1.RestorePhaseFailed))); value != failed+1 {
+		t.Errorf("Expected the restore to be counted as failed, got %v", value-failed)
+	}
+	if value := testutil.ToFloat64(metrics.RestoreTotal.WithLabelValues(string(etcdguardianv1alpha1.RestorePhaseCompleted))); value != completed {
+		t.Errorf("Expected no completed restore to be counted, got %v", value-completed)
+	}
+}
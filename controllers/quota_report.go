@@ -0,0 +1,136 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/quota"
+)
+
+// QuotaReporter periodically writes the storage used by every namespace, or
+// tenant with the tenant label of the operator-wide quota, to a ConfigMap.
+// Each key is "namespace.<name>" or "tenant.<name>" with the JSON usage,
+// including the limits of the operator-wide quota.
+type QuotaReporter struct {
+	client.Client
+	Log logr.Logger
+
+	// Interval between reports
+	Interval time.Duration
+
+	// Quota is the operator-wide quota, nil for none
+	Quota *etcdguardianv1alpha1.StorageQuota
+
+	// ConfigMap is the ConfigMap the usage is written to
+	ConfigMap client.ObjectKey
+}
+
+// quotaReport is the usage of a namespace or tenant in the ConfigMap
+type quotaReport struct {
+	etcdguardianv1alpha1.StorageUsage `json:",inline"`
+
+	// Quota is the operator-wide quota
+	Quota *etcdguardianv1alpha1.StorageQuota `json:"quota,omitempty"`
+}
+
+// +kubebuilder:rbac:groups=etcdguardian.io,resources=etcdbackups,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=create;update
+
+// Start runs the report loop until the context is cancelled
+func (q *QuotaReporter) Start(ctx context.Context) error {
+	ticker := time.NewTicker(q.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := q.report(ctx); err != nil {
+			q.Log.Error(err, "Failed to report storage usage")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection makes only the leader write the report
+func (q *QuotaReporter) NeedLeaderElection() bool {
+	return true
+}
+
+// report computes the storage used in all destinations and writes it
+func (q *QuotaReporter) report(ctx context.Context) error {
+	operatorQuota := q.Quota
+	if operatorQuota == nil {
+		operatorQuota = &etcdguardianv1alpha1.StorageQuota{}
+	}
+	grouper, err := quota.NewGrouper(ctx, q.Client, operatorQuota)
+	if err != nil {
+		return err
+	}
+	backups := &etcdguardianv1alpha1.EtcdBackupList{}
+	if err := q.List(ctx, backups); err != nil {
+		return fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	data := map[string]string{}
+	for _, usage := range quota.Report(quota.Compute(ctx, q.Client, grouper, backups.Items, nil)) {
+		key := "namespace." + usage.Namespace
+		if usage.Tenant != "" {
+			key = "tenant." + usage.Tenant
+		}
+		value, err := json.Marshal(quotaReport{StorageUsage: usage, Quota: q.Quota})
+		if err != nil {
+			return err
+		}
+		data[key] = string(value)
+	}
+
+	// The update is unconditional, so that the ConfigMap is not read
+	// through a cache of every ConfigMap in the cluster
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: q.ConfigMap.Name, Namespace: q.ConfigMap.Namespace},
+		Data:       data,
+	}
+	err = q.Update(ctx, configMap)
+	if errors.IsNotFound(err) {
+		err = q.Create(ctx, configMap)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write ConfigMap %s: %w", q.ConfigMap, err)
+	}
+	return nil
+}
+
+// SetupWithManager adds the report loop to the Manager.
+func (q *QuotaReporter) SetupWithManager(mgr ctrl.Manager) error {
+	return mgr.Add(q)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/replication"
+)
+
+// UploadCleaner periodically aborts multipart uploads that no backup tracks
+// anymore, such as uploads of backups that failed or were deleted while
+// their snapshot was being uploaded. Incomplete uploads are otherwise kept
+// and billed by most object stores.
+type UploadCleaner struct {
+	client.Client
+	Log logr.Logger
+
+	// Interval between cleanup runs
+	Interval time.Duration
+
+	// GracePeriod is how long an untracked upload is kept before it is
+	// aborted, which protects uploads whose session is not persisted yet
+	GracePeriod time.Duration
+}
+
+// Start runs the cleanup loop until the context is cancelled
+func (c *UploadCleaner) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		c.cleanup(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection makes only the leader abort uploads
+func (c *UploadCleaner) NeedLeaderElection() bool {
+	return true
+}
+
+// cleanup aborts orphaned uploads in the storage destinations of all backups
+func (c *UploadCleaner) cleanup(ctx context.Context) {
+	backups := &etcdguardianv1alpha1.EtcdBackupList{}
+	if err := c.List(ctx, backups); err != nil {
+		c.Log.Error(err, "Failed to list backups")
+		return
+	}
+
+	replicator := replication.NewReplicator(c.Client, c.Log)
+	aborted, err := replicator.AbortOrphanedUploads(ctx, backups.Items, c.GracePeriod)
+	if err != nil {
+		c.Log.Error(err, "Failed to clean up orphaned uploads")
+	}
+	if aborted > 0 {
+		c.Log.Info("Aborted orphaned uploads", "count", aborted)
+	}
+}
+
+// SetupWithManager adds the cleanup loop to the Manager.
+func (c *UploadCleaner) SetupWithManager(mgr ctrl.Manager) error {
+	return mgr.Add(c)
+}
@@ -0,0 +1,368 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backupjob runs the snapshot and upload of backups in the Job
+// execution mode in short-lived Jobs on control-plane nodes, which report
+// their results through the status of the backup.
+package backupjob
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/replication"
+	"github.com/etcdguardian/etcdguardian/pkg/snapshot"
+	"github.com/etcdguardian/etcdguardian/pkg/storage"
+)
+
+const (
+	// BackupUIDLabel labels Jobs with the UID of their backup
+	BackupUIDLabel = "etcdguardian.io/backup-uid"
+
+	// BackupNamespaceAnnotation and BackupNameAnnotation name the backup
+	// of a Job, which may be in another namespace
+	BackupNamespaceAnnotation = "etcdguardian.io/backup-namespace"
+	BackupNameAnnotation      = "etcdguardian.io/backup-name"
+
+	// JobNameEnv passes the name of its Job to the container
+	JobNameEnv = "ETCDGUARDIAN_JOB_NAME"
+
+	// ttlSecondsAfterFinished removes Jobs the operator failed to delete
+	ttlSecondsAfterFinished = 3600
+
+	containerName = "snapshot"
+	scratchDir    = "/var/lib/etcdguardian/scratch"
+
+	// nonRootUser is the user of the distroless image of the operator
+	nonRootUser = 65532
+)
+
+// controlPlaneRoles are the node role labels of control-plane nodes, the
+// legacy one for older clusters
+var controlPlaneRoles = []string{
+	"node-role.kubernetes.io/control-plane",
+	"node-role.kubernetes.io/master",
+}
+
+// forwardedEnv are the environment variables of the operator passed on to
+// Jobs: the storage settings the chart sets, which hold no secrets. Static
+// credentials are read from the credentials secrets of storage locations,
+// as the operator does, so that they never appear in Job specs.
+var forwardedEnv = []string{"ALIBABA_CLOUD_ECS_METADATA", "AZURE_STORAGE_ACCOUNT"}
+
+// Options configure the Jobs of backups
+type Options struct {
+	// Namespace Jobs are created in
+	Namespace string
+
+	// Image of the Jobs, an image of the operator
+	Image string
+
+	// ServiceAccountName Jobs run as, which may update the status of
+	// backups and read the credentials of their storage
+	ServiceAccountName string
+
+	// Env is passed on to the container of the Jobs
+	Env []corev1.EnvVar
+
+	// HostNetwork runs Jobs in the network namespace of their node, to
+	// reach etcd members listening on the loopback address only
+	HostNetwork bool
+}
+
+// ForwardedEnv returns the storage settings of the operator environment, to
+// pass on to Jobs
+func ForwardedEnv() []corev1.EnvVar {
+	env := []corev1.EnvVar{}
+	for _, name := range forwardedEnv {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, corev1.EnvVar{Name: name, Value: value})
+		}
+	}
+	return env
+}
+
+// Name returns the name of the Job of the current attempt of a backup. It is
+// unique across the namespaces of backups and short enough for the
+// job-name label of its pods.
+func Name(backup *etcdguardianv1alpha1.EtcdBackup) string {
+	name := backup.Name
+	if len(name) > 40 {
+		name = strings.TrimRight(name[:40], "-.")
+	}
+	uid := string(backup.UID)
+	if len(uid) > 8 {
+		uid = uid[:8]
+	}
+	return fmt.Sprintf("%s-%s-%d", name, uid, max(backup.Status.Attempts, 1))
+}
+
+// BackupKey returns the backup a Job runs for, false for Jobs of no backup
+func BackupKey(job client.Object) (client.ObjectKey, bool) {
+	annotations := job.GetAnnotations()
+	namespace, name := annotations[BackupNamespaceAnnotation], annotations[BackupNameAnnotation]
+	if namespace == "" || name == "" {
+		return client.ObjectKey{}, false
+	}
+	return client.ObjectKey{Namespace: namespace, Name: name}, true
+}
+
+// ValidateTargets checks that Jobs can store snapshots in every destination
+// of a backup. Filesystem and memory destinations would be local to the
+// pod of the Job, and gone with it.
+func ValidateTargets(targets []replication.Target) error {
+	for _, target := range targets {
+		switch target.Location.Provider {
+		case etcdguardianv1alpha1.StorageProviderFilesystem, storage.ProviderMemory:
+			return fmt.Errorf("destination %s: provider %s is not supported in the %s execution mode",
+				target.Name, target.Location.Provider, etcdguardianv1alpha1.ExecutionModeJob)
+		}
+	}
+	return nil
+}
+
+// NewJob returns the Job of the current attempt of a backup. It runs on a
+// control-plane node as a non-root user, reaches etcd with the certificates
+// of the backup, as the operator does, and writes the snapshot to an
+// emptyDir, so that the scratch space does not have to be given to the
+// operator.
+func NewJob(backup *etcdguardianv1alpha1.EtcdBackup, opts Options) *batchv1.Job {
+	execution := backup.Spec.Job
+	if execution == nil {
+		execution = &etcdguardianv1alpha1.JobExecution{}
+	}
+
+	name := Name(backup)
+	labels := map[string]string{
+		"app.kubernetes.io/name":       "etcdguardian",
+		"app.kubernetes.io/component":  "backup-job",
+		"app.kubernetes.io/managed-by": "etcdguardian",
+		BackupUIDLabel:                 string(backup.UID),
+	}
+	annotations := map[string]string{
+		BackupNamespaceAnnotation: backup.Namespace,
+		BackupNameAnnotation:      backup.Name,
+	}
+
+	env := []corev1.EnvVar{
+		{Name: JobNameEnv, Value: name},
+		// The root file system is read-only
+		{Name: "TMPDIR", Value: scratchDir},
+	}
+	env = append(env, opts.Env...)
+
+	// Any of the control-plane role labels selects a node
+	nodeSelectorTerms := []corev1.NodeSelectorTerm{}
+	tolerations := []corev1.Toleration{}
+	for _, role := range controlPlaneRoles {
+		nodeSelectorTerms = append(nodeSelectorTerms, corev1.NodeSelectorTerm{
+			MatchExpressions: []corev1.NodeSelectorRequirement{{Key: role, Operator: corev1.NodeSelectorOpExists}},
+		})
+		tolerations = append(tolerations, corev1.Toleration{Key: role, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule})
+	}
+	tolerations = append(tolerations, execution.Tolerations...)
+
+	backoffLimit := int32(0)
+	ttl := int32(ttlSecondsAfterFinished)
+	runAsUser := int64(nonRootUser)
+	runAsNonRoot := true
+	allowPrivilegeEscalation := false
+	readOnlyRoot := true
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   opts.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: batchv1.JobSpec{
+			// Failed attempts are retried by the controller, with backoff
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels, Annotations: annotations},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: opts.ServiceAccountName,
+					HostNetwork:        opts.HostNetwork,
+					Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: nodeSelectorTerms},
+					}},
+					Tolerations: tolerations,
+					Containers: []corev1.Container{{
+						Name:    containerName,
+						Image:   opts.Image,
+						Command: []string{"/manager"},
+						Args: []string{
+							"--run-backup=" + backup.Namespace + "/" + backup.Name,
+							"--snapshot-dir=" + scratchDir,
+						},
+						Env:       env,
+						Resources: execution.Resources,
+						SecurityContext: &corev1.SecurityContext{
+							RunAsUser:                &runAsUser,
+							RunAsNonRoot:             &runAsNonRoot,
+							AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+							ReadOnlyRootFilesystem:   &readOnlyRoot,
+							Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+						},
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "scratch", MountPath: scratchDir},
+						},
+						TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					}},
+					Volumes: []corev1.Volume{
+						{Name: "scratch", VolumeSource: corev1.VolumeSource{
+							EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: execution.ScratchSizeLimit},
+						}},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Finished reports whether a Job completed or failed, and whether it
+// completed
+func Finished(job *batchv1.Job) (finished, succeeded bool) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return true, true
+		case batchv1.JobFailed:
+			return true, false
+		}
+	}
+	return false, false
+}
+
+// FailureMessage returns why a failed Job failed: the termination message
+// of its container, or the reason of the Job when its pod did not report
+// one, such as when it was evicted or never scheduled
+func FailureMessage(ctx context.Context, reader client.Reader, job *batchv1.Job) string {
+	pods := &corev1.PodList{}
+	if err := reader.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err == nil {
+		for _, pod := range pods.Items {
+			for _, status := range pod.Status.ContainerStatuses {
+				if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+					if message := strings.TrimSpace(terminated.Message); message != "" {
+						return message
+					}
+					return fmt.Sprintf("container exited with code %d (%s)", terminated.ExitCode, terminated.Reason)
+				}
+			}
+		}
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return fmt.Sprintf("%s: %s", condition.Reason, condition.Message)
+		}
+	}
+	return "Job failed"
+}
+
+// Run takes and uploads the snapshot of a backup inside its Job and records
+// the snapshot and the state of every destination in the backup status.
+// Its error is the message the controller fails the attempt with.
+func Run(ctx context.Context, k8sClient client.Client, log logr.Logger, key types.NamespacedName, jobName, dir string) error {
+	backup := &etcdguardianv1alpha1.EtcdBackup{}
+	if err := k8sClient.Get(ctx, key, backup); err != nil {
+		return fmt.Errorf("failed to get backup %s: %w", key, err)
+	}
+	// A Job left over from an earlier attempt must not overwrite the
+	// results of the current one
+	if backup.Status.Phase != etcdguardianv1alpha1.BackupPhasePreparing || backup.Status.Job == nil || backup.Status.Job.Name != jobName {
+		return fmt.Errorf("backup %s is no longer taken by Job %s", key, jobName)
+	}
+
+	engine := snapshot.NewSnapshotEngine(log).WithDir(dir)
+	var snapshotPath string
+	var snapshotSize, etcdRevision int64
+	var err error
+	if backup.Spec.BackupMode == etcdguardianv1alpha1.BackupModeFull {
+		snapshotPath, snapshotSize, etcdRevision, err = engine.TakeFullSnapshot(ctx, backup)
+	} else {
+		snapshotPath, snapshotSize, etcdRevision, err = engine.TakeIncrementalSnapshot(ctx, backup)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to take snapshot: %w", err)
+	}
+
+	// The replicator checkpoints the status of the backup while it uploads
+	uploadErr := replication.NewReplicator(k8sClient, log).Replicate(ctx, backup, snapshotPath)
+	complete := false
+	if uploadErr == nil {
+		complete, uploadErr = replication.Evaluate(backup)
+	}
+	if uploadErr == nil && !complete {
+		uploadErr = fmt.Errorf("replication policy not met")
+	}
+	if uploadErr == nil {
+		backup.Status.SnapshotSize = snapshotSize
+		backup.Status.EtcdRevision = etcdRevision
+		backup.Status.SnapshotLocation = replication.SnapshotLocation(backup)
+	}
+
+	// The state of the destinations is recorded also when the upload
+	// failed, so that the controller fails the backup with their reason
+	if err := writeResults(ctx, k8sClient, backup); err != nil {
+		return fmt.Errorf("failed to record results: %w", err)
+	}
+	if uploadErr != nil {
+		return fmt.Errorf("failed to upload snapshot: %w", uploadErr)
+	}
+	log.Info("Snapshot taken and uploaded", "location", backup.Status.SnapshotLocation, "size", snapshotSize, "revision", etcdRevision)
+	return nil
+}
+
+// writeResults copies the results of a Job onto the latest status of its
+// backup
+func writeResults(ctx context.Context, k8sClient client.Client, results *etcdguardianv1alpha1.EtcdBackup) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		backup := &etcdguardianv1alpha1.EtcdBackup{}
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(results), backup); err != nil {
+			return err
+		}
+		backup.Status.SnapshotSize = results.Status.SnapshotSize
+		backup.Status.EtcdRevision = results.Status.EtcdRevision
+		backup.Status.SnapshotLocation = results.Status.SnapshotLocation
+		backup.Status.Replicas = results.Status.Replicas
+		backup.Status.UploadSessions = results.Status.UploadSessions
+		backup.Status.StorageAttempts = results.Status.StorageAttempts
+		return k8sClient.Status().Update(ctx, backup)
+	})
+}
+
+// WriteTerminationMessage writes the error of a Job where Kubernetes reads
+// the termination message of its container from
+func WriteTerminationMessage(err error) {
+	_ = os.WriteFile(corev1.TerminationMessagePathDefault, []byte(err.Error()), 0o644)
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupjob
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/replication"
+	"github.com/etcdguardian/etcdguardian/pkg/storage"
+)
+
+func TestMain(m *testing.M) {
+	storage.EnableMemoryStorage()
+	os.Exit(m.Run())
+}
+
+func newTestBackup() *etcdguardianv1alpha1.EtcdBackup {
+	return &etcdguardianv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "team-a", UID: "0123456789abcdef"},
+		Spec: etcdguardianv1alpha1.EtcdBackupSpec{
+			BackupMode:      etcdguardianv1alpha1.BackupModeFull,
+			StorageLocation: &etcdguardianv1alpha1.StorageLocation{Provider: storage.ProviderMemory, Bucket: "backupjob"},
+			ExecutionMode:   etcdguardianv1alpha1.ExecutionModeJob,
+		},
+		Status: etcdguardianv1alpha1.EtcdBackupStatus{
+			Phase:    etcdguardianv1alpha1.BackupPhasePreparing,
+			Attempts: 2,
+		},
+	}
+}
+
+func newFakeClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = etcdguardianv1alpha1.AddToScheme(scheme)
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&etcdguardianv1alpha1.EtcdBackup{}).
+		Build()
+}
+
+func TestName(t *testing.T) {
+	backup := newTestBackup()
+	if name := Name(backup); name != "nightly-01234567-2" {
+		t.Errorf("Expected nightly-01234567-2, got %s", name)
+	}
+
+	backup.Name = strings.Repeat("a", 39) + "-" + strings.Repeat("b", 200)
+	if name := Name(backup); len(name) > 63 || strings.Contains(name, "--") {
+		t.Errorf("Expected a valid label value, got %s", name)
+	}
+}
+
+func TestNewJob(t *testing.T) {
+	backup := newTestBackup()
+	limit := resource.MustParse("2Gi")
+	backup.Spec.Job = &etcdguardianv1alpha1.JobExecution{
+		ScratchSizeLimit: &limit,
+		Tolerations:      []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+	}
+	job := NewJob(backup, Options{
+		Namespace:          "etcdguardian-system",
+		Image:              "etcdguardian:test",
+		ServiceAccountName: "etcdguardian",
+		Env:                []corev1.EnvVar{{Name: "AZURE_STORAGE_ACCOUNT", Value: "backups"}},
+		HostNetwork:        true,
+	})
+
+	if job.Namespace != "etcdguardian-system" || job.Name != Name(backup) {
+		t.Errorf("Unexpected Job %s/%s", job.Namespace, job.Name)
+	}
+	if key, ok := BackupKey(job); !ok || key != client.ObjectKeyFromObject(backup) {
+		t.Errorf("Expected the Job to map to its backup, got %v", key)
+	}
+	if *job.Spec.BackoffLimit != 0 {
+		t.Errorf("Expected failed Jobs to be retried by the controller only")
+	}
+
+	pod := job.Spec.Template.Spec
+	if !pod.HostNetwork || pod.ServiceAccountName != "etcdguardian" || pod.RestartPolicy != corev1.RestartPolicyNever {
+		t.Errorf("Unexpected pod spec %+v", pod)
+	}
+	terms := pod.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) != 2 || terms[0].MatchExpressions[0].Key != "node-role.kubernetes.io/control-plane" {
+		t.Errorf("Expected the Job to require a control-plane node, got %+v", terms)
+	}
+	if len(pod.Tolerations) != 3 || pod.Tolerations[2].Key != "dedicated" {
+		t.Errorf("Expected the control-plane and extra tolerations, got %+v", pod.Tolerations)
+	}
+
+	volumes := map[string]corev1.Volume{}
+	for _, volume := range pod.Volumes {
+		volumes[volume.Name] = volume
+		if volume.HostPath != nil {
+			t.Errorf("Expected no hostPath volumes, got %+v", volume)
+		}
+	}
+	if emptyDir := volumes["scratch"].EmptyDir; emptyDir == nil || !emptyDir.SizeLimit.Equal(limit) {
+		t.Errorf("Expected a scratch emptyDir of 2Gi, got %+v", volumes["scratch"])
+	}
+
+	container := pod.Containers[0]
+	if container.Image != "etcdguardian:test" || container.Args[0] != "--run-backup=team-a/nightly" {
+		t.Errorf("Unexpected container %+v", container)
+	}
+	if security := container.SecurityContext; !*security.RunAsNonRoot || *security.RunAsUser == 0 || *security.AllowPrivilegeEscalation {
+		t.Errorf("Expected the Job to run as a non-root user, got %+v", security)
+	}
+	env := map[string]string{}
+	for _, variable := range container.Env {
+		env[variable.Name] = variable.Value
+	}
+	if env[JobNameEnv] != job.Name || env["AZURE_STORAGE_ACCOUNT"] != "backups" {
+		t.Errorf("Unexpected environment %v", env)
+	}
+}
+
+func TestForwardedEnv(t *testing.T) {
+	t.Setenv("AZURE_STORAGE_ACCOUNT", "backups")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("ALIBABA_CLOUD_OIDC_TOKEN_FILE", "/var/run/secrets/tokens/oidc-token")
+
+	env := ForwardedEnv()
+	if len(env) != 1 || env[0].Name != "AZURE_STORAGE_ACCOUNT" || env[0].Value != "backups" {
+		t.Errorf("Expected only the storage account to be forwarded, got %+v", env)
+	}
+}
+
+func TestFinished(t *testing.T) {
+	job := &batchv1.Job{}
+	if finished, _ := Finished(job); finished {
+		t.Errorf("Expected a Job without conditions to run")
+	}
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+	if finished, succeeded := Finished(job); !finished || succeeded {
+		t.Errorf("Expected the Job to have failed")
+	}
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	if finished, succeeded := Finished(job); !finished || !succeeded {
+		t.Errorf("Expected the Job to have completed")
+	}
+}
+
+func TestFailureMessage(t *testing.T) {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly-01234567-1", Namespace: "etcdguardian-system"},
+		Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{{
+			Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded", Message: "Job has reached the specified backoff limit",
+		}}},
+	}
+	if message := FailureMessage(context.Background(), newFakeClient(), job); !strings.HasPrefix(message, "BackoffLimitExceeded") {
+		t.Errorf("Expected the reason of the Job without pods, got %q", message)
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly-01234567-1-x7k2p", Namespace: job.Namespace, Labels: map[string]string{"job-name": job.Name}},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name:  containerName,
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Message: "failed to take snapshot: etcd unreachable\n"}},
+		}}},
+	}
+	if message := FailureMessage(context.Background(), newFakeClient(pod), job); message != "failed to take snapshot: etcd unreachable" {
+		t.Errorf("Expected the termination message, got %q", message)
+	}
+}
+
+func TestValidateTargets(t *testing.T) {
+	backup := newTestBackup()
+	backup.Spec.StorageLocation = &etcdguardianv1alpha1.StorageLocation{Provider: etcdguardianv1alpha1.StorageProviderS3, Bucket: "backups"}
+	targets, err := replication.Targets(context.Background(), nil, backup)
+	if err != nil {
+		t.Fatalf("Failed to resolve destinations: %v", err)
+	}
+	if err := ValidateTargets(targets); err != nil {
+		t.Errorf("Expected S3 to be supported, got %v", err)
+	}
+
+	targets[0].Location.Provider = etcdguardianv1alpha1.StorageProviderFilesystem
+	if err := ValidateTargets(targets); err == nil {
+		t.Errorf("Expected Filesystem to be rejected")
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Cleanup(storage.ResetMemoryStorage)
+	ctx := context.Background()
+	backup := newTestBackup()
+	jobName := Name(backup)
+	backup.Status.Job = &etcdguardianv1alpha1.JobReference{Namespace: "etcdguardian-system", Name: jobName}
+	k8sClient := newFakeClient(backup)
+	key := client.ObjectKeyFromObject(backup)
+
+	if err := Run(ctx, k8sClient, logr.Discard(), key, "nightly-01234567-1", t.TempDir()); err == nil {
+		t.Errorf("Expected a Job of an earlier attempt to stop")
+	}
+
+	if err := Run(ctx, k8sClient, logr.Discard(), key, jobName, t.TempDir()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	result := &etcdguardianv1alpha1.EtcdBackup{}
+	if err := k8sClient.Get(ctx, key, result); err != nil {
+		t.Fatalf("Failed to get backup: %v", err)
+	}
+	if !strings.HasPrefix(result.Status.SnapshotLocation, "memory://backupjob/") || result.Status.SnapshotSize == 0 {
+		t.Errorf("Expected the uploaded snapshot in the status, got %+v", result.Status)
+	}
+	if result.Status.Phase != etcdguardianv1alpha1.BackupPhasePreparing || result.Status.Job.Name != jobName {
+		t.Errorf("Expected the phase to be left to the controller, got %s", result.Status.Phase)
+	}
+
+	t.Run("upload failure", func(t *testing.T) {
+		faults := storage.MemoryFaults("backupjob")
+		faults.Add(storage.Fault{Operations: []storage.FaultOperation{storage.FaultPut}, Err: &storage.InjectedError{Class: storage.ErrorClassAuth}})
+		defer faults.Clear()
+
+		failing := newTestBackup()
+		failing.Name = "hourly"
+		failing.Status.Job = &etcdguardianv1alpha1.JobReference{Namespace: "etcdguardian-system", Name: Name(failing)}
+		k8sClient := newFakeClient(failing)
+		err := Run(ctx, k8sClient, logr.Discard(), client.ObjectKeyFromObject(failing), Name(failing), t.TempDir())
+		if err == nil {
+			t.Fatalf("Expected the upload to fail")
+		}
+
+		result := &etcdguardianv1alpha1.EtcdBackup{}
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(failing), result); err != nil {
+			t.Fatalf("Failed to get backup: %v", err)
+		}
+		if reason := replication.FailureReason(result); reason != "StorageAuthFailed" {
+			t.Errorf("Expected the reason of the destination in the status, got %q", reason)
+		}
+		if result.Status.SnapshotLocation != "" {
+			t.Errorf("Expected no snapshot location, got %s", result.Status.SnapshotLocation)
+		}
+	})
+}
@@ -0,0 +1,283 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/replication"
+	"github.com/etcdguardian/etcdguardian/pkg/storage"
+)
+
+// SyncedFromLabel marks backups recreated from the contents of a storage
+// location, with the name of the location as value. Synced backups are
+// read-only: the backup controller does not reconcile them.
+const SyncedFromLabel = "etcdguardian.io/synced-from"
+
+// SourceNamespaceLabel records the namespace of the backup that took the
+// snapshot of a synced backup
+const SourceNamespaceLabel = "etcdguardian.io/source-namespace"
+
+// ReasonSyncConflict is the reason of the events of storage locations
+// whose snapshots cannot be synced because a backup of the same name exists
+const ReasonSyncConflict = "SyncConflict"
+
+// IsSynced reports whether a backup was recreated from storage contents
+func IsSynced(backup *etcdguardianv1alpha1.EtcdBackup) bool {
+	_, ok := backup.Labels[SyncedFromLabel]
+	return ok
+}
+
+// Entry is a snapshot found in a storage location together with its manifest
+type Entry struct {
+	Manifest         storage.Manifest
+	SnapshotLocation string
+	ManifestLocation string
+}
+
+// Scan lists the snapshots in a storage backend and reads their manifests.
+// Snapshots without a manifest, such as those uploaded before manifests
+// were written, are skipped. Snapshots whose location is in known are
+// skipped without reading their manifest.
+func Scan(ctx context.Context, backend storage.Storage, known map[string]bool) ([]Entry, error) {
+	snapshots, err := backend.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	entries := []Entry{}
+	for _, snapshot := range snapshots {
+		if known[snapshot.Path] {
+			continue
+		}
+
+		manifestLocation := storage.ManifestPath(snapshot.Path)
+		manifest, err := storage.GetManifest(ctx, backend, manifestLocation)
+		if err != nil {
+			if storage.Classify(err) == storage.ErrorClassNotFound {
+				continue
+			}
+			return nil, err
+		}
+
+		entries = append(entries, Entry{
+			Manifest:         *manifest,
+			SnapshotLocation: snapshot.Path,
+			ManifestLocation: manifestLocation,
+		})
+	}
+	return entries, nil
+}
+
+// Name returns the name of the synced backup of a snapshot: the name of the
+// backup that took it, prefixed with its namespace, so that backups of the
+// same name in different namespaces sharing a location do not collide
+func Name(manifest storage.Manifest) string {
+	if manifest.BackupNamespace == "" || manifest.BackupName == "" {
+		return manifest.BackupName
+	}
+	return manifest.BackupNamespace + "." + manifest.BackupName
+}
+
+// NewBackup returns the read-only backup of a snapshot found in a storage
+// location. It lives in the namespace of the location, is named by Name
+// and references the location by name.
+func NewBackup(location *etcdguardianv1alpha1.EtcdBackupStorageLocation, entry Entry) *etcdguardianv1alpha1.EtcdBackup {
+	mode := etcdguardianv1alpha1.BackupMode(entry.Manifest.BackupMode)
+	if mode == "" {
+		mode = etcdguardianv1alpha1.BackupModeFull
+	}
+	created := metav1.NewTime(entry.Manifest.CreationTimestamp)
+	labels := map[string]string{SyncedFromLabel: location.Name}
+	if entry.Manifest.BackupNamespace != "" {
+		labels[SourceNamespaceLabel] = entry.Manifest.BackupNamespace
+	}
+
+	return &etcdguardianv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name(entry.Manifest),
+			Namespace: location.Namespace,
+			Labels:    labels,
+		},
+		Spec: etcdguardianv1alpha1.EtcdBackupSpec{
+			BackupMode:          mode,
+			StorageLocationName: location.Name,
+		},
+		Status: etcdguardianv1alpha1.EtcdBackupStatus{
+			Phase:            etcdguardianv1alpha1.BackupPhaseCompleted,
+			SnapshotSize:     entry.Manifest.Size,
+			SnapshotLocation: entry.SnapshotLocation,
+			EtcdRevision:     entry.Manifest.EtcdRevision,
+			StartTime:        &created,
+			CompletionTime:   &created,
+			Replicas: []etcdguardianv1alpha1.ReplicaStatus{{
+				Name:             replication.PrimaryName,
+				Provider:         location.Spec.Provider,
+				Phase:            etcdguardianv1alpha1.ReplicaPhaseCompleted,
+				Location:         entry.SnapshotLocation,
+				ManifestLocation: entry.ManifestLocation,
+				Hash:             entry.Manifest.SHA256,
+				CompletionTime:   &created,
+			}},
+			Message: fmt.Sprintf("Synced from storage location %s", location.Name),
+		},
+	}
+}
+
+// Syncer recreates the backups of the snapshots in storage locations that
+// have no EtcdBackup, such as after the loss of the cluster that took them
+type Syncer struct {
+	client   client.Client
+	recorder record.EventRecorder
+	log      logr.Logger
+
+	// newStorage creates the storage backend of a location
+	newStorage func(provider etcdguardianv1alpha1.StorageProvider, location etcdguardianv1alpha1.StorageLocation, k8sClient client.Client, namespace string) (storage.Storage, error)
+}
+
+// NewSyncer creates a new catalog syncer. Conflicts with existing backups
+// are recorded as events of the storage location.
+func NewSyncer(k8sClient client.Client, recorder record.EventRecorder, log logr.Logger) *Syncer {
+	return &Syncer{
+		client:     k8sClient,
+		recorder:   recorder,
+		log:        log,
+		newStorage: storage.NewStorage,
+	}
+}
+
+// Sync creates a read-only backup for every snapshot in a storage location
+// whose backup does not exist in the namespace of the location. Snapshots
+// of deleted backups are marked by tombstones and not listed. Locations
+// that failed their last access check are skipped. It returns the number of
+// backups created.
+func (s *Syncer) Sync(ctx context.Context, location *etcdguardianv1alpha1.EtcdBackupStorageLocation) (int, error) {
+	if meta.IsStatusConditionFalse(location.Status.Conditions, etcdguardianv1alpha1.StorageLocationConditionAvailable) {
+		return 0, nil
+	}
+
+	backups := &etcdguardianv1alpha1.EtcdBackupList{}
+	if err := s.client.List(ctx, backups, client.InNamespace(location.Namespace)); err != nil {
+		return 0, fmt.Errorf("failed to list backups: %w", err)
+	}
+	known := map[string]bool{}
+	for _, backup := range backups.Items {
+		for _, status := range backup.Status.Replicas {
+			known[status.Location] = true
+		}
+	}
+
+	backend, err := s.newStorage(location.Spec.Provider, location.Spec.StorageLocation, s.client, location.Namespace)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create storage backend: %w", err)
+	}
+	entries, err := Scan(ctx, backend, known)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	failures := []string{}
+	for _, entry := range entries {
+		backup := NewBackup(location, entry)
+		if backup.Name == "" {
+			continue
+		}
+		ok, err := s.create(ctx, location, backup)
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		if ok {
+			s.log.Info("Synced backup from storage", "backup", backup.Name, "storageLocation", location.Name, "snapshot", entry.SnapshotLocation)
+			created++
+		}
+	}
+	if len(failures) > 0 {
+		return created, fmt.Errorf("failed to sync backups: %s", strings.Join(failures, "; "))
+	}
+	return created, nil
+}
+
+// create creates a synced backup and then sets its status, which is
+// dropped on create. A synced backup left without status by an earlier
+// sync gets it now. A backup of the same name holding another snapshot is
+// left alone and reported as an event of the location. It reports whether
+// the backup was synced.
+func (s *Syncer) create(ctx context.Context, location *etcdguardianv1alpha1.EtcdBackupStorageLocation, backup *etcdguardianv1alpha1.EtcdBackup) (bool, error) {
+	status := backup.Status.DeepCopy()
+	if err := s.client.Create(ctx, backup); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return false, fmt.Errorf("failed to create backup %s: %w", backup.Name, err)
+		}
+		if err := s.client.Get(ctx, client.ObjectKeyFromObject(backup), backup); err != nil {
+			return false, fmt.Errorf("failed to get backup %s: %w", backup.Name, err)
+		}
+		if !IsSynced(backup) || (backup.Status.Phase != "" && backup.Status.SnapshotLocation != status.SnapshotLocation) {
+			s.log.Info("Backup of the same name exists, not syncing snapshot", "backup", backup.Name, "snapshot", status.SnapshotLocation)
+			s.recorder.Eventf(location, corev1.EventTypeWarning, ReasonSyncConflict,
+				"Backup %s already exists, not syncing snapshot %s", backup.Name, status.SnapshotLocation)
+			return false, nil
+		}
+		if backup.Status.Phase != "" {
+			return false, nil
+		}
+	}
+
+	backup.Status = *status
+	if err := s.client.Status().Update(ctx, backup); err != nil {
+		return false, fmt.Errorf("failed to update status of backup %s: %w", backup.Name, err)
+	}
+	return true, nil
+}
+
+// Tombstone marks the stored snapshots of a backup being deleted, so that
+// Sync does not bring the backup back. Destinations whose storage location
+// is gone are skipped.
+func Tombstone(ctx context.Context, k8sClient client.Client, backup *etcdguardianv1alpha1.EtcdBackup) error {
+	for _, status := range backup.Status.Replicas {
+		if status.Phase != etcdguardianv1alpha1.ReplicaPhaseCompleted || status.Location == "" {
+			continue
+		}
+		target, err := replication.Destination(ctx, k8sClient, backup, status.Name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		backend, err := storage.NewStorage(target.Location.Provider, target.Location, k8sClient, backup.Namespace)
+		if err != nil {
+			return fmt.Errorf("destination %s: failed to create storage backend: %w", status.Name, err)
+		}
+		if err := storage.WriteTombstone(ctx, backend, status.Location); err != nil {
+			return fmt.Errorf("destination %s: %w", status.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/storage"
+)
+
+func newFakeClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = etcdguardianv1alpha1.AddToScheme(scheme)
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&etcdguardianv1alpha1.EtcdBackup{}).
+		Build()
+}
+
+// newTestLocation returns a Filesystem storage location in a temporary directory
+func newTestLocation(t *testing.T) *etcdguardianv1alpha1.EtcdBackupStorageLocation {
+	return &etcdguardianv1alpha1.EtcdBackupStorageLocation{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "etcd-guardian-system"},
+		Spec: etcdguardianv1alpha1.EtcdBackupStorageLocationSpec{
+			StorageLocation: etcdguardianv1alpha1.StorageLocation{
+				Provider: etcdguardianv1alpha1.StorageProviderFilesystem,
+				Bucket:   t.TempDir(),
+				Prefix:   "etcd",
+			},
+		},
+	}
+}
+
+// uploadSnapshot stores a snapshot of a backup in a location, with its
+// manifest unless withoutManifest is set, and returns the snapshot location
+func uploadSnapshot(t *testing.T, location *etcdguardianv1alpha1.EtcdBackupStorageLocation, name string, withoutManifest bool) string {
+	t.Helper()
+
+	backup := &etcdguardianv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "production"},
+		Spec:       etcdguardianv1alpha1.EtcdBackupSpec{BackupMode: etcdguardianv1alpha1.BackupModeFull},
+		Status: etcdguardianv1alpha1.EtcdBackupStatus{
+			EtcdRevision: 42,
+			StartTime:    &metav1.Time{Time: time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC)},
+		},
+	}
+	snapshotPath := filepath.Join(t.TempDir(), name+".db")
+	if err := os.WriteFile(snapshotPath, []byte("snapshot of "+name), 0o600); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+
+	backend, err := storage.NewStorage(location.Spec.Provider, location.Spec.StorageLocation, nil, location.Namespace)
+	if err != nil {
+		t.Fatalf("NewStorage failed: %v", err)
+	}
+	snapshotLocation, err := backend.Upload(context.Background(), snapshotPath, backup)
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if withoutManifest {
+		return snapshotLocation
+	}
+
+	manifest, err := storage.NewManifest(backup, snapshotPath)
+	if err != nil {
+		t.Fatalf("NewManifest failed: %v", err)
+	}
+	manifestPath, err := manifest.WriteFile(snapshotPath)
+	if err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+	if _, err := backend.Upload(context.Background(), manifestPath, backup); err != nil {
+		t.Fatalf("Upload of manifest failed: %v", err)
+	}
+	return snapshotLocation
+}
+
+func TestSyncer_RecreatesBackups(t *testing.T) {
+	location := newTestLocation(t)
+	snapshotLocation := uploadSnapshot(t, location, "nightly", false)
+	uploadSnapshot(t, location, "legacy", true)
+	k8sClient := newFakeClient(location)
+	syncer := NewSyncer(k8sClient, record.NewFakeRecorder(10), logr.Discard())
+	ctx := context.Background()
+
+	created, err := syncer.Sync(ctx, location)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if created != 1 {
+		t.Fatalf("Expected 1 backup to be created, got %d", created)
+	}
+
+	backup := &etcdguardianv1alpha1.EtcdBackup{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: location.Namespace, Name: "production.nightly"}, backup); err != nil {
+		t.Fatalf("Failed to get synced backup: %v", err)
+	}
+	if !IsSynced(backup) || backup.Spec.StorageLocationName != "default" || backup.Labels[SourceNamespaceLabel] != "production" {
+		t.Errorf("Expected a read-only backup referencing the location, got %+v", backup.ObjectMeta)
+	}
+	status := backup.Status
+	if status.Phase != etcdguardianv1alpha1.BackupPhaseCompleted || status.SnapshotLocation != snapshotLocation || status.EtcdRevision != 42 {
+		t.Errorf("Unexpected status %+v", status)
+	}
+	if len(status.Replicas) != 1 || status.Replicas[0].Hash == "" || status.Replicas[0].ManifestLocation != storage.ManifestPath(snapshotLocation) {
+		t.Errorf("Unexpected replica status %+v", status.Replicas)
+	}
+	if !status.CompletionTime.Time.Equal(time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the completion time of the manifest, got %s", status.CompletionTime)
+	}
+
+	created, err = syncer.Sync(ctx, location)
+	if err != nil || created != 0 {
+		t.Errorf("Expected a second sync to create nothing, got %d (%v)", created, err)
+	}
+}
+
+func TestSyncer_KeepsExistingBackups(t *testing.T) {
+	location := newTestLocation(t)
+	uploadSnapshot(t, location, "nightly", false)
+	existing := &etcdguardianv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "production.nightly", Namespace: location.Namespace},
+		Spec:       etcdguardianv1alpha1.EtcdBackupSpec{BackupMode: etcdguardianv1alpha1.BackupModeIncremental},
+	}
+	k8sClient := newFakeClient(location, existing)
+	recorder := record.NewFakeRecorder(10)
+	ctx := context.Background()
+
+	created, err := NewSyncer(k8sClient, recorder, logr.Discard()).Sync(ctx, location)
+	if err != nil || created != 0 {
+		t.Fatalf("Expected no backup to be created, got %d (%v)", created, err)
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, ReasonSyncConflict) {
+			t.Errorf("Expected a %s event, got %s", ReasonSyncConflict, event)
+		}
+	default:
+		t.Errorf("Expected the conflict to be reported as an event")
+	}
+
+	backup := &etcdguardianv1alpha1.EtcdBackup{}
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(existing), backup); err != nil {
+		t.Fatalf("Failed to get backup: %v", err)
+	}
+	if IsSynced(backup) || backup.Status.Phase != "" {
+		t.Errorf("Expected the existing backup to be left alone, got %+v", backup)
+	}
+}
+
+func TestSyncer_SkipsUnavailableLocations(t *testing.T) {
+	location := newTestLocation(t)
+	uploadSnapshot(t, location, "nightly", false)
+	meta.SetStatusCondition(&location.Status.Conditions, metav1.Condition{
+		Type:   etcdguardianv1alpha1.StorageLocationConditionAvailable,
+		Status: metav1.ConditionFalse,
+		Reason: "StorageAuthFailed",
+	})
+
+	created, err := NewSyncer(newFakeClient(location), record.NewFakeRecorder(10), logr.Discard()).Sync(context.Background(), location)
+	if err != nil || created != 0 {
+		t.Errorf("Expected an unavailable location to be skipped, got %d (%v)", created, err)
+	}
+}
+
+func TestSyncer_HonoursTombstones(t *testing.T) {
+	location := newTestLocation(t)
+	uploadSnapshot(t, location, "nightly", false)
+	k8sClient := newFakeClient(location)
+	syncer := NewSyncer(k8sClient, record.NewFakeRecorder(10), logr.Discard())
+	ctx := context.Background()
+
+	if created, err := syncer.Sync(ctx, location); err != nil || created != 1 {
+		t.Fatalf("Expected 1 backup to be created, got %d (%v)", created, err)
+	}
+	backup := &etcdguardianv1alpha1.EtcdBackup{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: location.Namespace, Name: "production.nightly"}, backup); err != nil {
+		t.Fatalf("Failed to get synced backup: %v", err)
+	}
+
+	if err := Tombstone(ctx, k8sClient, backup); err != nil {
+		t.Fatalf("Tombstone failed: %v", err)
+	}
+	if err := k8sClient.Delete(ctx, backup); err != nil {
+		t.Fatalf("Failed to delete backup: %v", err)
+	}
+	if created, err := syncer.Sync(ctx, location); err != nil || created != 0 {
+		t.Errorf("Expected the deleted backup not to be synced back, got %d (%v)", created, err)
+	}
+}
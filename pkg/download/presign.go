@@ -0,0 +1,84 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/replication"
+	"github.com/etcdguardian/etcdguardian/pkg/storage"
+)
+
+// PresignURL returns a time-limited URL that downloads the snapshot of a
+// completed backup without credentials, creating the storage backend with
+// the replicator. The snapshot is taken from the named destination, or the
+// first destination holding it that is not archived when destination is
+// empty. Archived snapshots must be rehydrated first.
+func PresignURL(ctx context.Context, replicator *replication.Replicator, backup *etcdguardianv1alpha1.EtcdBackup, destination string, expiry time.Duration) (string, error) {
+	if expiry <= 0 || expiry > storage.MaxPresignExpiry {
+		return "", fmt.Errorf("expiry must be positive and at most %s", storage.MaxPresignExpiry)
+	}
+	if backup.Status.Phase != etcdguardianv1alpha1.BackupPhaseCompleted {
+		return "", fmt.Errorf("backup %s is not completed", backup.Name)
+	}
+	targets, err := replicator.SyncTargets(ctx, backup)
+	if err != nil {
+		return "", err
+	}
+
+	index := -1
+	for i := range targets {
+		status := backup.Status.Replicas[i]
+		if destination != "" {
+			if status.Name == destination {
+				index = i
+				break
+			}
+			continue
+		}
+		if status.Phase == etcdguardianv1alpha1.ReplicaPhaseCompleted && status.Tier != etcdguardianv1alpha1.StorageTierArchive {
+			index = i
+			break
+		}
+	}
+	switch {
+	case index < 0 && destination != "":
+		return "", fmt.Errorf("backup %s has no destination %s", backup.Name, destination)
+	case index < 0:
+		return "", fmt.Errorf("no destination of backup %s holds a snapshot that is not archived", backup.Name)
+	}
+	target, status := targets[index], backup.Status.Replicas[index]
+	if status.Phase != etcdguardianv1alpha1.ReplicaPhaseCompleted {
+		return "", fmt.Errorf("destination %s does not hold the snapshot of backup %s", target.Name, backup.Name)
+	}
+	if status.Tier == etcdguardianv1alpha1.StorageTierArchive {
+		return "", fmt.Errorf("the snapshot in destination %s is archived and must be rehydrated first", target.Name)
+	}
+
+	backend, err := replicator.Backend(backup, target)
+	if err != nil {
+		return "", fmt.Errorf("failed to create storage backend: %w", err)
+	}
+	presigner, ok := backend.(storage.Presigner)
+	if !ok {
+		return "", fmt.Errorf("storage provider %s does not support presigned URLs", target.Location.Provider)
+	}
+	return presigner.PresignURL(ctx, status.Location, expiry)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/replication"
+	"github.com/etcdguardian/etcdguardian/pkg/storage"
+)
+
+// presigningStorage presigns the objects of the Filesystem provider
+type presigningStorage struct {
+	storage.Storage
+}
+
+func (s *presigningStorage) PresignURL(_ context.Context, remotePath string, expiry time.Duration) (string, error) {
+	return fmt.Sprintf("https://signed.example.com/%s?expires=%s", filepath.Base(remotePath), expiry), nil
+}
+
+func TestPresignURL(t *testing.T) {
+	backup := &etcdguardianv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+		Spec: etcdguardianv1alpha1.EtcdBackupSpec{
+			BackupMode: etcdguardianv1alpha1.BackupModeFull,
+			StorageLocation: &etcdguardianv1alpha1.StorageLocation{
+				Provider: etcdguardianv1alpha1.StorageProviderFilesystem,
+				Bucket:   t.TempDir(),
+			},
+			Replicas: []etcdguardianv1alpha1.ReplicaLocation{{
+				Name: "dr-west",
+				StorageLocation: &etcdguardianv1alpha1.StorageLocation{
+					Provider: etcdguardianv1alpha1.StorageProviderFilesystem,
+					Bucket:   t.TempDir(),
+				},
+			}},
+		},
+	}
+	snapshotPath := filepath.Join(t.TempDir(), "etcd-snapshot.db")
+	if err := os.WriteFile(snapshotPath, []byte("snapshot data"), 0o600); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+	replicator := replication.NewReplicator(nil, logr.Discard()).WithStorage(func(provider etcdguardianv1alpha1.StorageProvider, location etcdguardianv1alpha1.StorageLocation, k8sClient client.Client, namespace string) (storage.Storage, error) {
+		backend, err := storage.NewStorage(provider, location, k8sClient, namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &presigningStorage{Storage: backend}, nil
+	})
+	if err := replicator.Replicate(context.Background(), backup, snapshotPath); err != nil {
+		t.Fatalf("Replicate failed: %v", err)
+	}
+	backup.Status.Phase = etcdguardianv1alpha1.BackupPhaseCompleted
+
+	signedURL, err := PresignURL(context.Background(), replicator, backup, "", time.Hour)
+	if err != nil || !strings.HasPrefix(signedURL, "https://signed.example.com/") {
+		t.Fatalf("Expected a presigned URL of the primary, got %q, %v", signedURL, err)
+	}
+	if _, err := PresignURL(context.Background(), replicator, backup, "", 8*24*time.Hour); err == nil {
+		t.Error("Expected error for an expiry over a week")
+	}
+	if _, err := PresignURL(context.Background(), replicator, backup, "missing", time.Hour); err == nil {
+		t.Error("Expected error for an unknown destination")
+	}
+
+	// Archived snapshots cannot be downloaded, other destinations can
+	backup.Status.Replicas[0].Tier = etcdguardianv1alpha1.StorageTierArchive
+	if _, err := PresignURL(context.Background(), replicator, backup, replication.PrimaryName, time.Hour); err == nil {
+		t.Error("Expected error for an archived snapshot")
+	}
+	if _, err := PresignURL(context.Background(), replicator, backup, "", time.Hour); err != nil {
+		t.Errorf("Expected the replica to be presigned: %v", err)
+	}
+
+	backup.Status.Phase = etcdguardianv1alpha1.BackupPhaseFailed
+	if _, err := PresignURL(context.Background(), replicator, backup, "", time.Hour); err == nil {
+		t.Error("Expected error for a failed backup")
+	}
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/replication"
+	"github.com/etcdguardian/etcdguardian/pkg/storage"
+)
+
+// DefaultExpiry is the validity of a presigned URL when none is requested
+const DefaultExpiry = 15 * time.Minute
+
+// Audience is the audience the bearer tokens of requests must be issued
+// for, e.g. with "kubectl create token --audience etcdguardian-download".
+// Tokens of the API server audience are rejected, so the server cannot
+// replay the tokens it receives against the API server.
+const Audience = "etcdguardian-download"
+
+// Subresource is the subresource of EtcdBackups users need the "get" verb
+// on to request download URLs. A presigned URL hands out the snapshot,
+// which holds every secret of the cluster, so reading the EtcdBackup
+// object alone does not grant it.
+const Subresource = "download"
+
+// Response is the body of a successful download URL request
+type Response struct {
+	// URL downloads the snapshot with a plain GET request
+	URL string `json:"url"`
+
+	// ExpiresAt is when the URL stops working
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Path returns the path of the download URL of a backup on the server
+func Path(namespace, name string) string {
+	return fmt.Sprintf("/v1/namespaces/%s/etcdbackups/%s/download-url", namespace, name)
+}
+
+// Server serves presigned download URLs of backup snapshots to users that
+// may get the download subresource of the EtcdBackup. Requests carry a
+// bearer token of the user for the Audience, which is authenticated with a
+// TokenReview and authorized with a SubjectAccessReview, so the server
+// never grants more than the RBAC of the user does.
+type Server struct {
+	client.Client
+	Log logr.Logger
+
+	// Addr is the address the server listens on
+	Addr string
+
+	// CertFile and KeyFile serve TLS. Both are required, since requests
+	// carry bearer tokens and responses carry presigned URLs.
+	CertFile string
+	KeyFile  string
+
+	// Replicator creates the storage backends that presign the snapshots
+	Replicator *replication.Replicator
+}
+
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// Start serves requests until the context is cancelled
+func (s *Server) Start(ctx context.Context) error {
+	if s.CertFile == "" || s.KeyFile == "" {
+		return fmt.Errorf("the download endpoint requires a TLS certificate and key")
+	}
+	server := &http.Server{
+		Addr:              s.Addr,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		err := server.ListenAndServeTLS(s.CertFile, s.KeyFile)
+		if !errors.Is(err, http.ErrServerClosed) {
+			errs <- err
+		}
+		close(errs)
+	}()
+	s.Log.Info("Serving download URLs", "addr", s.Addr)
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+// NeedLeaderElection makes every replica serve requests
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// ServeHTTP handles GET /v1/namespaces/<namespace>/etcdbackups/<name>/download-url
+// with the optional query parameters expiry, a duration, and destination,
+// the name of the destination to download from
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	namespace, name, ok := parsePath(r.URL.Path)
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	expiry := DefaultExpiry
+	if value := r.URL.Query().Get("expiry"); value != "" {
+		var err error
+		if expiry, err = time.ParseDuration(value); err != nil || expiry <= 0 || expiry > storage.MaxPresignExpiry {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("expiry must be a positive duration of at most %s", storage.MaxPresignExpiry))
+			return
+		}
+	}
+
+	ctx := r.Context()
+	user, err := s.authenticate(ctx, r)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if err := s.authorize(ctx, user, namespace, name); err != nil {
+		writeError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	log := s.Log.WithValues("user", user.Username, "etcdbackup", namespace+"/"+name)
+
+	backup := &etcdguardianv1alpha1.EtcdBackup{}
+	if err := s.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, backup); err != nil {
+		if apierrors.IsNotFound(err) {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("backup %s/%s not found", namespace, name))
+			return
+		}
+		log.Error(err, "Failed to get backup")
+		writeError(w, http.StatusInternalServerError, "failed to get backup")
+		return
+	}
+	if backup.Status.Phase != etcdguardianv1alpha1.BackupPhaseCompleted {
+		writeError(w, http.StatusConflict, fmt.Sprintf("backup %s/%s is not completed", namespace, name))
+		return
+	}
+
+	expiresAt := time.Now().Add(expiry).UTC().Truncate(time.Second)
+	signedURL, err := PresignURL(ctx, s.Replicator, backup, r.URL.Query().Get("destination"), expiry)
+	if err != nil {
+		log.Error(err, "Failed to presign snapshot")
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	// The URL is a credential of its own and is deliberately not logged
+	log.Info("Issued download URL", "expiresAt", expiresAt)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(Response{URL: signedURL, ExpiresAt: expiresAt})
+}
+
+// authenticate reviews the bearer token of a request
+func (s *Server) authenticate(ctx context.Context, r *http.Request) (authenticationv1.UserInfo, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return authenticationv1.UserInfo{}, fmt.Errorf("a bearer token is required")
+	}
+
+	review := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token, Audiences: []string{Audience}}}
+	if err := s.Create(ctx, review); err != nil {
+		s.Log.Error(err, "Failed to review token")
+		return authenticationv1.UserInfo{}, fmt.Errorf("failed to review token")
+	}
+	if !review.Status.Authenticated {
+		return authenticationv1.UserInfo{}, fmt.Errorf("invalid bearer token")
+	}
+	if !slices.Contains(review.Status.Audiences, Audience) {
+		return authenticationv1.UserInfo{}, fmt.Errorf("the bearer token is not issued for the %s audience", Audience)
+	}
+	return review.Status.User, nil
+}
+
+// authorize checks that a user may get the download subresource of the
+// backup
+func (s *Server) authorize(ctx context.Context, user authenticationv1.UserInfo, namespace, name string) error {
+	extra := map[string]authorizationv1.ExtraValue{}
+	for key, value := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   namespace,
+				Verb:        "get",
+				Group:       etcdguardianv1alpha1.GroupVersion.Group,
+				Resource:    "etcdbackups",
+				Subresource: Subresource,
+				Name:        name,
+			},
+		},
+	}
+	if err := s.Create(ctx, review); err != nil {
+		s.Log.Error(err, "Failed to review access")
+		return fmt.Errorf("failed to review access")
+	}
+	if !review.Status.Allowed {
+		return fmt.Errorf("user %s cannot get etcdbackups/%s %s in namespace %s", user.Username, Subresource, name, namespace)
+	}
+	return nil
+}
+
+// parsePath returns the namespace and name of a download URL path
+func parsePath(path string) (string, string, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 6 || parts[0] != "v1" || parts[1] != "namespaces" || parts[3] != "etcdbackups" || parts[5] != "download-url" {
+		return "", "", false
+	}
+	if parts[2] == "" || parts[4] == "" {
+		return "", "", false
+	}
+	return parts[2], parts[4], true
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/replication"
+)
+
+// newTestServer returns a server whose API server knows the token "alice"
+// for the download audience and "alice-apiserver" for its own audience.
+// Alice may only get the download subresource of backups in namespace
+// "team-a", while "bob" may only get the backups themselves
+func newTestServer(objects ...client.Object) *Server {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = authenticationv1.AddToScheme(scheme)
+	_ = authorizationv1.AddToScheme(scheme)
+	_ = etcdguardianv1alpha1.AddToScheme(scheme)
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			switch review := obj.(type) {
+			case *authenticationv1.TokenReview:
+				audience := map[string]string{"alice": Audience, "alice-apiserver": "https://kubernetes.default.svc", "bob": Audience}[review.Spec.Token]
+				if audience != "" && (len(review.Spec.Audiences) == 0 || slices.Contains(review.Spec.Audiences, audience)) {
+					review.Status.Authenticated = true
+					review.Status.Audiences = []string{audience}
+					review.Status.User = authenticationv1.UserInfo{Username: strings.TrimSuffix(review.Spec.Token, "-apiserver"), Groups: []string{"system:authenticated"}}
+				}
+				return nil
+			case *authorizationv1.SubjectAccessReview:
+				attributes := review.Spec.ResourceAttributes
+				subresource := map[string]string{"alice": Subresource, "bob": ""}
+				expected, ok := subresource[review.Spec.User]
+				review.Status.Allowed = ok && attributes.Verb == "get" && attributes.Resource == "etcdbackups" &&
+					attributes.Subresource == expected && attributes.Namespace == "team-a"
+				return nil
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+	}).Build()
+
+	return &Server{
+		Client:     k8sClient,
+		Log:        logr.Discard(),
+		Replicator: replication.NewReplicator(k8sClient, logr.Discard()),
+	}
+}
+
+// ossBackup returns a backup whose snapshot is stored in OSS, which signs
+// URLs without contacting the service
+func ossBackup(namespace, name string, phase etcdguardianv1alpha1.BackupPhase) *etcdguardianv1alpha1.EtcdBackup {
+	return &etcdguardianv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: etcdguardianv1alpha1.EtcdBackupSpec{
+			StorageLocation: &etcdguardianv1alpha1.StorageLocation{
+				Provider:          etcdguardianv1alpha1.StorageProviderOSS,
+				Bucket:            "backups",
+				Prefix:            "etcd",
+				Endpoint:          "https://oss-cn-hangzhou.aliyuncs.com",
+				CredentialsSecret: "oss-credentials",
+			},
+		},
+		Status: etcdguardianv1alpha1.EtcdBackupStatus{
+			Phase: phase,
+			Replicas: []etcdguardianv1alpha1.ReplicaStatus{{
+				Name:     replication.PrimaryName,
+				Phase:    etcdguardianv1alpha1.ReplicaPhaseCompleted,
+				Location: "oss://backups/etcd/" + namespace + "/" + name + "/etcd-snapshot.db",
+			}},
+		},
+	}
+}
+
+func get(server *Server, path, token string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestServer(t *testing.T) {
+	server := newTestServer(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "oss-credentials", Namespace: "team-a"},
+			Data:       map[string][]byte{"access-key-id": []byte("test-ak"), "access-key-secret": []byte("test-sk")},
+		},
+		ossBackup("team-a", "nightly", etcdguardianv1alpha1.BackupPhaseCompleted),
+		ossBackup("team-a", "running", etcdguardianv1alpha1.BackupPhaseUploading),
+	)
+
+	recorder := get(server, Path("team-a", "nightly")+"?expiry=1h", "alice")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected a download URL, got %d: %s", recorder.Code, recorder.Body)
+	}
+	response := Response{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid response: %v", err)
+	}
+	if !strings.Contains(response.URL, "etcd-snapshot.db") || !strings.Contains(response.URL, "Signature=") || response.ExpiresAt.IsZero() {
+		t.Errorf("Expected a presigned URL of the snapshot, got %+v", response)
+	}
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		token string
+		code  int
+	}{
+		{name: "no token", path: Path("team-a", "nightly"), code: http.StatusUnauthorized},
+		{name: "invalid token", path: Path("team-a", "nightly"), token: "mallory", code: http.StatusUnauthorized},
+		{name: "API server audience", path: Path("team-a", "nightly"), token: "alice-apiserver", code: http.StatusUnauthorized},
+		{name: "other backup", path: Path("team-b", "nightly"), token: "alice", code: http.StatusForbidden},
+		{name: "get without download", path: Path("team-a", "nightly"), token: "bob", code: http.StatusForbidden},
+		{name: "backup not completed", path: Path("team-a", "running"), token: "alice", code: http.StatusConflict},
+		{name: "backup not found", path: Path("team-a", "missing"), token: "alice", code: http.StatusNotFound},
+		{name: "expiry too long", path: Path("team-a", "nightly") + "?expiry=200h", token: "alice", code: http.StatusBadRequest},
+		{name: "unknown path", path: "/v1/namespaces/team-a/etcdbackups", token: "alice", code: http.StatusNotFound},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if recorder := get(server, tc.path, tc.token); recorder.Code != tc.code {
+				t.Errorf("Expected status %d, got %d: %s", tc.code, recorder.Code, recorder.Body)
+			}
+		})
+	}
+}
+
+func TestServer_StartRequiresTLS(t *testing.T) {
+	server := newTestServer()
+	server.Addr = "127.0.0.1:0"
+	if err := server.Start(context.Background()); err == nil || !strings.Contains(err.Error(), "TLS") {
+		t.Errorf("Expected the server to refuse plain HTTP, got %v", err)
+	}
+}
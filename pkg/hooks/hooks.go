@@ -0,0 +1,267 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilexec "k8s.io/client-go/util/exec"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+)
+
+// DefaultExecTimeout bounds exec hooks without a timeout
+const DefaultExecTimeout = 30 * time.Second
+
+// maxOutput is how much of the end of the output of a command is recorded
+const maxOutput = 2048
+
+// Executor runs a command in a container of a pod
+type Executor interface {
+	// Exec runs command and writes its output to stdout and stderr. A
+	// command exiting with a non-zero code returns a
+	// k8s.io/client-go/util/exec.ExitError.
+	Exec(ctx context.Context, namespace, pod, container string, command []string, stdout, stderr io.Writer) error
+}
+
+// Runner runs the hooks of backups and restores
+type Runner struct {
+	reader     client.Reader
+	executor   Executor
+	httpClient *http.Client
+	log        logr.Logger
+
+	// retryDelay is the delay before the first retry of an HTTP hook
+	retryDelay time.Duration
+}
+
+// NewRunner creates a hook runner that selects pods and reads secrets
+// through reader and runs exec hooks through executor
+func NewRunner(reader client.Reader, executor Executor, log logr.Logger) *Runner {
+	return &Runner{
+		reader:     reader,
+		executor:   executor,
+		httpClient: newHTTPClient(),
+		log:        log,
+		retryDelay: defaultHTTPRetryDelay,
+	}
+}
+
+// Run runs hooks in order for the operation of a backup or restore. It
+// returns the results of every hook run, and stops at the first hook that
+// fails with the Fail error mode, returning its error.
+func (r *Runner) Run(ctx context.Context, object client.Object, stage etcdguardianv1alpha1.HookStage, hooks []etcdguardianv1alpha1.Hook) ([]etcdguardianv1alpha1.HookResult, error) {
+	results := []etcdguardianv1alpha1.HookResult{}
+	for _, hook := range hooks {
+		hookResults, err := r.runHook(ctx, object, stage, hook)
+		results = append(results, hookResults...)
+		if err == nil {
+			continue
+		}
+		if hook.OnError == etcdguardianv1alpha1.HookErrorModeContinue {
+			r.log.Info("Hook failed, continuing", "hook", hook.Name, "stage", stage, "error", err.Error())
+			continue
+		}
+		return results, fmt.Errorf("%s hook %s failed: %w", stage, hook.Name, err)
+	}
+	return results, nil
+}
+
+// StageRan reports whether hooks of a stage ran, given the results of the
+// hooks of an operation
+func StageRan(results []etcdguardianv1alpha1.HookResult, stage etcdguardianv1alpha1.HookStage) bool {
+	for _, result := range results {
+		if result.Stage == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// StageRanAfter reports whether hooks of a stage ran after the last run of
+// the hooks of another stage, or at all if those never ran. Retried
+// operations run their hooks again, so that post hooks resume what the pre
+// hooks of the latest attempt paused.
+func StageRanAfter(results []etcdguardianv1alpha1.HookResult, stage, after etcdguardianv1alpha1.HookStage) bool {
+	ran := false
+	for _, result := range results {
+		switch result.Stage {
+		case after:
+			ran = false
+		case stage:
+			ran = true
+		}
+	}
+	return ran
+}
+
+// runHook runs one hook
+func (r *Runner) runHook(ctx context.Context, object client.Object, stage etcdguardianv1alpha1.HookStage, hook etcdguardianv1alpha1.Hook) ([]etcdguardianv1alpha1.HookResult, error) {
+	failed := func(err error) ([]etcdguardianv1alpha1.HookResult, error) {
+		now := metav1.Now()
+		return []etcdguardianv1alpha1.HookResult{{
+			Name:           hook.Name,
+			Stage:          stage,
+			Message:        err.Error(),
+			StartTime:      &now,
+			CompletionTime: &now,
+		}}, err
+	}
+
+	switch {
+	case hook.Type == etcdguardianv1alpha1.HookTypeExec || (hook.Type == "" && hook.Exec != nil):
+		if hook.Exec == nil {
+			return failed(fmt.Errorf("exec hook has no exec settings"))
+		}
+		if r.executor == nil {
+			return failed(fmt.Errorf("no executor to run exec hooks with"))
+		}
+		pods, err := r.selectPods(ctx, object.GetNamespace(), hook.Exec)
+		if err != nil {
+			return failed(err)
+		}
+		results := []etcdguardianv1alpha1.HookResult{}
+		for i := range pods {
+			result, err := r.exec(ctx, stage, hook, &pods[i])
+			results = append(results, result)
+			if err != nil {
+				return results, err
+			}
+		}
+		return results, nil
+	case hook.Type == etcdguardianv1alpha1.HookTypeHTTP || (hook.Type == "" && hook.HTTP != nil):
+		if hook.HTTP == nil {
+			return failed(fmt.Errorf("HTTP hook has no http settings"))
+		}
+		result, err := r.request(ctx, object, stage, hook)
+		return []etcdguardianv1alpha1.HookResult{result}, err
+	default:
+		return failed(fmt.Errorf("unsupported hook type %q", hook.Type))
+	}
+}
+
+// selectPods returns the running pods an exec hook selects
+func (r *Runner) selectPods(ctx context.Context, namespace string, hook *etcdguardianv1alpha1.ExecHook) ([]corev1.Pod, error) {
+	if hook.PodSelector == nil {
+		return nil, fmt.Errorf("exec hook has no pod selector")
+	}
+	if len(hook.Command) == 0 {
+		return nil, fmt.Errorf("exec hook has no command")
+	}
+	selector, err := metav1.LabelSelectorAsSelector(hook.PodSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pod selector: %w", err)
+	}
+
+	list := &corev1.PodList{}
+	if err := r.reader.List(ctx, list, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	pods := []corev1.Pod{}
+	for _, pod := range list.Items {
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+			pods = append(pods, pod)
+		}
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("no running pod in namespace %s matches selector %s", namespace, selector)
+	}
+	return pods, nil
+}
+
+// exec runs the command of an exec hook in a pod
+func (r *Runner) exec(ctx context.Context, stage etcdguardianv1alpha1.HookStage, hook etcdguardianv1alpha1.Hook, pod *corev1.Pod) (etcdguardianv1alpha1.HookResult, error) {
+	container := hook.Exec.Container
+	if container == "" && len(pod.Spec.Containers) > 0 {
+		container = pod.Spec.Containers[0].Name
+	}
+	start := metav1.Now()
+	result := etcdguardianv1alpha1.HookResult{
+		Name:      hook.Name,
+		Stage:     stage,
+		Pod:       pod.Namespace + "/" + pod.Name,
+		Container: container,
+		StartTime: &start,
+	}
+
+	timeout := DefaultExecTimeout
+	if hook.Exec.Timeout != nil {
+		timeout = hook.Exec.Timeout.Duration
+	}
+	execCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	r.log.Info("Running exec hook", "hook", hook.Name, "stage", stage, "pod", result.Pod, "container", container)
+	output := &tailBuffer{}
+	err := r.executor.Exec(execCtx, pod.Namespace, pod.Name, container, hook.Exec.Command, output, output)
+
+	end := metav1.Now()
+	result.CompletionTime = &end
+	result.Output = output.String()
+
+	var exitErr utilexec.ExitError
+	switch {
+	case err == nil:
+		result.Succeeded = true
+		result.ExitCode = new(int32)
+	case execCtx.Err() != nil && ctx.Err() == nil:
+		err = fmt.Errorf("command timed out after %s in pod %s", timeout, result.Pod)
+	case errors.As(err, &exitErr):
+		code := int32(exitErr.ExitStatus())
+		result.ExitCode = &code
+		err = fmt.Errorf("command exited with code %d in pod %s", code, result.Pod)
+	default:
+		err = fmt.Errorf("failed to run command in pod %s: %w", result.Pod, err)
+	}
+	if err != nil {
+		result.Message = err.Error()
+	}
+	return result, err
+}
+
+// tailBuffer keeps the end of what is written to it. Standard output and
+// error are written to it concurrently.
+type tailBuffer struct {
+	mu   sync.Mutex
+	data []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.data = append(b.data, p...)
+	if len(b.data) > maxOutput {
+		b.data = append(b.data[:0], b.data[len(b.data)-maxOutput:]...)
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.data)
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	utilexec "k8s.io/client-go/util/exec"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+)
+
+// fakeExecutor records the commands run and answers them with the exit code
+// configured for the pod
+type fakeExecutor struct {
+	calls     []string
+	exitCodes map[string]int
+	hang      bool
+}
+
+func (f *fakeExecutor) Exec(ctx context.Context, namespace, pod, container string, command []string, stdout, stderr io.Writer) error {
+	f.calls = append(f.calls, fmt.Sprintf("%s/%s/%s: %s", namespace, pod, container, strings.Join(command, " ")))
+	if f.hang {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	fmt.Fprintf(stdout, "ran %s\n", command[0])
+	if code := f.exitCodes[pod]; code != 0 {
+		fmt.Fprintf(stderr, "error in %s\n", pod)
+		return utilexec.CodeExitError{Err: fmt.Errorf("command terminated with exit code %d", code), Code: code}
+	}
+	return nil
+}
+
+func newTestPod(name string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "api"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "server", Image: "api"},
+			{Name: "sidecar", Image: "proxy"},
+		}},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+// testBackup is the backup the hooks of the tests run for
+var testBackup = &etcdguardianv1alpha1.EtcdBackup{
+	ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+	Spec:       etcdguardianv1alpha1.EtcdBackupSpec{BackupMode: etcdguardianv1alpha1.BackupModeFull},
+	Status:     etcdguardianv1alpha1.EtcdBackupStatus{Phase: etcdguardianv1alpha1.BackupPhaseValidating},
+}
+
+func newTestRunner(executor Executor, objects ...client.Object) *Runner {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	objects = append(objects,
+		newTestPod("api-0", corev1.PodRunning),
+		newTestPod("api-1", corev1.PodRunning),
+		newTestPod("api-2", corev1.PodPending),
+	)
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	runner := NewRunner(k8sClient, executor, logr.Discard())
+	// The fake webhooks listen on the loopback address
+	runner.httpClient = &http.Client{}
+	return runner
+}
+
+func execHook(name string, command ...string) etcdguardianv1alpha1.Hook {
+	return etcdguardianv1alpha1.Hook{
+		Name: name,
+		Type: etcdguardianv1alpha1.HookTypeExec,
+		Exec: &etcdguardianv1alpha1.ExecHook{
+			PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+			Command:     command,
+		},
+	}
+}
+
+func TestRunner_Exec(t *testing.T) {
+	executor := &fakeExecutor{}
+	runner := newTestRunner(executor)
+
+	hook := execHook("flush", "sync")
+	hook.Exec.Container = "sidecar"
+	results, err := runner.Run(context.Background(), testBackup, etcdguardianv1alpha1.HookStagePreBackup, []etcdguardianv1alpha1.Hook{hook, execHook("freeze", "fsfreeze", "-f")})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	// Pending pods are skipped and the first container is the default
+	expected := []string{
+		"default/api-0/sidecar: sync",
+		"default/api-1/sidecar: sync",
+		"default/api-0/server: fsfreeze -f",
+		"default/api-1/server: fsfreeze -f",
+	}
+	if strings.Join(executor.calls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected commands:\n%s", strings.Join(executor.calls, "\n"))
+	}
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 results, got %+v", results)
+	}
+	for _, result := range results {
+		if !result.Succeeded || result.ExitCode == nil || *result.ExitCode != 0 || result.Stage != etcdguardianv1alpha1.HookStagePreBackup {
+			t.Errorf("Unexpected result %+v", result)
+		}
+	}
+	if results[2].Output != "ran fsfreeze\n" || results[2].Pod != "default/api-0" {
+		t.Errorf("Unexpected result %+v", results[2])
+	}
+}
+
+func TestRunner_OnError(t *testing.T) {
+	executor := &fakeExecutor{exitCodes: map[string]int{"api-0": 3}}
+	runner := newTestRunner(executor)
+	ctx := context.Background()
+
+	// Continue records the failure and runs the next hooks
+	tolerated := execHook("flush", "sync")
+	tolerated.OnError = etcdguardianv1alpha1.HookErrorModeContinue
+	results, err := runner.Run(ctx, testBackup, etcdguardianv1alpha1.HookStagePostBackup, []etcdguardianv1alpha1.Hook{tolerated, execHook("notify", "true")})
+	if err == nil {
+		t.Fatalf("Expected the notify hook to fail")
+	}
+	if len(results) != 2 || results[0].Succeeded || *results[0].ExitCode != 3 || results[0].Output != "ran sync\nerror in api-0\n" {
+		t.Errorf("Unexpected results %+v", results)
+	}
+	if results[1].Name != "notify" || results[1].Succeeded {
+		t.Errorf("Expected the notify hook to fail, got %+v", results[1])
+	}
+
+	// Fail stops at the first failure
+	executor.calls = nil
+	if _, err := runner.Run(ctx, testBackup, etcdguardianv1alpha1.HookStagePreBackup, []etcdguardianv1alpha1.Hook{execHook("flush", "sync"), execHook("freeze", "fsfreeze")}); err == nil {
+		t.Errorf("Expected the flush hook to fail")
+	}
+	if len(executor.calls) != 1 {
+		t.Errorf("Expected the hooks to stop at the failure, ran %v", executor.calls)
+	}
+
+	// Selectors matching no running pod fail
+	unmatched := execHook("flush", "sync")
+	unmatched.Exec.PodSelector.MatchLabels["app"] = "web"
+	results, err = runner.Run(ctx, testBackup, etcdguardianv1alpha1.HookStagePreBackup, []etcdguardianv1alpha1.Hook{unmatched})
+	if err == nil || len(results) != 1 || !strings.Contains(results[0].Message, "no running pod") {
+		t.Errorf("Expected no pod to match, got %+v, %v", results, err)
+	}
+}
+
+func TestRunner_Timeout(t *testing.T) {
+	runner := newTestRunner(&fakeExecutor{hang: true})
+
+	hook := execHook("freeze", "fsfreeze")
+	hook.Exec.Timeout = &metav1.Duration{Duration: 10 * time.Millisecond}
+	results, err := runner.Run(context.Background(), testBackup, etcdguardianv1alpha1.HookStagePreBackup, []etcdguardianv1alpha1.Hook{hook})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("Expected the hook to time out, got %v", err)
+	}
+	if len(results) != 1 || results[0].ExitCode != nil {
+		t.Errorf("Expected no exit code, got %+v", results)
+	}
+}
+
+func TestStageRanAfter(t *testing.T) {
+	pre := etcdguardianv1alpha1.HookResult{Stage: etcdguardianv1alpha1.HookStagePreBackup}
+	post := etcdguardianv1alpha1.HookResult{Stage: etcdguardianv1alpha1.HookStagePostBackup}
+	tests := []struct {
+		name    string
+		results []etcdguardianv1alpha1.HookResult
+		ran     bool
+	}{
+		{name: "none"},
+		{name: "post only", results: []etcdguardianv1alpha1.HookResult{post}, ran: true},
+		{name: "pre only", results: []etcdguardianv1alpha1.HookResult{pre}},
+		{name: "post after pre", results: []etcdguardianv1alpha1.HookResult{pre, pre, post}, ran: true},
+		{name: "retried", results: []etcdguardianv1alpha1.HookResult{pre, post, pre}},
+	}
+	for _, tt := range tests {
+		if ran := StageRanAfter(tt.results, etcdguardianv1alpha1.HookStagePostBackup, etcdguardianv1alpha1.HookStagePreBackup); ran != tt.ran {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.ran, ran)
+		}
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	buffer := &tailBuffer{}
+	fmt.Fprint(buffer, strings.Repeat("a", maxOutput))
+	fmt.Fprint(buffer, "end")
+	if got := buffer.String(); len(got) != maxOutput || !strings.HasSuffix(got, "aend") {
+		t.Errorf("Expected the end of the output, got %d bytes", len(got))
+	}
+}
@@ -0,0 +1,295 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"text/template"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+)
+
+// DefaultHTTPTimeout bounds each attempt of HTTP hooks without a timeout
+const DefaultHTTPTimeout = 30 * time.Second
+
+const (
+	// defaultHTTPRetryDelay is the delay before the first retry of an HTTP
+	// hook, doubled for every further retry
+	defaultHTTPRetryDelay = time.Second
+
+	// maxHTTPRetryDelay caps the delay between retries of an HTTP hook
+	maxHTTPRetryDelay = 30 * time.Second
+
+	// maxDrainedBody is how much of a response body is read, and
+	// discarded, to reuse the connection
+	maxDrainedBody = 64 << 10
+)
+
+// errBlockedAddress is the error of HTTP hooks to addresses they may not
+// reach
+var errBlockedAddress = errors.New("address not allowed for HTTP hooks")
+
+// blockedNetworks are networks HTTP hooks may not reach besides loopback,
+// link-local and multicast addresses: the instance metadata services of
+// Alibaba Cloud and of AWS over IPv6. Requests are sent with the network
+// identity of the operator, which these services hand credentials to.
+var blockedNetworks = []*net.IPNet{
+	mustParseCIDR("100.100.100.200/32"),
+	mustParseCIDR("fd00:ec2::254/128"),
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
+
+// addressAllowed reports whether HTTP hooks may connect to an IP address.
+// Link-local addresses include the metadata services of AWS, GCP and Azure.
+func addressAllowed(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// newHTTPClient returns the client of HTTP hooks. It checks the address it
+// dials rather than the URL, so that names resolving to blocked addresses
+// and redirects to them are refused too, and ignores proxies, which would
+// dial on its behalf.
+func newHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: DefaultHTTPTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !addressAllowed(ip) {
+				return fmt.Errorf("%w: %s", errBlockedAddress, host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Transport: transport}
+}
+
+// TemplateData is what the body template of an HTTP hook is executed with
+type TemplateData struct {
+	Kind      string                         `json:"kind"`
+	Name      string                         `json:"name"`
+	Namespace string                         `json:"namespace"`
+	Stage     etcdguardianv1alpha1.HookStage `json:"stage"`
+	Phase     string                         `json:"phase"`
+
+	// Object is the EtcdBackup or EtcdRestore
+	Object client.Object `json:"-"`
+}
+
+// newTemplateData describes the operation of a backup or restore at a stage
+func newTemplateData(object client.Object, stage etcdguardianv1alpha1.HookStage) TemplateData {
+	data := TemplateData{
+		Name:      object.GetName(),
+		Namespace: object.GetNamespace(),
+		Stage:     stage,
+		Object:    object,
+	}
+	switch o := object.(type) {
+	case *etcdguardianv1alpha1.EtcdBackup:
+		data.Kind = "EtcdBackup"
+		data.Phase = string(o.Status.Phase)
+	case *etcdguardianv1alpha1.EtcdRestore:
+		data.Kind = "EtcdRestore"
+		data.Phase = string(o.Status.Phase)
+	}
+	return data
+}
+
+// request sends the request of an HTTP hook, retrying connection errors,
+// 429 and 5xx responses
+func (r *Runner) request(ctx context.Context, object client.Object, stage etcdguardianv1alpha1.HookStage, hook etcdguardianv1alpha1.Hook) (etcdguardianv1alpha1.HookResult, error) {
+	start := metav1.Now()
+	result := etcdguardianv1alpha1.HookResult{
+		Name:      hook.Name,
+		Stage:     stage,
+		StartTime: &start,
+	}
+	finish := func(err error) (etcdguardianv1alpha1.HookResult, error) {
+		end := metav1.Now()
+		result.CompletionTime = &end
+		if err != nil {
+			result.Message = err.Error()
+		} else {
+			result.Succeeded = true
+		}
+		return result, err
+	}
+
+	spec := hook.HTTP
+	method := spec.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	body, err := requestBody(spec, newTemplateData(object, stage))
+	if err != nil {
+		return finish(err)
+	}
+	header, err := r.requestHeader(ctx, object.GetNamespace(), spec)
+	if err != nil {
+		return finish(err)
+	}
+	timeout := DefaultHTTPTimeout
+	if spec.Timeout != nil {
+		timeout = spec.Timeout.Duration
+	}
+
+	r.log.Info("Running HTTP hook", "hook", hook.Name, "stage", stage, "method", method, "url", spec.URL)
+	delay := r.retryDelay
+	for {
+		result.Attempts++
+		statusCode, err := r.send(ctx, method, spec.URL, header, body, timeout)
+		result.StatusCode = int32(statusCode)
+
+		retryable := err != nil || statusCode == http.StatusTooManyRequests || statusCode >= 500
+		if err == nil && expectedStatus(spec, statusCode) {
+			return finish(nil)
+		}
+		if err == nil {
+			err = fmt.Errorf("%s %s returned unexpected status %d", method, spec.URL, statusCode)
+		}
+		if !retryable || result.Attempts > spec.Retries {
+			return finish(err)
+		}
+
+		r.log.Info("HTTP hook failed, retrying", "hook", hook.Name, "attempt", result.Attempts, "error", err.Error())
+		select {
+		case <-ctx.Done():
+			return finish(ctx.Err())
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > maxHTTPRetryDelay {
+			delay = maxHTTPRetryDelay
+		}
+	}
+}
+
+// send sends one attempt of the request of an HTTP hook and returns the
+// status code. The response body is not recorded: the status of backups
+// and restores is readable by their authors, who choose the URL.
+func (r *Runner) send(ctx context.Context, method, url string, header http.Header, body []byte, timeout time.Duration) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("invalid request: %w", err)
+	}
+	req.Header = header.Clone()
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainedBody))
+	return resp.StatusCode, nil
+}
+
+// requestBody renders the body template of an HTTP hook, or a JSON object
+// of the template fields if it has none
+func requestBody(spec *etcdguardianv1alpha1.HTTPHook, data TemplateData) ([]byte, error) {
+	if spec.Body == "" {
+		return json.Marshal(data)
+	}
+	tmpl, err := template.New("body").Option("missingkey=error").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			encoded, err := json.Marshal(v)
+			return string(encoded), err
+		},
+	}).Parse(spec.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid body template: %w", err)
+	}
+	body := &bytes.Buffer{}
+	if err := tmpl.Execute(body, data); err != nil {
+		return nil, fmt.Errorf("failed to render body template: %w", err)
+	}
+	return body.Bytes(), nil
+}
+
+// requestHeader builds the headers of an HTTP hook, reading the values kept
+// in secrets
+func (r *Runner) requestHeader(ctx context.Context, namespace string, spec *etcdguardianv1alpha1.HTTPHook) (http.Header, error) {
+	header := http.Header{}
+	if spec.Body == "" {
+		header.Set("Content-Type", "application/json")
+	}
+	for _, h := range spec.Headers {
+		value := h.Value
+		if ref := h.ValueFrom; ref != nil {
+			secret := &corev1.Secret{}
+			if err := r.reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+				return nil, fmt.Errorf("failed to get secret %s of header %s: %w", ref.Name, h.Name, err)
+			}
+			data, ok := secret.Data[ref.Key]
+			if !ok {
+				return nil, fmt.Errorf("secret %s of header %s has no %s", ref.Name, h.Name, ref.Key)
+			}
+			value = string(data)
+		}
+		header.Set(h.Name, value)
+	}
+	return header, nil
+}
+
+// expectedStatus reports whether a status code is one an HTTP hook expects
+func expectedStatus(spec *etcdguardianv1alpha1.HTTPHook, statusCode int) bool {
+	if len(spec.ExpectedStatusCodes) == 0 {
+		return statusCode >= 200 && statusCode < 300
+	}
+	for _, code := range spec.ExpectedStatusCodes {
+		if int(code) == statusCode {
+			return true
+		}
+	}
+	return false
+}
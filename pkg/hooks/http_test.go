@@ -0,0 +1,230 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+)
+
+// fakeWebhook records the requests it receives and answers them with the
+// status codes given, the last one repeated
+type fakeWebhook struct {
+	mu       sync.Mutex
+	server   *httptest.Server
+	statuses []int
+	requests []*http.Request
+	bodies   []string
+}
+
+func newFakeWebhook(t *testing.T, statuses ...int) *fakeWebhook {
+	t.Helper()
+
+	webhook := &fakeWebhook{statuses: statuses}
+	webhook.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		webhook.mu.Lock()
+		defer webhook.mu.Unlock()
+		webhook.requests = append(webhook.requests, r)
+		webhook.bodies = append(webhook.bodies, string(body))
+		status := webhook.statuses[0]
+		if len(webhook.statuses) > 1 {
+			webhook.statuses = webhook.statuses[1:]
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("paused"))
+	}))
+	t.Cleanup(webhook.server.Close)
+	return webhook
+}
+
+func httpHook(name, url string) etcdguardianv1alpha1.Hook {
+	return etcdguardianv1alpha1.Hook{
+		Name: name,
+		Type: etcdguardianv1alpha1.HookTypeHTTP,
+		HTTP: &etcdguardianv1alpha1.HTTPHook{URL: url},
+	}
+}
+
+func TestRunner_HTTP(t *testing.T) {
+	webhook := newFakeWebhook(t, http.StatusAccepted)
+	token := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "pipeline-token", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("Bearer s3cr3t")},
+	}
+	runner := newTestRunner(nil, token)
+	ctx := context.Background()
+
+	// The default body describes the backup as JSON
+	results, err := runner.Run(ctx, testBackup, etcdguardianv1alpha1.HookStagePreBackup, []etcdguardianv1alpha1.Hook{httpHook("pause", webhook.server.URL)})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(results) != 1 || !results[0].Succeeded || results[0].StatusCode != http.StatusAccepted || results[0].Attempts != 1 || results[0].Output != "" {
+		t.Errorf("Unexpected results %+v", results)
+	}
+	event := map[string]string{}
+	if err := json.Unmarshal([]byte(webhook.bodies[0]), &event); err != nil {
+		t.Fatalf("Expected a JSON body: %v", err)
+	}
+	if event["kind"] != "EtcdBackup" || event["name"] != "nightly" || event["stage"] != "PreBackup" || event["phase"] != "Validating" {
+		t.Errorf("Unexpected body %v", event)
+	}
+	if webhook.requests[0].Method != http.MethodPost || webhook.requests[0].Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Unexpected request %s %v", webhook.requests[0].Method, webhook.requests[0].Header)
+	}
+
+	// Headers are read from secrets and the body is rendered from its
+	// template
+	hook := httpHook("pause", webhook.server.URL)
+	hook.HTTP.Method = http.MethodPut
+	hook.HTTP.Headers = []etcdguardianv1alpha1.HTTPHeader{
+		{Name: "Authorization", ValueFrom: &etcdguardianv1alpha1.SecretKeySelector{Name: "pipeline-token", Key: "token"}},
+		{Name: "Content-Type", Value: "text/plain"},
+	}
+	hook.HTTP.Body = `{{ .Stage }} {{ .Namespace }}/{{ .Name }} {{ .Object.Spec.BackupMode }} {{ json .Name }}`
+	hook.HTTP.ExpectedStatusCodes = []int32{http.StatusAccepted}
+	if _, err := runner.Run(ctx, testBackup, etcdguardianv1alpha1.HookStagePostBackup, []etcdguardianv1alpha1.Hook{hook}); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	request := webhook.requests[1]
+	if request.Method != http.MethodPut || request.Header.Get("Authorization") != "Bearer s3cr3t" || request.Header.Get("Content-Type") != "text/plain" {
+		t.Errorf("Unexpected request %s %v", request.Method, request.Header)
+	}
+	if webhook.bodies[1] != `PostBackup default/nightly Full "nightly"` {
+		t.Errorf("Unexpected body %q", webhook.bodies[1])
+	}
+
+	// Unexpected status codes fail without retries
+	hook.HTTP.ExpectedStatusCodes = []int32{http.StatusOK}
+	hook.HTTP.Retries = 3
+	results, err = runner.Run(ctx, testBackup, etcdguardianv1alpha1.HookStagePostBackup, []etcdguardianv1alpha1.Hook{hook})
+	if err == nil || len(results) != 1 || results[0].Attempts != 1 || !strings.Contains(results[0].Message, "unexpected status 202") {
+		t.Errorf("Expected an unexpected status, got %+v, %v", results, err)
+	}
+
+	// Missing secrets and broken templates fail before the request
+	hook.HTTP.Headers[0].ValueFrom.Key = "missing"
+	if _, err := runner.Run(ctx, testBackup, etcdguardianv1alpha1.HookStagePostBackup, []etcdguardianv1alpha1.Hook{hook}); err == nil || !strings.Contains(err.Error(), "has no missing") {
+		t.Errorf("Expected the missing key to be reported, got %v", err)
+	}
+	hook.HTTP.Headers = nil
+	hook.HTTP.Body = "{{ .Object.Spec.Missing }}"
+	if _, err := runner.Run(ctx, testBackup, etcdguardianv1alpha1.HookStagePostBackup, []etcdguardianv1alpha1.Hook{hook}); err == nil || !strings.Contains(err.Error(), "body template") {
+		t.Errorf("Expected the template to fail, got %v", err)
+	}
+	if len(webhook.requests) != 3 {
+		t.Errorf("Expected no request for failed hooks, got %d requests", len(webhook.requests))
+	}
+}
+
+func TestRunner_HTTPRetries(t *testing.T) {
+	webhook := newFakeWebhook(t, http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK)
+	runner := newTestRunner(nil)
+	runner.retryDelay = time.Millisecond
+	ctx := context.Background()
+
+	restore := &etcdguardianv1alpha1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{Name: "rollback", Namespace: "default"},
+		Status:     etcdguardianv1alpha1.EtcdRestoreStatus{Phase: etcdguardianv1alpha1.RestorePhaseQuiescing},
+	}
+	hook := httpHook("pause", webhook.server.URL)
+	hook.HTTP.Retries = 1
+	results, err := runner.Run(ctx, restore, etcdguardianv1alpha1.HookStagePreRestore, []etcdguardianv1alpha1.Hook{hook})
+	if err == nil || results[0].Attempts != 2 || results[0].StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Expected the retries to run out, got %+v, %v", results, err)
+	}
+
+	results, err = runner.Run(ctx, restore, etcdguardianv1alpha1.HookStagePreRestore, []etcdguardianv1alpha1.Hook{hook})
+	if err != nil || !results[0].Succeeded {
+		t.Fatalf("Expected the hook to succeed, got %+v, %v", results, err)
+	}
+	if !strings.Contains(webhook.bodies[0], `"kind":"EtcdRestore"`) || !strings.Contains(webhook.bodies[0], `"stage":"PreRestore"`) {
+		t.Errorf("Unexpected body %s", webhook.bodies[0])
+	}
+
+	// Connection errors are retried too, each attempt bounded by the timeout
+	stop := make(chan struct{})
+	hang := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-stop:
+		}
+	}))
+	defer hang.Close()
+	defer close(stop)
+	hook = httpHook("pause", hang.URL)
+	hook.HTTP.Timeout = &metav1.Duration{Duration: 10 * time.Millisecond}
+	hook.HTTP.Retries = 2
+	results, err = runner.Run(ctx, restore, etcdguardianv1alpha1.HookStagePreRestore, []etcdguardianv1alpha1.Hook{hook})
+	if err == nil || results[0].Attempts != 3 || results[0].StatusCode != 0 {
+		t.Errorf("Expected every attempt to time out, got %+v, %v", results, err)
+	}
+}
+
+func TestRunner_HTTPBlockedAddresses(t *testing.T) {
+	webhook := newFakeWebhook(t, http.StatusOK)
+	runner := NewRunner(nil, nil, logr.Discard())
+	ctx := context.Background()
+
+	for _, url := range []string{
+		webhook.server.URL,
+		"http://169.254.169.254/latest/meta-data/iam/security-credentials/",
+		"http://100.100.100.200/latest/meta-data/ram/security-credentials/",
+		"http://[::1]:8080/",
+	} {
+		results, err := runner.Run(ctx, testBackup, etcdguardianv1alpha1.HookStagePreBackup, []etcdguardianv1alpha1.Hook{httpHook("metadata", url)})
+		if !errors.Is(err, errBlockedAddress) || results[0].StatusCode != 0 {
+			t.Errorf("Expected %s to be refused, got %+v, %v", url, results, err)
+		}
+	}
+	if len(webhook.requests) != 0 {
+		t.Errorf("Expected no request to reach the loopback address, got %d", len(webhook.requests))
+	}
+
+	for ip, allowed := range map[string]bool{
+		"10.96.0.10":       true,
+		"203.0.113.7":      true,
+		"2001:db8::1":      true,
+		"127.0.0.1":        false,
+		"::ffff:127.0.0.1": false,
+		"169.254.170.2":    false,
+		"100.100.100.200":  false,
+		"fd00:ec2::254":    false,
+		"fe80::1":          false,
+		"0.0.0.0":          false,
+	} {
+		if addressAllowed(net.ParseIP(ip)) != allowed {
+			t.Errorf("Expected %s allowed to be %v", ip, allowed)
+		}
+	}
+}
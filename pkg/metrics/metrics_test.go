@@ -0,0 +1,50 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRecordBackupSize(t *testing.T) {
+	BackupSize.Reset()
+	for i := 0; i < MaxBackupSizeSeries+10; i++ {
+		RecordBackupSize(fmt.Sprintf("nightly-%d", i), "Full", int64(i))
+	}
+	if count := testutil.CollectAndCount(BackupSize); count != MaxBackupSizeSeries {
+		t.Fatalf("Expected %d series, got %d", MaxBackupSizeSeries, count)
+	}
+
+	// The oldest backups are dropped, and recording a backup again makes
+	// it the newest
+	if value := testutil.ToFloat64(BackupSize.WithLabelValues("nightly-10", "Full")); value != 10 {
+		t.Errorf("Expected nightly-10 to be kept, got %g", value)
+	}
+	RecordBackupSize("nightly-10", "Full", 1024)
+	RecordBackupSize("extra", "Full", 1)
+	if value := testutil.ToFloat64(BackupSize.WithLabelValues("nightly-10", "Full")); value != 1024 {
+		t.Errorf("Expected nightly-10 to be kept, got %g", value)
+	}
+
+	DeleteBackupSize("nightly-10")
+	if count := testutil.CollectAndCount(BackupSize); count != MaxBackupSizeSeries-1 {
+		t.Errorf("Expected the deleted backup to be dropped, got %d series", count)
+	}
+}
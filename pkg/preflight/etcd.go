@@ -0,0 +1,248 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+)
+
+// Keys of the etcd certificate secrets. The CA, client certificate and key
+// may be kept in one kubernetes.io/tls secret or in separate ones.
+const (
+	EtcdCAKey   = "ca.crt"
+	EtcdCertKey = corev1.TLSCertKey
+	EtcdKeyKey  = corev1.TLSPrivateKeyKey
+)
+
+// etcdDBSizeMetrics are the metrics etcd reports the size of its database
+// in, the debugging one in releases before 3.4
+var etcdDBSizeMetrics = []string{
+	"etcd_mvcc_db_total_size_in_bytes",
+	"etcd_debugging_mvcc_db_total_size_in_bytes",
+}
+
+// EtcdTLSConfig builds the TLS configuration to dial etcd with from the
+// secrets named in certs, nil when certs is nil
+func EtcdTLSConfig(ctx context.Context, reader client.Reader, namespace string, certs *etcdguardianv1alpha1.EtcdCertificates) (*tls.Config, error) {
+	if certs == nil {
+		return nil, nil
+	}
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if certs.CA != "" {
+		ca, err := secretKey(ctx, reader, namespace, certs.CA, EtcdCAKey)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("%s of secret %s holds no PEM certificate", EtcdCAKey, certs.CA)
+		}
+	}
+
+	if (certs.Cert == "") != (certs.Key == "") {
+		return nil, fmt.Errorf("etcd client certificate and key must be set together")
+	}
+	if certs.Cert != "" {
+		cert, err := secretKey(ctx, reader, namespace, certs.Cert, EtcdCertKey)
+		if err != nil {
+			return nil, err
+		}
+		key, err := secretKey(ctx, reader, namespace, certs.Key, EtcdKeyKey)
+		if err != nil {
+			return nil, err
+		}
+		pair, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid etcd client certificate in secrets %s and %s: %w", certs.Cert, certs.Key, err)
+		}
+		config.Certificates = []tls.Certificate{pair}
+	}
+	return config, nil
+}
+
+// secretKey reads a key of a secret
+func secretKey(ctx context.Context, reader client.Reader, namespace, name, key string) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get etcd certificate secret %s/%s: %w", namespace, name, err)
+	}
+	value, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("etcd certificate secret %s has no %s", name, key)
+	}
+	return value, nil
+}
+
+// HealthyEndpoint returns the URL of the first endpoint whose /health
+// reports etcd healthy. Endpoints without a scheme are dialed over HTTPS
+// when tlsConfig is set.
+func HealthyEndpoint(ctx context.Context, endpoints []string, tlsConfig *tls.Config) (string, error) {
+	errs := []error{}
+	for _, endpoint := range endpoints {
+		endpoint = endpointURL(endpoint, tlsConfig)
+		body, err := get(ctx, endpoint+"/health", tlsConfig)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var health struct {
+			Health string `json:"health"`
+			Reason string `json:"reason"`
+		}
+		if err := json.Unmarshal(body, &health); err != nil {
+			errs = append(errs, fmt.Errorf("%s/health: %w", endpoint, err))
+			continue
+		}
+		if health.Health != "true" {
+			errs = append(errs, fmt.Errorf("etcd at %s is unhealthy: %s", endpoint, health.Reason))
+			continue
+		}
+		return endpoint, nil
+	}
+	return "", fmt.Errorf("no healthy etcd endpoint: %w", errors.Join(errs...))
+}
+
+// DBSize returns the size of the database of the etcd member at endpoint,
+// read from its metrics
+func DBSize(ctx context.Context, endpoint string, tlsConfig *tls.Config) (int64, error) {
+	values, err := scrape(ctx, endpoint, tlsConfig)
+	if err != nil {
+		return 0, err
+	}
+	return metricValue(values, etcdDBSizeMetrics, endpoint, "database size")
+}
+
+// memberStatus is the response of the Maintenance Status API of etcd, as
+// served by its JSON gateway, which encodes 64-bit integers as strings
+type memberStatus struct {
+	Header *struct {
+		Revision int64 `json:"revision,string"`
+	} `json:"header"`
+	DBSize int64 `json:"dbSize,string"`
+}
+
+// MemberStatus returns the size of the database and the current revision of
+// the etcd member at endpoint, read from its Maintenance Status API
+func MemberStatus(ctx context.Context, endpoint string, tlsConfig *tls.Config) (dbSize, revision int64, err error) {
+	url := endpointURL(endpoint, tlsConfig) + "/v3/maintenance/status"
+	body, err := do(ctx, http.MethodPost, url, strings.NewReader("{}"), tlsConfig)
+	if err != nil {
+		return 0, 0, err
+	}
+	var status memberStatus
+	if err := json.Unmarshal(body, &status); err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", url, err)
+	}
+	if status.Header == nil || status.Header.Revision == 0 {
+		return 0, 0, fmt.Errorf("%s does not report the current revision", url)
+	}
+	return status.DBSize, status.Header.Revision, nil
+}
+
+// scrape reads the metrics without labels of the etcd member at endpoint
+func scrape(ctx context.Context, endpoint string, tlsConfig *tls.Config) (map[string]string, error) {
+	body, err := get(ctx, endpointURL(endpoint, tlsConfig)+"/metrics", tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(string(body)))
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), " ")
+		if ok {
+			values[name] = strings.TrimSpace(value)
+		}
+	}
+	return values, nil
+}
+
+// metricValue returns the value of the first of metrics that was scraped
+func metricValue(values map[string]string, metrics []string, endpoint, what string) (int64, error) {
+	for _, metric := range metrics {
+		value, ok := values[metric]
+		if !ok {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s: %w", metric, err)
+		}
+		return int64(parsed), nil
+	}
+	return 0, fmt.Errorf("%s/metrics does not report the %s", endpoint, what)
+}
+
+// endpointURL adds the scheme to an endpoint given as host:port
+func endpointURL(endpoint string, tlsConfig *tls.Config) string {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if strings.Contains(endpoint, "://") {
+		return endpoint
+	}
+	if tlsConfig != nil {
+		return "https://" + endpoint
+	}
+	return "http://" + endpoint
+}
+
+// get reads a URL of an etcd member
+func get(ctx context.Context, url string, tlsConfig *tls.Config) ([]byte, error) {
+	return do(ctx, http.MethodGet, url, nil, tlsConfig)
+}
+
+// do sends a request to an etcd member and returns the response body
+func do(ctx context.Context, method, url string, body io.Reader, tlsConfig *tls.Config) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	transport := &http.Transport{TLSClientConfig: tlsConfig}
+	defer transport.CloseIdleConnections()
+
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	return data, nil
+}
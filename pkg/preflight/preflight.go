@@ -0,0 +1,221 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/replication"
+	"github.com/etcdguardian/etcdguardian/pkg/storage"
+)
+
+// Machine-readable reasons of failed preflight checks
+const (
+	ReasonCredentialsNotFound      = "CredentialsNotFound"
+	ReasonCredentialsInvalid       = "CredentialsInvalid"
+	ReasonEtcdCertificatesInvalid  = "EtcdCertificatesInvalid"
+	ReasonEtcdUnreachable          = "EtcdUnreachable"
+	ReasonStorageAccessFailed      = "StorageAccessFailed"
+	ReasonInsufficientScratchSpace = "InsufficientScratchSpace"
+)
+
+// DefaultTimeout bounds each probe of etcd and of a storage destination
+const DefaultTimeout = 30 * time.Second
+
+// Error is the error of a failed preflight check
+type Error struct {
+	// Reason is one of the Reason constants
+	Reason string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Checker checks that a backup can succeed before its snapshot is taken:
+// the credentials of every destination can be parsed, etcd answers with the
+// configured certificates, every destination accepts a write and delete
+// probe, and the scratch directory has room for the etcd database.
+type Checker struct {
+	client     client.Client
+	scratchDir string
+	timeout    time.Duration
+
+	// freeSpace returns the bytes available in a directory, replaced in tests
+	freeSpace func(dir string) (uint64, error)
+}
+
+// NewChecker creates a preflight checker of backups whose snapshots are
+// written to scratchDir
+func NewChecker(k8sClient client.Client, scratchDir string) *Checker {
+	if scratchDir == "" {
+		scratchDir = os.TempDir()
+	}
+	return &Checker{
+		client:     k8sClient,
+		scratchDir: scratchDir,
+		timeout:    DefaultTimeout,
+		freeSpace:  freeSpace,
+	}
+}
+
+// Run runs the preflight checks of a backup stored into targets and returns
+// the size of the etcd database, zero when it was not checked. The error of
+// the first check that fails is an *Error; other errors, such as the API
+// server being unavailable, should be retried.
+func (c *Checker) Run(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup, targets []replication.Target) (int64, error) {
+	for _, target := range targets {
+		if err := storage.ValidateCredentials(ctx, c.client, backup.Namespace, target.Location); err != nil {
+			if apierrors.IsNotFound(err) {
+				return 0, &Error{Reason: ReasonCredentialsNotFound, Err: fmt.Errorf("destination %s: %w", target.Name, err)}
+			}
+			if isAPIError(err) {
+				return 0, err
+			}
+			return 0, &Error{Reason: ReasonCredentialsInvalid, Err: fmt.Errorf("destination %s: %w", target.Name, err)}
+		}
+	}
+
+	// Backups in the Job execution mode reach etcd from a control-plane
+	// node, possibly in its network, and write their snapshot to the
+	// scratch space of the Job, neither of which the operator can check
+	jobMode := backup.Spec.ExecutionMode == etcdguardianv1alpha1.ExecutionModeJob
+	var dbSize int64
+	if !jobMode {
+		size, err := c.checkEtcd(ctx, backup)
+		if err != nil {
+			return 0, err
+		}
+		dbSize = size
+	}
+
+	for _, target := range targets {
+		if err := c.checkAccess(ctx, backup, target); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := c.CheckScratchSpace(dbSize); err != nil {
+		return 0, err
+	}
+	return dbSize, nil
+}
+
+// checkEtcd dials the etcd endpoints of a backup and returns the size of the
+// database reported by the first healthy one. Backups without endpoints rely
+// on auto-discovery by the snapshot engine and are not probed.
+func (c *Checker) checkEtcd(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup) (int64, error) {
+	if len(backup.Spec.EtcdEndpoints) == 0 {
+		return 0, nil
+	}
+
+	tlsConfig, err := EtcdTLSConfig(ctx, c.client, backup.Namespace, backup.Spec.EtcdCertificates)
+	if err != nil {
+		if isAPIError(err) && !apierrors.IsNotFound(err) {
+			return 0, err
+		}
+		return 0, &Error{Reason: ReasonEtcdCertificatesInvalid, Err: err}
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	endpoint, err := HealthyEndpoint(probeCtx, backup.Spec.EtcdEndpoints, tlsConfig)
+	if err != nil {
+		return 0, &Error{Reason: ReasonEtcdUnreachable, Err: err}
+	}
+
+	// The size only sizes the scratch space check, which is skipped when
+	// the metrics cannot be read
+	size, err := DBSize(probeCtx, endpoint, tlsConfig)
+	if err != nil {
+		size = 0
+	}
+	return size, nil
+}
+
+// checkAccess writes, reads back, lists and deletes a probe object in a
+// destination. Immutable destinations retaining the probe object of an
+// earlier check only read and list it, so that checking every backup does
+// not leave a retained object behind per backup.
+func (c *Checker) checkAccess(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup, target replication.Target) error {
+	probeCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	store, err := storage.NewObjectStore(target.Location.Provider, target.Location, c.client, backup.Namespace)
+	if err == nil {
+		err = storage.CheckAccess(probeCtx, store, target.Location)
+	}
+	if err != nil {
+		return &Error{
+			Reason: ReasonStorageAccessFailed,
+			Err:    fmt.Errorf("destination %s (%s): %w", target.Name, storage.Classify(err).Reason(), err),
+		}
+	}
+	return nil
+}
+
+// CheckScratchSpace checks that the scratch directory has room for a
+// snapshot of dbSize bytes
+func (c *Checker) CheckScratchSpace(dbSize int64) error {
+	if dbSize <= 0 {
+		return nil
+	}
+	if err := os.MkdirAll(c.scratchDir, 0o700); err != nil {
+		return &Error{Reason: ReasonInsufficientScratchSpace, Err: fmt.Errorf("failed to create scratch directory: %w", err)}
+	}
+	available, err := c.freeSpace(c.scratchDir)
+	if err != nil {
+		return &Error{Reason: ReasonInsufficientScratchSpace, Err: fmt.Errorf("failed to read free space of %s: %w", c.scratchDir, err)}
+	}
+	if available < uint64(dbSize) {
+		return &Error{
+			Reason: ReasonInsufficientScratchSpace,
+			Err:    fmt.Errorf("scratch directory %s has %d bytes free, the etcd database is %d bytes", c.scratchDir, available, dbSize),
+		}
+	}
+	return nil
+}
+
+// freeSpace returns the bytes available to unprivileged users in the file
+// system of a directory
+func freeSpace(dir string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
+
+// isAPIError reports whether err wraps an error of the API server
+func isAPIError(err error) bool {
+	var status apierrors.APIStatus
+	return errors.As(err, &status)
+}
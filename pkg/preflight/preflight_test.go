@@ -0,0 +1,345 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/replication"
+	"github.com/etcdguardian/etcdguardian/pkg/storage"
+)
+
+func TestMain(m *testing.M) {
+	storage.EnableMemoryStorage()
+	os.Exit(m.Run())
+}
+
+// newClientCertificate generates a self-signed client certificate and key in
+// PEM format
+func newClientCertificate(t *testing.T) (*x509.Certificate, []byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "etcdguardian"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	return cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// fakeEtcd serves the health and metrics endpoints of an etcd member that
+// requires client certificates
+type fakeEtcd struct {
+	server  *httptest.Server
+	healthy bool
+	dbSize  int64
+	secret  *corev1.Secret
+}
+
+func newFakeEtcd(t *testing.T) *fakeEtcd {
+	t.Helper()
+
+	etcd := &fakeEtcd{healthy: true, dbSize: 2048}
+	etcd.server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			if !etcd.healthy {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				fmt.Fprint(w, `{"health":"false","reason":"NOSPACE"}`)
+				return
+			}
+			fmt.Fprint(w, `{"health":"true","reason":""}`)
+		case "/metrics":
+			fmt.Fprintf(w, "# TYPE etcd_mvcc_db_total_size_in_bytes gauge\netcd_mvcc_db_total_size_in_bytes %g\n", float64(etcd.dbSize))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	clientCert, certPEM, keyPEM := newClientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	etcd.server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	etcd.server.StartTLS()
+	t.Cleanup(etcd.server.Close)
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: etcd.server.Certificate().Raw})
+	etcd.secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "etcd-certs", Namespace: "default"},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			EtcdCAKey:   caPEM,
+			EtcdCertKey: certPEM,
+			EtcdKeyKey:  keyPEM,
+		},
+	}
+	return etcd
+}
+
+func newFakeClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = etcdguardianv1alpha1.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func newTestBackup(endpoint string) *etcdguardianv1alpha1.EtcdBackup {
+	return &etcdguardianv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+		Spec: etcdguardianv1alpha1.EtcdBackupSpec{
+			BackupMode:    etcdguardianv1alpha1.BackupModeFull,
+			EtcdEndpoints: []string{endpoint},
+			EtcdCertificates: &etcdguardianv1alpha1.EtcdCertificates{
+				CA:   "etcd-certs",
+				Cert: "etcd-certs",
+				Key:  "etcd-certs",
+			},
+		},
+	}
+}
+
+func memoryTarget(bucket string) replication.Target {
+	return replication.Target{
+		Name: replication.PrimaryName,
+		Location: etcdguardianv1alpha1.StorageLocation{
+			Provider: storage.ProviderMemory,
+			Bucket:   bucket,
+			Prefix:   "etcd",
+		},
+	}
+}
+
+func newTestChecker(k8sClient client.Client, free uint64) *Checker {
+	checker := NewChecker(k8sClient, "")
+	checker.timeout = 5 * time.Second
+	checker.freeSpace = func(string) (uint64, error) { return free, nil }
+	return checker
+}
+
+func reasonOf(err error) string {
+	if failed, ok := err.(*Error); ok {
+		return failed.Reason
+	}
+	return fmt.Sprintf("unexpected error %v", err)
+}
+
+func TestChecker_Run(t *testing.T) {
+	t.Cleanup(storage.ResetMemoryStorage)
+	etcd := newFakeEtcd(t)
+	ctx := context.Background()
+
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "gcs-credentials", Namespace: "default"},
+		Data:       map[string][]byte{"service-account.json": []byte("{")},
+	}
+	k8sClient := newFakeClient(etcd.secret, credentials)
+	backup := newTestBackup(etcd.server.URL)
+	targets := []replication.Target{memoryTarget("preflight")}
+
+	size, err := newTestChecker(k8sClient, 1<<20).Run(ctx, backup, targets)
+	if err != nil {
+		t.Fatalf("Expected the preflight checks to pass, got %v", err)
+	}
+	if size <= 0 {
+		t.Errorf("Expected the size of the etcd database, got %d", size)
+	}
+
+	t.Run("credentials not found", func(t *testing.T) {
+		target := memoryTarget("preflight")
+		target.Location.CredentialsSecret = "missing"
+		_, err := newTestChecker(k8sClient, 1<<20).Run(ctx, backup, []replication.Target{target})
+		if reason := reasonOf(err); reason != ReasonCredentialsNotFound {
+			t.Errorf("Expected %s, got %s", ReasonCredentialsNotFound, reason)
+		}
+	})
+
+	t.Run("credentials invalid", func(t *testing.T) {
+		target := replication.Target{Name: "gcs", Location: etcdguardianv1alpha1.StorageLocation{
+			Provider:          etcdguardianv1alpha1.StorageProviderGCS,
+			Bucket:            "backups",
+			CredentialsSecret: "gcs-credentials",
+		}}
+		_, err := newTestChecker(k8sClient, 1<<20).Run(ctx, backup, append(targets, target))
+		if reason := reasonOf(err); reason != ReasonCredentialsInvalid {
+			t.Errorf("Expected %s, got %s", ReasonCredentialsInvalid, reason)
+		}
+	})
+
+	t.Run("etcd certificates invalid", func(t *testing.T) {
+		invalid := newTestBackup(etcd.server.URL)
+		invalid.Spec.EtcdCertificates.Key = "missing"
+		_, err := newTestChecker(k8sClient, 1<<20).Run(ctx, invalid, targets)
+		if reason := reasonOf(err); reason != ReasonEtcdCertificatesInvalid {
+			t.Errorf("Expected %s, got %s", ReasonEtcdCertificatesInvalid, reason)
+		}
+	})
+
+	t.Run("etcd unreachable", func(t *testing.T) {
+		// Without a client certificate the TLS handshake fails
+		anonymous := newTestBackup(etcd.server.URL)
+		anonymous.Spec.EtcdCertificates = &etcdguardianv1alpha1.EtcdCertificates{CA: "etcd-certs"}
+		_, err := newTestChecker(k8sClient, 1<<20).Run(ctx, anonymous, targets)
+		if reason := reasonOf(err); reason != ReasonEtcdUnreachable {
+			t.Errorf("Expected %s, got %s", ReasonEtcdUnreachable, reason)
+		}
+
+		etcd.healthy = false
+		defer func() { etcd.healthy = true }()
+		_, err = newTestChecker(k8sClient, 1<<20).Run(ctx, backup, targets)
+		if reason := reasonOf(err); reason != ReasonEtcdUnreachable {
+			t.Errorf("Expected %s, got %s", ReasonEtcdUnreachable, reason)
+		}
+	})
+
+	t.Run("storage access failed", func(t *testing.T) {
+		faults := storage.MemoryFaults("preflight")
+		faults.Add(storage.Fault{Operations: []storage.FaultOperation{storage.FaultDelete}, Err: &storage.InjectedError{Class: storage.ErrorClassAuth}})
+		defer faults.Clear()
+		_, err := newTestChecker(k8sClient, 1<<20).Run(ctx, backup, targets)
+		if reason := reasonOf(err); reason != ReasonStorageAccessFailed {
+			t.Errorf("Expected %s, got %s", ReasonStorageAccessFailed, reason)
+		}
+	})
+
+	t.Run("immutable destination", func(t *testing.T) {
+		// The bucket retains the probe object and refuses to overwrite it
+		target := memoryTarget("worm")
+		target.Location.Immutability = &etcdguardianv1alpha1.ImmutabilityConfig{Mode: etcdguardianv1alpha1.ImmutabilityModeCompliance}
+		faults := storage.MemoryFaults("worm")
+		faults.Add(storage.Fault{Operations: []storage.FaultOperation{storage.FaultDelete}, Err: &storage.InjectedError{Class: storage.ErrorClassObjectLocked}})
+		defer faults.Clear()
+
+		for i := 0; i < 3; i++ {
+			if _, err := newTestChecker(k8sClient, 1<<20).Run(ctx, backup, []replication.Target{target}); err != nil {
+				t.Fatalf("Expected the preflight checks of backup %d to pass, got %v", i, err)
+			}
+			faults.Add(storage.Fault{Operations: []storage.FaultOperation{storage.FaultPut}, Err: &storage.InjectedError{Class: storage.ErrorClassObjectLocked}})
+		}
+
+		store, err := storage.NewObjectStore(target.Location.Provider, target.Location, nil, "default")
+		if err != nil {
+			t.Fatalf("NewObjectStore failed: %v", err)
+		}
+		objects, err := store.ListObjects(ctx, "etcd/")
+		if err != nil || len(objects) != 1 {
+			t.Errorf("Expected the backups to share a single retained probe object, got %+v (%v)", objects, err)
+		}
+	})
+
+	t.Run("insufficient scratch space", func(t *testing.T) {
+		_, err := newTestChecker(k8sClient, 1024).Run(ctx, backup, targets)
+		if reason := reasonOf(err); reason != ReasonInsufficientScratchSpace {
+			t.Errorf("Expected %s, got %s", ReasonInsufficientScratchSpace, reason)
+		}
+
+		// Without endpoints the size of the database is unknown
+		discovered := newTestBackup("")
+		discovered.Spec.EtcdEndpoints = nil
+		if _, err := newTestChecker(k8sClient, 1024).Run(ctx, discovered, targets); err != nil {
+			t.Errorf("Expected the etcd and scratch space checks to be skipped, got %v", err)
+		}
+	})
+
+	t.Run("job execution mode", func(t *testing.T) {
+		// The Job reaches etcd from its node, possibly in the node network
+		job := newTestBackup(etcd.server.URL)
+		job.Spec.EtcdCertificates = nil
+		job.Spec.ExecutionMode = etcdguardianv1alpha1.ExecutionModeJob
+		if _, err := newTestChecker(k8sClient, 1024).Run(ctx, job, targets); err != nil {
+			t.Errorf("Expected the etcd and scratch space checks to be skipped, got %v", err)
+		}
+	})
+}
+
+func TestDBSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "etcd_server_has_leader 1\netcd_debugging_mvcc_db_total_size_in_bytes 1.048576e+06\n")
+	}))
+	defer server.Close()
+
+	size, err := DBSize(context.Background(), server.URL, nil)
+	if err != nil || size != 1<<20 {
+		t.Errorf("Expected a database of 1MiB, got %d, %v", size, err)
+	}
+	if got := endpointURL("10.0.0.1:2379", &tls.Config{}); got != "https://10.0.0.1:2379" {
+		t.Errorf("Unexpected endpoint URL %s", got)
+	}
+}
+
+func TestMemberStatus(t *testing.T) {
+	response := `{"header":{"cluster_id":"14841639068965178418","member_id":"10276657743932975437","revision":"1234500","raft_term":"2"},"version":"3.5.9","dbSize":"4096","leader":"10276657743932975437"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v3/maintenance/status" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, response)
+	}))
+	defer server.Close()
+
+	dbSize, revision, err := MemberStatus(context.Background(), server.URL, nil)
+	if err != nil || dbSize != 4096 || revision != 1234500 {
+		t.Errorf("Unexpected status %d, %d, %v", dbSize, revision, err)
+	}
+
+	response = `{"version":"3.5.9","dbSize":"4096"}`
+	if _, _, err := MemberStatus(context.Background(), server.URL, nil); err == nil || !strings.Contains(err.Error(), "current revision") {
+		t.Errorf("Expected the revision to be missing, got %v", err)
+	}
+}
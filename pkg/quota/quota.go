@@ -0,0 +1,226 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/replication"
+)
+
+// Group is the namespace or tenant a quota applies to
+type Group struct {
+	Namespace string
+	Tenant    string
+}
+
+func (g Group) String() string {
+	if g.Tenant != "" {
+		return "tenant " + g.Tenant
+	}
+	return "namespace " + g.Namespace
+}
+
+// Usage is the storage used by a group
+type Usage struct {
+	Backups int32
+	Bytes   int64
+}
+
+// ExceededError reports a group that cannot store another backup
+type ExceededError struct {
+	Group Group
+	// Resource is "backups" or "bytes"
+	Resource string
+	Used     int64
+	Limit    int64
+	// Requested is the expected size of the new backup that does not fit
+	Requested int64
+}
+
+func (e *ExceededError) Error() string {
+	if e.Resource == "bytes" {
+		message := fmt.Sprintf("storage quota of %s exceeded: %s of %s stored", e.Group,
+			resource.NewQuantity(e.Used, resource.BinarySI), resource.NewQuantity(e.Limit, resource.BinarySI))
+		if e.Used < e.Limit {
+			message += fmt.Sprintf(", the backup is expected to take %s", resource.NewQuantity(e.Requested, resource.BinarySI))
+		}
+		return message
+	}
+	return fmt.Sprintf("backup quota of %s exceeded: %d of %d backups stored", e.Group, e.Used, e.Limit)
+}
+
+// Grouper maps namespaces to the groups of a quota
+type Grouper struct {
+	tenantLabel string
+	labels      map[string]map[string]string
+}
+
+// NewGrouper reads the namespace labels a quota groups tenants by. Without
+// a tenant label every namespace is a group of its own.
+func NewGrouper(ctx context.Context, reader client.Reader, quota *etcdguardianv1alpha1.StorageQuota) (*Grouper, error) {
+	grouper := &Grouper{tenantLabel: quota.TenantLabel, labels: map[string]map[string]string{}}
+	if quota.TenantLabel == "" {
+		return grouper, nil
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	if err := reader.List(ctx, namespaces); err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	for _, namespace := range namespaces.Items {
+		grouper.labels[namespace.Name] = namespace.Labels
+	}
+	return grouper, nil
+}
+
+// Group returns the group of a namespace: its tenant when it has the
+// tenant label, the namespace itself otherwise
+func (g *Grouper) Group(namespace string) Group {
+	if tenant := g.labels[namespace][g.tenantLabel]; g.tenantLabel != "" && tenant != "" {
+		return Group{Tenant: tenant}
+	}
+	return Group{Namespace: namespace}
+}
+
+// Compute returns the storage used per group by the given backups: the
+// snapshots stored by finished backups, and the snapshots backups in
+// progress are about to store, counted at the size of their snapshot or,
+// until it is taken, at the size expected by their preflight checks. Only
+// snapshots in a destination matched by include count, every destination
+// when include is nil. A backup counts once when at least one of its
+// snapshots counts.
+func Compute(ctx context.Context, reader client.Reader, grouper *Grouper, backups []etcdguardianv1alpha1.EtcdBackup, include func(replication.Target) bool) map[Group]Usage {
+	usage := map[Group]Usage{}
+	for i := range backups {
+		backup := &backups[i]
+		stored := snapshots(ctx, reader, backup, include)
+		if stored == 0 {
+			continue
+		}
+		size := backup.Status.SnapshotSize
+		if size == 0 {
+			size = backup.Status.ExpectedSnapshotSize
+		}
+
+		group := grouper.Group(backup.Namespace)
+		groupUsage := usage[group]
+		groupUsage.Backups++
+		groupUsage.Bytes += stored * size
+		usage[group] = groupUsage
+	}
+	return usage
+}
+
+// snapshots returns the number of snapshots a backup stores in the
+// destinations matched by include: the completed ones of a finished
+// backup, and every one not failed yet of a backup in progress
+func snapshots(ctx context.Context, reader client.Reader, backup *etcdguardianv1alpha1.EtcdBackup, include func(replication.Target) bool) int64 {
+	phases := map[string]etcdguardianv1alpha1.ReplicaPhase{}
+	for _, status := range backup.Status.Replicas {
+		phases[status.Name] = status.Phase
+	}
+
+	running := inProgress(backup)
+	if !running && include == nil {
+		stored := int64(0)
+		for _, phase := range phases {
+			if phase == etcdguardianv1alpha1.ReplicaPhaseCompleted {
+				stored++
+			}
+		}
+		return stored
+	}
+
+	targets, err := replication.Targets(ctx, reader, backup)
+	if err != nil {
+		// Backups whose locations are gone cannot be attributed
+		return 0
+	}
+	stored := int64(0)
+	for _, target := range targets {
+		phase := phases[target.Name]
+		if running && phase == etcdguardianv1alpha1.ReplicaPhaseFailed {
+			continue
+		}
+		if !running && phase != etcdguardianv1alpha1.ReplicaPhaseCompleted {
+			continue
+		}
+		if include != nil && !include(target) {
+			continue
+		}
+		stored++
+	}
+	return stored
+}
+
+// inProgress reports whether a backup passed its quota checks and has not
+// finished yet
+func inProgress(backup *etcdguardianv1alpha1.EtcdBackup) bool {
+	switch backup.Status.Phase {
+	case "", etcdguardianv1alpha1.BackupPhasePending, etcdguardianv1alpha1.BackupPhaseCompleted, etcdguardianv1alpha1.BackupPhaseFailed:
+		return false
+	}
+	return true
+}
+
+// Check returns an *ExceededError when a group with the given usage cannot
+// store a new backup taking request: the quota is used up, or the expected
+// size of the new backup does not fit
+func Check(quota *etcdguardianv1alpha1.StorageQuota, group Group, usage, request Usage) error {
+	if quota.MaxBackups != nil && usage.Backups+request.Backups > *quota.MaxBackups {
+		return &ExceededError{Group: group, Resource: "backups", Used: int64(usage.Backups), Limit: int64(*quota.MaxBackups)}
+	}
+	if quota.MaxBytes != nil && (usage.Bytes >= quota.MaxBytes.Value() || usage.Bytes+request.Bytes > quota.MaxBytes.Value()) {
+		return &ExceededError{Group: group, Resource: "bytes", Used: usage.Bytes, Limit: quota.MaxBytes.Value(), Requested: request.Bytes}
+	}
+	return nil
+}
+
+// SameBucket reports whether two storage locations store into the same
+// bucket of a provider. Quotas of a location apply to the whole bucket,
+// whatever the endpoint and prefix it is reached with.
+func SameBucket(a, b etcdguardianv1alpha1.StorageLocation) bool {
+	return a.Provider == b.Provider && a.Bucket == b.Bucket
+}
+
+// Report returns the usage of every group, sorted by namespace and tenant
+func Report(usage map[Group]Usage) []etcdguardianv1alpha1.StorageUsage {
+	report := []etcdguardianv1alpha1.StorageUsage{}
+	for group, groupUsage := range usage {
+		report = append(report, etcdguardianv1alpha1.StorageUsage{
+			Namespace: group.Namespace,
+			Tenant:    group.Tenant,
+			Backups:   groupUsage.Backups,
+			Bytes:     *resource.NewQuantity(groupUsage.Bytes, resource.BinarySI),
+		})
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Namespace != report[j].Namespace {
+			return report[i].Namespace < report[j].Namespace
+		}
+		return report[i].Tenant < report[j].Tenant
+	})
+	return report
+}
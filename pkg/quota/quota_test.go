@@ -0,0 +1,164 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/replication"
+)
+
+func newFakeClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = etcdguardianv1alpha1.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func namespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+// sharedBucket is the location of the shared storage location
+var sharedBucket = etcdguardianv1alpha1.StorageLocation{
+	Provider: etcdguardianv1alpha1.StorageProviderOSS,
+	Bucket:   "shared",
+	Prefix:   "etcd",
+}
+
+// storedBackup returns a backup of the given size stored in the shared
+// bucket, replicated to a private bucket when replicated is set
+func storedBackup(namespace, name string, size int64, replicated bool) etcdguardianv1alpha1.EtcdBackup {
+	shared := sharedBucket
+	backup := etcdguardianv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       etcdguardianv1alpha1.EtcdBackupSpec{StorageLocation: &shared},
+		Status: etcdguardianv1alpha1.EtcdBackupStatus{
+			Phase:        etcdguardianv1alpha1.BackupPhaseCompleted,
+			SnapshotSize: size,
+			Replicas: []etcdguardianv1alpha1.ReplicaStatus{
+				{Name: replication.PrimaryName, Phase: etcdguardianv1alpha1.ReplicaPhaseCompleted},
+			},
+		},
+	}
+	if replicated {
+		backup.Spec.Replicas = []etcdguardianv1alpha1.ReplicaLocation{{
+			Name:            "private",
+			StorageLocation: &etcdguardianv1alpha1.StorageLocation{Provider: etcdguardianv1alpha1.StorageProviderOSS, Bucket: "private"},
+		}}
+		backup.Status.Replicas = append(backup.Status.Replicas,
+			etcdguardianv1alpha1.ReplicaStatus{Name: "private", Phase: etcdguardianv1alpha1.ReplicaPhaseCompleted})
+	}
+	return backup
+}
+
+func TestCompute(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := newFakeClient(
+		namespace("team-a-dev", map[string]string{"tenant": "team-a"}),
+		namespace("team-a-prod", map[string]string{"tenant": "team-a"}),
+		namespace("team-b", nil),
+	)
+	failed := storedBackup("team-b", "failed", 100, false)
+	failed.Status.Replicas[0].Phase = etcdguardianv1alpha1.ReplicaPhaseFailed
+	// Backups in progress count at the size expected by their preflight
+	// checks, pending ones not yet
+	uploading := storedBackup("team-b", "uploading", 0, false)
+	uploading.Status.Phase = etcdguardianv1alpha1.BackupPhaseUploading
+	uploading.Status.ExpectedSnapshotSize = 30
+	uploading.Status.Replicas = nil
+	pending := storedBackup("team-b", "pending", 0, false)
+	pending.Status.Phase = etcdguardianv1alpha1.BackupPhasePending
+	pending.Status.ExpectedSnapshotSize = 30
+	pending.Status.Replicas = nil
+	backups := []etcdguardianv1alpha1.EtcdBackup{
+		storedBackup("team-a-dev", "nightly", 100, true),
+		storedBackup("team-a-prod", "nightly", 200, false),
+		storedBackup("team-b", "nightly", 50, false),
+		failed,
+		uploading,
+		pending,
+	}
+
+	grouper, err := NewGrouper(ctx, k8sClient, &etcdguardianv1alpha1.StorageQuota{})
+	if err != nil {
+		t.Fatalf("NewGrouper failed: %v", err)
+	}
+	usage := Compute(ctx, k8sClient, grouper, backups, nil)
+	if got := usage[Group{Namespace: "team-a-dev"}]; got != (Usage{Backups: 1, Bytes: 200}) {
+		t.Errorf("Expected both destinations of team-a-dev to count, got %+v", got)
+	}
+	if got := usage[Group{Namespace: "team-b"}]; got != (Usage{Backups: 2, Bytes: 80}) {
+		t.Errorf("Expected the failed and pending backups of team-b not to count, got %+v", got)
+	}
+
+	grouper, err = NewGrouper(ctx, k8sClient, &etcdguardianv1alpha1.StorageQuota{TenantLabel: "tenant"})
+	if err != nil {
+		t.Fatalf("NewGrouper failed: %v", err)
+	}
+	// Other prefixes and endpoints of the bucket count too
+	usage = Compute(ctx, k8sClient, grouper, backups, func(target replication.Target) bool {
+		return SameBucket(target.Location, etcdguardianv1alpha1.StorageLocation{Provider: etcdguardianv1alpha1.StorageProviderOSS, Bucket: "shared", Prefix: "other"})
+	})
+	if got := usage[Group{Tenant: "team-a"}]; got != (Usage{Backups: 2, Bytes: 300}) {
+		t.Errorf("Expected the shared bucket usage of tenant team-a, got %+v", got)
+	}
+	if got := usage[Group{Namespace: "team-b"}]; got != (Usage{Backups: 2, Bytes: 80}) {
+		t.Errorf("Expected namespace team-b without tenant label to be its own group, got %+v", got)
+	}
+
+	report := Report(usage)
+	if len(report) != 2 || report[0].Tenant != "team-a" || report[1].Namespace != "team-b" || report[0].Bytes.Value() != 300 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	maxBackups := int32(2)
+	maxBytes := resource.MustParse("1Ki")
+	quota := &etcdguardianv1alpha1.StorageQuota{MaxBackups: &maxBackups, MaxBytes: &maxBytes}
+	group := Group{Namespace: "team-b"}
+
+	if err := Check(quota, group, Usage{Backups: 1, Bytes: 512}, Usage{Backups: 1, Bytes: 256}); err != nil {
+		t.Errorf("Expected the usage to fit the quota: %v", err)
+	}
+
+	var exceeded *ExceededError
+	if err := Check(quota, group, Usage{Backups: 2, Bytes: 512}, Usage{Backups: 1}); !errors.As(err, &exceeded) || exceeded.Resource != "backups" {
+		t.Errorf("Expected the backup quota to be exceeded, got %v", err)
+	}
+	if err := Check(quota, group, Usage{Backups: 1, Bytes: 1024}, Usage{Backups: 1}); !errors.As(err, &exceeded) || exceeded.Resource != "bytes" {
+		t.Errorf("Expected the size quota to be exceeded, got %v", err)
+	} else if exceeded.Error() != "storage quota of namespace team-b exceeded: 1Ki of 1Ki stored" {
+		t.Errorf("Unexpected message %q", exceeded.Error())
+	}
+	if err := Check(quota, group, Usage{Backups: 1, Bytes: 512}, Usage{Backups: 1, Bytes: 768}); !errors.As(err, &exceeded) || exceeded.Resource != "bytes" {
+		t.Errorf("Expected the expected size not to fit, got %v", err)
+	} else if exceeded.Error() != "storage quota of namespace team-b exceeded: 512 of 1Ki stored, the backup is expected to take 768" {
+		t.Errorf("Unexpected message %q", exceeded.Error())
+	}
+}
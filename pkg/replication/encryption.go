@@ -0,0 +1,93 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/storage"
+)
+
+// ValidateServerSideEncryption checks the server-side encryption settings of
+// the storage destinations of a backup
+func ValidateServerSideEncryption(targets []Target) error {
+	for _, target := range targets {
+		if err := storage.ValidateServerSideEncryption(target.Location); err != nil {
+			return fmt.Errorf("destination %s: %w", target.Name, err)
+		}
+	}
+	return nil
+}
+
+// VerifyServerSideEncryption checks that the snapshot and manifest in every
+// destination holding them are encrypted as its server-side encryption
+// settings require. Destinations that fail the check are marked failed.
+func (r *Replicator) VerifyServerSideEncryption(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup) error {
+	targets, err := r.SyncTargets(ctx, backup)
+	if err != nil {
+		return err
+	}
+
+	failures := []string{}
+	for i, target := range targets {
+		status := &backup.Status.Replicas[i]
+		if target.Location.ServerSideEncryption == nil || status.Phase != etcdguardianv1alpha1.ReplicaPhaseCompleted {
+			continue
+		}
+
+		err := r.verifyServerSideEncryption(ctx, backup, target, status)
+		if err != nil {
+			r.log.Error(err, "Server-side encryption check failed", "destination", target.Name)
+			status.Phase = etcdguardianv1alpha1.ReplicaPhaseFailed
+			status.Reason = storage.Classify(err).Reason()
+			status.Message = err.Error()
+			failures = append(failures, fmt.Sprintf("%s: %v", target.Name, err))
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("server-side encryption check failed: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// verifyServerSideEncryption checks the objects in one destination
+func (r *Replicator) verifyServerSideEncryption(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup, target Target, status *etcdguardianv1alpha1.ReplicaStatus) error {
+	backend, err := r.Backend(backup, target)
+	if err != nil {
+		return fmt.Errorf("failed to create storage backend: %w", err)
+	}
+	verifier, ok := backend.(storage.EncryptionVerifier)
+	if !ok {
+		return fmt.Errorf("storage provider %s does not report server-side encryption", target.Location.Provider)
+	}
+
+	for _, remotePath := range []string{status.Location, status.ManifestLocation} {
+		if remotePath == "" {
+			continue
+		}
+		err := r.Retry(ctx, backup, target.Name, storage.OperationVerifyEncryption, func(ctx context.Context) error {
+			return verifier.VerifyEncryption(ctx, remotePath)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/storage"
+)
+
+// encryptionStorage reports the server-side encryption of the objects of
+// the Filesystem provider, failing the objects marked unencrypted
+type encryptionStorage struct {
+	storage.Storage
+
+	unencrypted map[string]bool
+}
+
+func (s *encryptionStorage) VerifyEncryption(_ context.Context, remotePath string) error {
+	if s.unencrypted[remotePath] {
+		return fmt.Errorf("%s is not encrypted", remotePath)
+	}
+	return nil
+}
+
+func TestReplicator_VerifyServerSideEncryption(t *testing.T) {
+	backup := newTestBackup(t, etcdguardianv1alpha1.ReplicationPolicyAll, t.TempDir())
+	sse := &etcdguardianv1alpha1.ServerSideEncryption{Mode: etcdguardianv1alpha1.ServerSideEncryptionSSES3}
+	backup.Spec.StorageLocation.ServerSideEncryption = sse
+	backup.Spec.Replicas[0].StorageLocation.ServerSideEncryption = sse
+
+	unencrypted := map[string]bool{}
+	replicator := NewReplicator(nil, logr.Discard())
+	replicator.newStorage = func(provider etcdguardianv1alpha1.StorageProvider, location etcdguardianv1alpha1.StorageLocation, k8sClient client.Client, namespace string) (storage.Storage, error) {
+		backend, err := storage.NewStorage(provider, location, k8sClient, namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &encryptionStorage{Storage: backend, unencrypted: unencrypted}, nil
+	}
+
+	if err := replicator.Replicate(context.Background(), backup, writeTestSnapshot(t)); err != nil {
+		t.Fatalf("Replicate failed: %v", err)
+	}
+	if err := replicator.VerifyServerSideEncryption(context.Background(), backup); err != nil {
+		t.Fatalf("Expected encrypted snapshots: %v", err)
+	}
+
+	unencrypted[backup.Status.Replicas[1].ManifestLocation] = true
+	err := replicator.VerifyServerSideEncryption(context.Background(), backup)
+	if err == nil || !strings.Contains(err.Error(), "dr-west") {
+		t.Fatalf("Expected dr-west to fail the check, got %v", err)
+	}
+	if backup.Status.Replicas[0].Phase != etcdguardianv1alpha1.ReplicaPhaseCompleted || backup.Status.Replicas[1].Phase != etcdguardianv1alpha1.ReplicaPhaseFailed {
+		t.Errorf("Expected only dr-west to be failed, got %+v", backup.Status.Replicas)
+	}
+}
+
+func TestValidateServerSideEncryption(t *testing.T) {
+	backup := newTestBackup(t, "")
+	backup.Spec.StorageLocation.ServerSideEncryption = &etcdguardianv1alpha1.ServerSideEncryption{
+		Mode: etcdguardianv1alpha1.ServerSideEncryptionSSEKMS,
+	}
+	if err := ValidateServerSideEncryption(testTargets(t, backup)); err == nil {
+		t.Error("Expected error for server-side encryption of the Filesystem provider")
+	}
+
+	backup.Spec.StorageLocation.Provider = etcdguardianv1alpha1.StorageProviderOSS
+	if err := ValidateServerSideEncryption(testTargets(t, backup)); err != nil {
+		t.Errorf("Expected OSS KMS to be valid: %v", err)
+	}
+
+	backup.Spec.StorageLocation.ServerSideEncryption.Mode = etcdguardianv1alpha1.ServerSideEncryptionSSEC
+	backup.Spec.StorageLocation.ServerSideEncryption.CustomerKeySecret = "sse-key"
+	if err := ValidateServerSideEncryption(testTargets(t, backup)); err == nil {
+		t.Error("Expected error for SSE-C in OSS")
+	}
+
+	backup.Spec.StorageLocation.Provider = etcdguardianv1alpha1.StorageProviderS3
+	if err := ValidateServerSideEncryption(testTargets(t, backup)); err == nil {
+		t.Error("Expected error for server-side encryption of the S3 provider")
+	}
+
+	backup.Spec.StorageLocation.Provider = etcdguardianv1alpha1.StorageProviderGCS
+	if err := ValidateServerSideEncryption(testTargets(t, backup)); err != nil {
+		t.Errorf("Expected GCS customer-supplied keys to be valid: %v", err)
+	}
+
+	backup.Spec.StorageLocation.ServerSideEncryption.CustomerKeySecret = ""
+	if err := ValidateServerSideEncryption(testTargets(t, backup)); err == nil {
+		t.Error("Expected error for SSE-C without a key secret")
+	}
+}
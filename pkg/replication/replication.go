@@ -0,0 +1,516 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/storage"
+)
+
+// PrimaryName is the status name of the storage location of the backup spec
+const PrimaryName = "primary"
+
+// maxStorageAttempts bounds the storage attempts kept in the backup status
+const maxStorageAttempts = 50
+
+// Target is a storage destination of a backup
+type Target struct {
+	Name     string
+	Location etcdguardianv1alpha1.StorageLocation
+
+	// LocationRef is the EtcdBackupStorageLocation the location is taken
+	// from, nil for locations given inline
+	LocationRef *etcdguardianv1alpha1.EtcdBackupStorageLocation
+}
+
+// Targets returns the storage destinations of a backup, the primary first.
+// Locations referenced by name are read from the EtcdBackupStorageLocations
+// in the namespace of the backup.
+func Targets(ctx context.Context, reader client.Reader, backup *etcdguardianv1alpha1.EtcdBackup) ([]Target, error) {
+	primary, err := resolveTarget(ctx, reader, backup.Namespace, PrimaryName, backup.Spec.StorageLocation, backup.Spec.StorageLocationName)
+	if err != nil {
+		return nil, err
+	}
+	targets := []Target{primary}
+	for _, replica := range backup.Spec.Replicas {
+		target, err := resolveTarget(ctx, reader, backup.Namespace, replica.Name, replica.StorageLocation, replica.StorageLocationName)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// Destination returns the storage destination of a backup with the given
+// status name
+func Destination(ctx context.Context, reader client.Reader, backup *etcdguardianv1alpha1.EtcdBackup, name string) (Target, error) {
+	if name == PrimaryName {
+		return resolveTarget(ctx, reader, backup.Namespace, PrimaryName, backup.Spec.StorageLocation, backup.Spec.StorageLocationName)
+	}
+	for _, replica := range backup.Spec.Replicas {
+		if replica.Name == name {
+			return resolveTarget(ctx, reader, backup.Namespace, replica.Name, replica.StorageLocation, replica.StorageLocationName)
+		}
+	}
+	return Target{}, fmt.Errorf("backup has no destination %s", name)
+}
+
+// resolveTarget returns a destination given either inline or by the name
+// of an EtcdBackupStorageLocation
+func resolveTarget(ctx context.Context, reader client.Reader, namespace, name string, location *etcdguardianv1alpha1.StorageLocation, locationName string) (Target, error) {
+	if locationName == "" {
+		if location == nil {
+			return Target{}, fmt.Errorf("destination %s has no storage location", name)
+		}
+		return Target{Name: name, Location: *location}, nil
+	}
+	if reader == nil {
+		return Target{}, fmt.Errorf("no kubernetes client to read storage location %s", locationName)
+	}
+
+	ref := &etcdguardianv1alpha1.EtcdBackupStorageLocation{}
+	if err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: locationName}, ref); err != nil {
+		return Target{}, fmt.Errorf("failed to get storage location %s of destination %s: %w", locationName, name, err)
+	}
+	return Target{Name: name, Location: ref.Spec.StorageLocation, LocationRef: ref}, nil
+}
+
+// ValidateStorageLocation checks that the primary location of a backup is
+// given either inline or by name
+func ValidateStorageLocation(backup *etcdguardianv1alpha1.EtcdBackup) error {
+	return validateLocation(PrimaryName, backup.Spec.StorageLocation, backup.Spec.StorageLocationName)
+}
+
+// ValidateReplicas checks the replica locations of a backup
+func ValidateReplicas(backup *etcdguardianv1alpha1.EtcdBackup) error {
+	names := map[string]bool{PrimaryName: true}
+	for _, replica := range backup.Spec.Replicas {
+		if replica.Name == "" {
+			return fmt.Errorf("replica name is required")
+		}
+		if names[replica.Name] {
+			return fmt.Errorf("duplicate replica name %q", replica.Name)
+		}
+		names[replica.Name] = true
+
+		if err := validateLocation(replica.Name, replica.StorageLocation, replica.StorageLocationName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateLocation checks the location of one destination
+func validateLocation(name string, location *etcdguardianv1alpha1.StorageLocation, locationName string) error {
+	switch {
+	case location != nil && locationName != "":
+		return fmt.Errorf("destination %s sets both a storage location and a storage location name", name)
+	case location == nil && locationName == "":
+		return fmt.Errorf("destination %s needs a storage location or a storage location name", name)
+	case location != nil && location.Bucket == "":
+		return fmt.Errorf("storage bucket of destination %s is required", name)
+	}
+	return nil
+}
+
+// Replicator uploads snapshots and their manifests to the storage
+// destinations of a backup and records the outcome per destination in the
+// backup status
+type Replicator struct {
+	client client.Client
+	log    logr.Logger
+
+	// newStorage creates the storage backend of a destination
+	newStorage func(provider etcdguardianv1alpha1.StorageProvider, location etcdguardianv1alpha1.StorageLocation, k8sClient client.Client, namespace string) (storage.Storage, error)
+
+	// retryPolicy applies to every storage operation
+	retryPolicy storage.RetryPolicy
+
+	// scratchDir holds the snapshots downloaded for copying, the system
+	// temporary directory when empty
+	scratchDir string
+}
+
+// NewReplicator creates a new replicator
+func NewReplicator(k8sClient client.Client, log logr.Logger) *Replicator {
+	return &Replicator{
+		client:      k8sClient,
+		log:         log,
+		newStorage:  storage.NewStorage,
+		retryPolicy: storage.DefaultRetryPolicy,
+	}
+}
+
+// WithRetryPolicy sets the retry policy of the storage operations of the
+// replicator
+func (r *Replicator) WithRetryPolicy(policy storage.RetryPolicy) *Replicator {
+	r.retryPolicy = policy
+	return r
+}
+
+// WithScratchDir sets the directory snapshots are downloaded to before they
+// are copied to the replicas
+func (r *Replicator) WithScratchDir(dir string) *Replicator {
+	r.scratchDir = dir
+	return r
+}
+
+// WithStorage sets how the replicator creates the storage backends of
+// destinations
+func (r *Replicator) WithStorage(newStorage func(provider etcdguardianv1alpha1.StorageProvider, location etcdguardianv1alpha1.StorageLocation, k8sClient client.Client, namespace string) (storage.Storage, error)) *Replicator {
+	r.newStorage = newStorage
+	return r
+}
+
+// Backend creates the storage backend of a destination of a backup
+func (r *Replicator) Backend(backup *etcdguardianv1alpha1.EtcdBackup, target Target) (storage.Storage, error) {
+	return r.newStorage(target.Location.Provider, target.Location, r.client, backup.Namespace)
+}
+
+// SyncTargets returns the storage destinations of a backup and syncs its
+// replica status with them, so that every destination has the status at
+// its index
+func (r *Replicator) SyncTargets(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup) ([]Target, error) {
+	targets, err := Targets(ctx, r.client, backup)
+	if err != nil {
+		return nil, err
+	}
+	SyncStatus(backup, targets)
+	return targets, nil
+}
+
+// Replicate uploads a local snapshot and its manifest to every destination
+// that does not hold it yet. With the PrimaryAsync policy only the primary
+// is uploaded; ReplicateAsync copies to the replicas later. Upload failures
+// are recorded in the status of the destination and are not returned.
+func (r *Replicator) Replicate(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup, snapshotPath string) error {
+	targets, err := Targets(ctx, r.client, backup)
+	if err != nil {
+		return err
+	}
+	async := backup.Spec.ReplicationPolicy == etcdguardianv1alpha1.ReplicationPolicyPrimaryAsync
+	return r.replicate(ctx, backup, targets, snapshotPath, func(target Target) bool {
+		return !async || target.Name == PrimaryName
+	})
+}
+
+// ReplicateAsync copies a completed backup to the replicas still pending. The
+// snapshot is downloaded to the scratch directory from a destination that
+// already holds it.
+func (r *Replicator) ReplicateAsync(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup) error {
+	targets, err := r.SyncTargets(ctx, backup)
+	if err != nil {
+		return err
+	}
+
+	var source *Target
+	var sourceStatus etcdguardianv1alpha1.ReplicaStatus
+	for i, target := range targets {
+		if status := backup.Status.Replicas[i]; status.Phase == etcdguardianv1alpha1.ReplicaPhaseCompleted && status.Location != "" {
+			source, sourceStatus = &target, status
+			break
+		}
+	}
+	if source == nil {
+		return fmt.Errorf("no destination holds the snapshot")
+	}
+
+	sourceStorage, err := r.Backend(backup, *source)
+	if err != nil {
+		return fmt.Errorf("failed to create storage backend of %s: %w", source.Name, err)
+	}
+
+	if r.scratchDir != "" {
+		if err := os.MkdirAll(r.scratchDir, 0o700); err != nil {
+			return fmt.Errorf("failed to create scratch directory: %w", err)
+		}
+	}
+	tmpDir, err := os.MkdirTemp(r.scratchDir, "etcdguardian-replica-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	snapshotPath := filepath.Join(tmpDir, path.Base(sourceStatus.Location))
+	err = r.Retry(ctx, backup, source.Name, storage.OperationDownload, func(ctx context.Context) error {
+		return sourceStorage.Download(ctx, sourceStatus.Location, snapshotPath)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to download snapshot from %s: %w", source.Name, err)
+	}
+
+	return r.replicate(ctx, backup, targets, snapshotPath, func(Target) bool { return true })
+}
+
+// replicate uploads the snapshot and manifest to the pending destinations
+// selected by include
+func (r *Replicator) replicate(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup, targets []Target, snapshotPath string, include func(Target) bool) error {
+	SyncStatus(backup, targets)
+
+	manifest, err := storage.NewManifest(backup, snapshotPath)
+	if err != nil {
+		return err
+	}
+
+	for i, target := range targets {
+		if backup.Status.Replicas[i].Phase == etcdguardianv1alpha1.ReplicaPhaseCompleted || !include(target) {
+			continue
+		}
+
+		location, manifestLocation, err := r.upload(ctx, backup, target, snapshotPath, manifest)
+		// Persisting upload sessions replaces the status, so look it up
+		// only after the upload
+		status := &backup.Status.Replicas[i]
+		if err != nil {
+			r.log.Error(err, "Failed to replicate snapshot", "destination", target.Name)
+			status.Phase = etcdguardianv1alpha1.ReplicaPhaseFailed
+			status.Reason = storage.Classify(err).Reason()
+			status.Message = err.Error()
+			continue
+		}
+
+		r.log.Info("Replicated snapshot", "destination", target.Name, "location", location)
+		status.Phase = etcdguardianv1alpha1.ReplicaPhaseCompleted
+		status.Location = location
+		status.ManifestLocation = manifestLocation
+		status.Hash = manifest.SHA256
+		status.Reason = ""
+		status.Message = ""
+		status.CompletionTime = &metav1.Time{Time: time.Now()}
+		if target.Location.Immutability != nil {
+			status.RetainUntil = &metav1.Time{Time: RetainUntil(backup)}
+			status.LegalHold = backup.Spec.LegalHold
+		}
+	}
+	return nil
+}
+
+// upload stores the snapshot and its manifest in one destination
+func (r *Replicator) upload(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup, target Target, snapshotPath string, manifest *storage.Manifest) (string, string, error) {
+	backend, err := r.Backend(backup, target)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create storage backend: %w", err)
+	}
+
+	// A retried resumable upload continues from the parts of the session
+	// checkpointed by the failed attempt
+	var location string
+	err = r.Retry(ctx, backup, target.Name, storage.OperationUpload, func(ctx context.Context) (err error) {
+		if resumable, ok := backend.(storage.ResumableUploader); ok {
+			location, err = r.uploadResumable(ctx, backup, target, resumable, snapshotPath)
+		} else {
+			location, err = backend.Upload(ctx, snapshotPath, backup)
+		}
+		return err
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to upload snapshot: %w", err)
+	}
+	var manifestLocation string
+	err = r.Retry(ctx, backup, target.Name, storage.OperationUploadManifest, func(ctx context.Context) (err error) {
+		manifestLocation, err = storage.PutManifest(ctx, backend, location, snapshotPath, manifest, backup)
+		return err
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to upload manifest: %w", err)
+	}
+
+	if immutability := target.Location.Immutability; immutability != nil {
+		locker, ok := backend.(storage.ObjectLocker)
+		if !ok {
+			return "", "", fmt.Errorf("storage provider %s does not support immutability", target.Location.Provider)
+		}
+		for _, remotePath := range []string{location, manifestLocation} {
+			err := r.Retry(ctx, backup, target.Name, storage.OperationLock, func(ctx context.Context) error {
+				return locker.LockObject(ctx, remotePath, immutability.Mode, RetainUntil(backup))
+			})
+			if err != nil {
+				return "", "", fmt.Errorf("failed to lock %s: %w", remotePath, err)
+			}
+			if backup.Spec.LegalHold {
+				err := r.Retry(ctx, backup, target.Name, storage.OperationLegalHold, func(ctx context.Context) error {
+					return locker.SetLegalHold(ctx, remotePath, true)
+				})
+				if err != nil {
+					return "", "", fmt.Errorf("failed to place legal hold on %s: %w", remotePath, err)
+				}
+			}
+		}
+	}
+	return location, manifestLocation, nil
+}
+
+// Retry runs a storage operation on a destination with the retry policy of
+// the replicator, recording every attempt in the backup status
+func (r *Replicator) Retry(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup, destination string, operation storage.Operation, fn func(ctx context.Context) error) error {
+	return storage.Retry(ctx, r.retryPolicy, operation, func(attempt storage.Attempt) {
+		if attempt.Err != nil && attempt.Class.Retryable() {
+			r.log.Info("Storage operation failed, retrying", "destination", destination, "operation", operation, "attempt", attempt.Number, "error", attempt.Err.Error())
+		}
+		recordAttempt(backup, destination, attempt)
+	}, fn)
+}
+
+// recordAttempt appends a storage attempt to the backup status, dropping
+// the oldest attempts beyond maxStorageAttempts
+func recordAttempt(backup *etcdguardianv1alpha1.EtcdBackup, destination string, attempt storage.Attempt) {
+	record := etcdguardianv1alpha1.StorageAttempt{
+		Destination: destination,
+		Operation:   string(attempt.Operation),
+		Attempt:     int32(attempt.Number),
+		Time:        metav1.Time{Time: attempt.Start},
+		Result:      "Succeeded",
+	}
+	if attempt.Err != nil {
+		record.Result = string(attempt.Class)
+		record.Message = attempt.Err.Error()
+	}
+
+	attempts := append(backup.Status.StorageAttempts, record)
+	if len(attempts) > maxStorageAttempts {
+		attempts = append([]etcdguardianv1alpha1.StorageAttempt(nil), attempts[len(attempts)-maxStorageAttempts:]...)
+	}
+	backup.Status.StorageAttempts = attempts
+}
+
+// FailedAttempts returns the number of failed attempts of an operation on a
+// destination recorded in the backup status
+func FailedAttempts(backup *etcdguardianv1alpha1.EtcdBackup, destination string, operation storage.Operation) int {
+	failed := 0
+	for _, attempt := range backup.Status.StorageAttempts {
+		if attempt.Destination == destination && attempt.Operation == string(operation) && attempt.Result != "Succeeded" {
+			failed++
+		}
+	}
+	return failed
+}
+
+// SyncStatus makes the replica status of a backup list every destination in
+// order, keeping the state of destinations already known
+func SyncStatus(backup *etcdguardianv1alpha1.EtcdBackup, targets []Target) {
+	existing := map[string]etcdguardianv1alpha1.ReplicaStatus{}
+	for _, status := range backup.Status.Replicas {
+		existing[status.Name] = status
+	}
+
+	statuses := []etcdguardianv1alpha1.ReplicaStatus{}
+	for _, target := range targets {
+		status, ok := existing[target.Name]
+		if !ok {
+			status = etcdguardianv1alpha1.ReplicaStatus{
+				Name:  target.Name,
+				Phase: etcdguardianv1alpha1.ReplicaPhasePending,
+			}
+		}
+		status.Provider = target.Location.Provider
+		statuses = append(statuses, status)
+	}
+	backup.Status.Replicas = statuses
+}
+
+// Evaluate applies the replication policy of a backup to the state of its
+// destinations. It reports whether the backup is complete, and returns an
+// error once the policy can no longer be met.
+func Evaluate(backup *etcdguardianv1alpha1.EtcdBackup) (bool, error) {
+	total := len(backup.Status.Replicas)
+	completed, failed := 0, 0
+	failures := []string{}
+	for _, status := range backup.Status.Replicas {
+		switch status.Phase {
+		case etcdguardianv1alpha1.ReplicaPhaseCompleted:
+			completed++
+		case etcdguardianv1alpha1.ReplicaPhaseFailed:
+			failed++
+			failures = append(failures, fmt.Sprintf("%s: %s", status.Name, status.Message))
+		}
+	}
+	if total == 0 {
+		return false, nil
+	}
+
+	switch backup.Spec.ReplicationPolicy {
+	case etcdguardianv1alpha1.ReplicationPolicyQuorum:
+		quorum := total/2 + 1
+		if completed >= quorum {
+			return true, nil
+		}
+		if total-failed < quorum {
+			return false, fmt.Errorf("replication quorum of %d/%d destinations not reached: %s", quorum, total, strings.Join(failures, "; "))
+		}
+	case etcdguardianv1alpha1.ReplicationPolicyPrimaryAsync:
+		switch backup.Status.Replicas[0].Phase {
+		case etcdguardianv1alpha1.ReplicaPhaseCompleted:
+			return true, nil
+		case etcdguardianv1alpha1.ReplicaPhaseFailed:
+			return false, fmt.Errorf("replication to primary failed: %s", backup.Status.Replicas[0].Message)
+		}
+	default:
+		if failed > 0 {
+			return false, fmt.Errorf("replication failed: %s", strings.Join(failures, "; "))
+		}
+		if completed == total {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// FailureReason returns the machine-readable reason of the first failed
+// destination of a backup, the primary first
+func FailureReason(backup *etcdguardianv1alpha1.EtcdBackup) string {
+	for _, status := range backup.Status.Replicas {
+		if status.Phase == etcdguardianv1alpha1.ReplicaPhaseFailed && status.Reason != "" {
+			return status.Reason
+		}
+	}
+	return ""
+}
+
+// SnapshotLocation returns the location of the snapshot in the primary, or
+// in the first replica holding it when the primary does not
+func SnapshotLocation(backup *etcdguardianv1alpha1.EtcdBackup) string {
+	for _, status := range backup.Status.Replicas {
+		if status.Phase == etcdguardianv1alpha1.ReplicaPhaseCompleted {
+			return status.Location
+		}
+	}
+	return ""
+}
+
+// HasPendingReplicas reports whether a backup has destinations it was not
+// yet copied to
+func HasPendingReplicas(backup *etcdguardianv1alpha1.EtcdBackup) bool {
+	for _, status := range backup.Status.Replicas {
+		if status.Phase == etcdguardianv1alpha1.ReplicaPhasePending {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,433 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/storage"
+)
+
+func TestMain(m *testing.M) {
+	storage.EnableMemoryStorage()
+	os.Exit(m.Run())
+}
+
+func filesystemLocation(root string) *etcdguardianv1alpha1.StorageLocation {
+	return &etcdguardianv1alpha1.StorageLocation{
+		Provider: etcdguardianv1alpha1.StorageProviderFilesystem,
+		Bucket:   root,
+	}
+}
+
+// newTestBackup returns a backup replicated to the given replica roots. A
+// relative root makes the Filesystem provider fail.
+func newTestBackup(t *testing.T, policy etcdguardianv1alpha1.ReplicationPolicy, replicaRoots ...string) *etcdguardianv1alpha1.EtcdBackup {
+	backup := &etcdguardianv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+		Spec: etcdguardianv1alpha1.EtcdBackupSpec{
+			BackupMode:        etcdguardianv1alpha1.BackupModeFull,
+			StorageLocation:   filesystemLocation(t.TempDir()),
+			ReplicationPolicy: policy,
+		},
+	}
+	for i, root := range replicaRoots {
+		backup.Spec.Replicas = append(backup.Spec.Replicas, etcdguardianv1alpha1.ReplicaLocation{
+			Name:            []string{"dr-west", "dr-east", "dr-north"}[i],
+			StorageLocation: filesystemLocation(root),
+		})
+	}
+	return backup
+}
+
+// testTargets returns the destinations of a backup with inline locations
+func testTargets(t *testing.T, backup *etcdguardianv1alpha1.EtcdBackup) []Target {
+	t.Helper()
+	targets, err := Targets(context.Background(), nil, backup)
+	if err != nil {
+		t.Fatalf("Failed to resolve destinations: %v", err)
+	}
+	return targets
+}
+
+func writeTestSnapshot(t *testing.T) string {
+	t.Helper()
+
+	snapshotPath := filepath.Join(t.TempDir(), "etcd-snapshot.db")
+	if err := os.WriteFile(snapshotPath, []byte("snapshot data"), 0o600); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+	return snapshotPath
+}
+
+func TestReplicator_All(t *testing.T) {
+	backup := newTestBackup(t, etcdguardianv1alpha1.ReplicationPolicyAll, t.TempDir(), t.TempDir())
+	replicator := NewReplicator(nil, logr.Discard())
+
+	if err := replicator.Replicate(context.Background(), backup, writeTestSnapshot(t)); err != nil {
+		t.Fatalf("Replicate failed: %v", err)
+	}
+
+	complete, err := Evaluate(backup)
+	if err != nil || !complete {
+		t.Fatalf("Expected complete backup, got %v, %v", complete, err)
+	}
+	if len(backup.Status.Replicas) != 3 || backup.Status.Replicas[0].Name != PrimaryName {
+		t.Fatalf("Unexpected replica status %+v", backup.Status.Replicas)
+	}
+	for i, status := range backup.Status.Replicas {
+		root := testTargets(t, backup)[i].Location.Bucket
+		if !strings.HasPrefix(status.Location, "file://"+root) {
+			t.Errorf("Replica %s stored at unexpected location %s", status.Name, status.Location)
+		}
+		if status.ManifestLocation != status.Location+storage.ManifestSuffix {
+			t.Errorf("Replica %s has unexpected manifest location %s", status.Name, status.ManifestLocation)
+		}
+		if status.Hash == "" || status.Hash != backup.Status.Replicas[0].Hash {
+			t.Errorf("Replica %s has unexpected hash %q", status.Name, status.Hash)
+		}
+
+		manifest, err := storage.ReadManifest(strings.TrimPrefix(status.ManifestLocation, "file://"))
+		if err != nil {
+			t.Fatalf("Failed to read manifest: %v", err)
+		}
+		if manifest.SHA256 != status.Hash || manifest.BackupName != "nightly" {
+			t.Errorf("Unexpected manifest %+v", manifest)
+		}
+	}
+	if SnapshotLocation(backup) != backup.Status.Replicas[0].Location {
+		t.Errorf("Expected primary snapshot location, got %s", SnapshotLocation(backup))
+	}
+}
+
+func TestReplicator_AllFailsOnReplicaFailure(t *testing.T) {
+	backup := newTestBackup(t, etcdguardianv1alpha1.ReplicationPolicyAll, t.TempDir(), "relative")
+	replicator := NewReplicator(nil, logr.Discard())
+
+	if err := replicator.Replicate(context.Background(), backup, writeTestSnapshot(t)); err != nil {
+		t.Fatalf("Replicate failed: %v", err)
+	}
+
+	if _, err := Evaluate(backup); err == nil || !strings.Contains(err.Error(), "dr-east") {
+		t.Errorf("Expected replication failure of dr-east, got %v", err)
+	}
+	if backup.Status.Replicas[2].Phase != etcdguardianv1alpha1.ReplicaPhaseFailed || backup.Status.Replicas[2].Message == "" {
+		t.Errorf("Expected failed replica status, got %+v", backup.Status.Replicas[2])
+	}
+}
+
+func TestReplicator_Quorum(t *testing.T) {
+	replicator := NewReplicator(nil, logr.Discard())
+
+	backup := newTestBackup(t, etcdguardianv1alpha1.ReplicationPolicyQuorum, t.TempDir(), "relative")
+	if err := replicator.Replicate(context.Background(), backup, writeTestSnapshot(t)); err != nil {
+		t.Fatalf("Replicate failed: %v", err)
+	}
+	if complete, err := Evaluate(backup); err != nil || !complete {
+		t.Errorf("Expected 2 of 3 destinations to reach quorum, got %v, %v", complete, err)
+	}
+
+	backup = newTestBackup(t, etcdguardianv1alpha1.ReplicationPolicyQuorum, "relative", "relative")
+	if err := replicator.Replicate(context.Background(), backup, writeTestSnapshot(t)); err != nil {
+		t.Fatalf("Replicate failed: %v", err)
+	}
+	if _, err := Evaluate(backup); err == nil {
+		t.Error("Expected 1 of 3 destinations to miss quorum")
+	}
+}
+
+func TestReplicator_PrimaryAsync(t *testing.T) {
+	backup := newTestBackup(t, etcdguardianv1alpha1.ReplicationPolicyPrimaryAsync, t.TempDir())
+	replicator := NewReplicator(nil, logr.Discard())
+	ctx := context.Background()
+
+	snapshotPath := writeTestSnapshot(t)
+	if err := replicator.Replicate(ctx, backup, snapshotPath); err != nil {
+		t.Fatalf("Replicate failed: %v", err)
+	}
+	if complete, err := Evaluate(backup); err != nil || !complete {
+		t.Fatalf("Expected complete backup once the primary holds it, got %v, %v", complete, err)
+	}
+	if !HasPendingReplicas(backup) {
+		t.Fatal("Expected replica to be copied asynchronously")
+	}
+
+	// The local snapshot is gone by the time the replicas are copied
+	if err := os.Remove(snapshotPath); err != nil {
+		t.Fatalf("Failed to remove snapshot: %v", err)
+	}
+	if err := replicator.ReplicateAsync(ctx, backup); err != nil {
+		t.Fatalf("ReplicateAsync failed: %v", err)
+	}
+	if HasPendingReplicas(backup) {
+		t.Errorf("Expected no pending replicas, got %+v", backup.Status.Replicas)
+	}
+	replica := backup.Status.Replicas[1]
+	if replica.Phase != etcdguardianv1alpha1.ReplicaPhaseCompleted || replica.Hash != backup.Status.Replicas[0].Hash {
+		t.Errorf("Unexpected replica status %+v", replica)
+	}
+	if filepath.Base(replica.Location) != filepath.Base(backup.Status.Replicas[0].Location) {
+		t.Errorf("Expected replica to keep the snapshot name, got %s", replica.Location)
+	}
+}
+
+// downloadingStorage records the local paths snapshots are downloaded to
+type downloadingStorage struct {
+	storage.Storage
+
+	localPaths *[]string
+}
+
+func (s *downloadingStorage) Download(ctx context.Context, remotePath, localPath string) error {
+	*s.localPaths = append(*s.localPaths, localPath)
+	return s.Storage.Download(ctx, remotePath, localPath)
+}
+
+func TestReplicator_ReplicateAsyncUsesScratchDir(t *testing.T) {
+	backup := newTestBackup(t, etcdguardianv1alpha1.ReplicationPolicyPrimaryAsync, t.TempDir())
+	scratchDir := filepath.Join(t.TempDir(), "snapshots")
+	ctx := context.Background()
+
+	if err := NewReplicator(nil, logr.Discard()).Replicate(ctx, backup, writeTestSnapshot(t)); err != nil {
+		t.Fatalf("Replicate failed: %v", err)
+	}
+
+	localPaths := []string{}
+	replicator := NewReplicator(nil, logr.Discard()).WithScratchDir(scratchDir).WithStorage(
+		func(provider etcdguardianv1alpha1.StorageProvider, location etcdguardianv1alpha1.StorageLocation, k8sClient client.Client, namespace string) (storage.Storage, error) {
+			backend, err := storage.NewStorage(provider, location, k8sClient, namespace)
+			return &downloadingStorage{Storage: backend, localPaths: &localPaths}, err
+		})
+	if err := replicator.ReplicateAsync(ctx, backup); err != nil {
+		t.Fatalf("ReplicateAsync failed: %v", err)
+	}
+	if HasPendingReplicas(backup) {
+		t.Fatalf("Expected no pending replicas, got %+v", backup.Status.Replicas)
+	}
+
+	if len(localPaths) != 1 || !strings.HasPrefix(localPaths[0], scratchDir+string(filepath.Separator)) {
+		t.Errorf("Expected the snapshot to be downloaded below %s, got %v", scratchDir, localPaths)
+	}
+	entries, err := os.ReadDir(scratchDir)
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Expected the scratch directory to be cleaned up, got %v, %v", entries, err)
+	}
+}
+
+func TestValidateReplicas(t *testing.T) {
+	backup := newTestBackup(t, "", t.TempDir(), t.TempDir())
+	if err := ValidateReplicas(backup); err != nil {
+		t.Errorf("Expected valid replicas: %v", err)
+	}
+
+	backup.Spec.Replicas[1].Name = "dr-west"
+	if err := ValidateReplicas(backup); err == nil {
+		t.Error("Expected error for duplicate replica name")
+	}
+
+	backup.Spec.Replicas[1].Name = PrimaryName
+	if err := ValidateReplicas(backup); err == nil {
+		t.Error("Expected error for replica named primary")
+	}
+}
+
+func newFakeClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = etcdguardianv1alpha1.AddToScheme(scheme)
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&etcdguardianv1alpha1.EtcdBackup{}).
+		Build()
+}
+
+// flakyStorage fails the first uploads with an error
+type flakyStorage struct {
+	storage.Storage
+
+	err      error
+	failures int
+}
+
+func (s *flakyStorage) Upload(ctx context.Context, localPath string, backup *etcdguardianv1alpha1.EtcdBackup) (string, error) {
+	if s.failures > 0 {
+		s.failures--
+		return "", s.err
+	}
+	return s.Storage.Upload(ctx, localPath, backup)
+}
+
+// useFlakyStorage makes the replicator wrap the backend of a bucket in
+// flakyStorage and retry without waiting
+func useFlakyStorage(replicator *Replicator, flaky *flakyStorage, bucket string) {
+	replicator.retryPolicy = storage.RetryPolicy{MaxAttempts: 3, Timeout: time.Minute}
+	replicator.newStorage = func(provider etcdguardianv1alpha1.StorageProvider, location etcdguardianv1alpha1.StorageLocation, k8sClient client.Client, namespace string) (storage.Storage, error) {
+		backend, err := storage.NewStorage(provider, location, k8sClient, namespace)
+		if err != nil || location.Bucket != bucket {
+			return backend, err
+		}
+		flaky.Storage = backend
+		return flaky, nil
+	}
+}
+
+func TestReplicator_RetriesTransientStorageErrors(t *testing.T) {
+	backup := newTestBackup(t, etcdguardianv1alpha1.ReplicationPolicyAll, t.TempDir())
+	replicator := NewReplicator(nil, logr.Discard())
+	useFlakyStorage(replicator, &flakyStorage{err: fmt.Errorf("write: %w", syscall.ECONNRESET), failures: 2}, backup.Spec.Replicas[0].StorageLocation.Bucket)
+
+	if err := replicator.Replicate(context.Background(), backup, writeTestSnapshot(t)); err != nil {
+		t.Fatalf("Replicate failed: %v", err)
+	}
+	if complete, err := Evaluate(backup); !complete || err != nil {
+		t.Fatalf("Expected replication to complete, got %v (%+v)", err, backup.Status.Replicas)
+	}
+
+	results := []string{}
+	for _, attempt := range backup.Status.StorageAttempts {
+		if attempt.Destination == "dr-west" && attempt.Operation == string(storage.OperationUpload) {
+			results = append(results, fmt.Sprintf("%d:%s", attempt.Attempt, attempt.Result))
+		}
+	}
+	if strings.Join(results, ",") != "1:Retryable,2:Retryable,3:Succeeded" {
+		t.Errorf("Unexpected upload attempts %v", results)
+	}
+}
+
+func TestReplicator_MemoryStorageFaults(t *testing.T) {
+	t.Cleanup(storage.ResetMemoryStorage)
+	backup := newTestBackup(t, etcdguardianv1alpha1.ReplicationPolicyAll)
+	backup.Spec.StorageLocation = &etcdguardianv1alpha1.StorageLocation{Provider: storage.ProviderMemory, Bucket: "primary"}
+	backup.Spec.Replicas = []etcdguardianv1alpha1.ReplicaLocation{{
+		Name:            "dr-west",
+		StorageLocation: &etcdguardianv1alpha1.StorageLocation{Provider: storage.ProviderMemory, Bucket: "dr-west"},
+	}}
+	storage.MemoryFaults("dr-west").Add(storage.Fault{
+		Operations: []storage.FaultOperation{storage.FaultPut},
+		Err:        &storage.InjectedError{Class: storage.ErrorClassQuota},
+	})
+
+	replicator := NewReplicator(nil, logr.Discard())
+	if err := replicator.Replicate(context.Background(), backup, writeTestSnapshot(t)); err != nil {
+		t.Fatalf("Replicate failed: %v", err)
+	}
+	if replica := backup.Status.Replicas[0]; replica.Phase != etcdguardianv1alpha1.ReplicaPhaseCompleted || !strings.HasPrefix(replica.Location, "memory://primary/") {
+		t.Errorf("Expected the snapshot in the primary bucket, got %+v", replica)
+	}
+	if replica := backup.Status.Replicas[1]; replica.Phase != etcdguardianv1alpha1.ReplicaPhaseFailed || replica.Reason != "StorageQuotaExceeded" {
+		t.Errorf("Expected the replica to fail on its quota, got %+v", replica)
+	}
+}
+
+func TestReplicator_FailsFastOnPermanentStorageErrors(t *testing.T) {
+	backup := newTestBackup(t, etcdguardianv1alpha1.ReplicationPolicyAll, t.TempDir())
+	replicator := NewReplicator(nil, logr.Discard())
+	flaky := &flakyStorage{err: fmt.Errorf("open: %w", os.ErrPermission), failures: 3}
+	useFlakyStorage(replicator, flaky, backup.Spec.Replicas[0].StorageLocation.Bucket)
+
+	if err := replicator.Replicate(context.Background(), backup, writeTestSnapshot(t)); err != nil {
+		t.Fatalf("Replicate failed: %v", err)
+	}
+	if flaky.failures != 2 {
+		t.Errorf("Expected a single upload attempt, got %d", 3-flaky.failures)
+	}
+
+	replica := backup.Status.Replicas[1]
+	if replica.Phase != etcdguardianv1alpha1.ReplicaPhaseFailed || replica.Reason != "StorageAuthFailed" {
+		t.Errorf("Unexpected replica status %+v", replica)
+	}
+	if reason := FailureReason(backup); reason != "StorageAuthFailed" {
+		t.Errorf("Expected failure reason StorageAuthFailed, got %q", reason)
+	}
+}
+
+func TestRecordAttempt_KeepsMostRecent(t *testing.T) {
+	backup := newTestBackup(t, etcdguardianv1alpha1.ReplicationPolicyAll)
+	for i := 1; i <= maxStorageAttempts+5; i++ {
+		recordAttempt(backup, PrimaryName, storage.Attempt{Operation: storage.OperationUpload, Number: i})
+	}
+
+	attempts := backup.Status.StorageAttempts
+	if len(attempts) != maxStorageAttempts || attempts[0].Attempt != 6 || attempts[len(attempts)-1].Attempt != maxStorageAttempts+5 {
+		t.Errorf("Expected the last %d attempts, got %d from %d", maxStorageAttempts, len(attempts), attempts[0].Attempt)
+	}
+}
+
+func TestTargets_ResolvesStorageLocationNames(t *testing.T) {
+	root := t.TempDir()
+	location := &etcdguardianv1alpha1.EtcdBackupStorageLocation{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "default"},
+		Spec:       etcdguardianv1alpha1.EtcdBackupStorageLocationSpec{StorageLocation: *filesystemLocation(root)},
+	}
+	backup := newTestBackup(t, etcdguardianv1alpha1.ReplicationPolicyAll, t.TempDir())
+	backup.Spec.StorageLocation = nil
+	backup.Spec.StorageLocationName = "shared"
+	ctx := context.Background()
+
+	targets, err := Targets(ctx, newFakeClient(location), backup)
+	if err != nil {
+		t.Fatalf("Targets failed: %v", err)
+	}
+	if targets[0].Location.Bucket != root || targets[0].LocationRef == nil || targets[0].LocationRef.Name != "shared" {
+		t.Errorf("Unexpected primary %+v", targets[0])
+	}
+	if targets[1].LocationRef != nil || targets[1].Location.Bucket != backup.Spec.Replicas[0].StorageLocation.Bucket {
+		t.Errorf("Unexpected replica %+v", targets[1])
+	}
+
+	backup.Spec.StorageLocationName = "missing"
+	if _, err := Targets(ctx, newFakeClient(location), backup); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
+func TestValidateStorageLocation(t *testing.T) {
+	backup := newTestBackup(t, etcdguardianv1alpha1.ReplicationPolicyAll)
+	if err := ValidateStorageLocation(backup); err != nil {
+		t.Errorf("Expected inline location to be valid, got %v", err)
+	}
+
+	backup.Spec.StorageLocationName = "shared"
+	if err := ValidateStorageLocation(backup); err == nil {
+		t.Error("Expected a location given both inline and by name to be rejected")
+	}
+
+	backup.Spec.StorageLocation = nil
+	if err := ValidateStorageLocation(backup); err != nil {
+		t.Errorf("Expected location name to be valid, got %v", err)
+	}
+
+	backup.Spec.StorageLocationName = ""
+	if err := ValidateStorageLocation(backup); err == nil {
+		t.Error("Expected a missing location to be rejected")
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/storage"
+)
+
+// ValidateImmutability checks the immutability settings of the storage
+// destinations of a backup. Immutable snapshots are retained for the MaxAge
+// of the retention policy, which is therefore required.
+func ValidateImmutability(backup *etcdguardianv1alpha1.EtcdBackup, targets []Target) error {
+	for _, target := range targets {
+		if target.Location.Immutability == nil {
+			continue
+		}
+		if err := storage.ValidateImmutability(target.Location, backup.Spec.LegalHold); err != nil {
+			return fmt.Errorf("destination %s: %w", target.Name, err)
+		}
+		if backup.Spec.RetentionPolicy == nil || backup.Spec.RetentionPolicy.MaxAge == nil {
+			return fmt.Errorf("destination %s is immutable and needs the maxAge of the retention policy", target.Name)
+		}
+	}
+	return nil
+}
+
+// RetainUntil returns when the retention of the immutable snapshots of a
+// backup ends: its start time plus the MaxAge of its retention policy
+func RetainUntil(backup *etcdguardianv1alpha1.EtcdBackup) time.Time {
+	start := backup.CreationTimestamp.Time
+	if backup.Status.StartTime != nil {
+		start = backup.Status.StartTime.Time
+	}
+	if backup.Spec.RetentionPolicy == nil || backup.Spec.RetentionPolicy.MaxAge == nil {
+		return start
+	}
+	return start.Add(backup.Spec.RetentionPolicy.MaxAge.Duration)
+}
+
+// LegalHoldPending reports whether a stored snapshot of a backup has a legal
+// hold that differs from the backup spec. Only immutable snapshots, which
+// have a retention in their status, are held.
+func LegalHoldPending(backup *etcdguardianv1alpha1.EtcdBackup) bool {
+	for _, status := range backup.Status.Replicas {
+		if status.RetainUntil != nil &&
+			status.Phase == etcdguardianv1alpha1.ReplicaPhaseCompleted && status.LegalHold != backup.Spec.LegalHold {
+			return true
+		}
+	}
+	return false
+}
+
+// SyncLegalHold places or releases the legal hold on the snapshot and
+// manifest in every immutable destination holding them, so that it matches
+// the backup spec. The hold applied is recorded in the destination status.
+func (r *Replicator) SyncLegalHold(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup) error {
+	targets, err := r.SyncTargets(ctx, backup)
+	if err != nil {
+		return err
+	}
+
+	failures := []string{}
+	for i, target := range targets {
+		status := &backup.Status.Replicas[i]
+		if target.Location.Immutability == nil || status.Phase != etcdguardianv1alpha1.ReplicaPhaseCompleted || status.LegalHold == backup.Spec.LegalHold {
+			continue
+		}
+
+		backend, err := r.Backend(backup, target)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", target.Name, err))
+			continue
+		}
+		locker, ok := backend.(storage.ObjectLocker)
+		if !ok {
+			failures = append(failures, fmt.Sprintf("%s: storage provider %s does not support legal holds", target.Name, target.Location.Provider))
+			continue
+		}
+
+		var holdErr error
+		for _, remotePath := range []string{status.Location, status.ManifestLocation} {
+			if remotePath == "" {
+				continue
+			}
+			holdErr = r.Retry(ctx, backup, target.Name, storage.OperationLegalHold, func(ctx context.Context) error {
+				return locker.SetLegalHold(ctx, remotePath, backup.Spec.LegalHold)
+			})
+			if holdErr != nil {
+				break
+			}
+		}
+		if holdErr != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", target.Name, holdErr))
+			continue
+		}
+
+		r.log.Info("Updated legal hold", "destination", target.Name, "legalHold", backup.Spec.LegalHold)
+		status.LegalHold = backup.Spec.LegalHold
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to update legal hold: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
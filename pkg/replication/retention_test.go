@@ -0,0 +1,171 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/storage"
+)
+
+// lockingStorage records the retention and legal holds applied to the
+// objects of the Filesystem provider
+type lockingStorage struct {
+	storage.Storage
+
+	retainUntil map[string]time.Time
+	modes       map[string]etcdguardianv1alpha1.ImmutabilityMode
+	holds       map[string]bool
+}
+
+func (s *lockingStorage) LockObject(_ context.Context, remotePath string, mode etcdguardianv1alpha1.ImmutabilityMode, retainUntil time.Time) error {
+	s.retainUntil[remotePath] = retainUntil
+	s.modes[remotePath] = mode
+	return nil
+}
+
+func (s *lockingStorage) SetLegalHold(_ context.Context, remotePath string, hold bool) error {
+	s.holds[remotePath] = hold
+	return nil
+}
+
+// useLockingStorage makes the replicator wrap the backends of immutable
+// locations in lockingStorage
+func useLockingStorage(replicator *Replicator, locker *lockingStorage) {
+	replicator.newStorage = func(provider etcdguardianv1alpha1.StorageProvider, location etcdguardianv1alpha1.StorageLocation, k8sClient client.Client, namespace string) (storage.Storage, error) {
+		backend, err := storage.NewStorage(provider, location, k8sClient, namespace)
+		if err != nil || location.Immutability == nil {
+			return backend, err
+		}
+		return &lockingStorage{Storage: backend, retainUntil: locker.retainUntil, modes: locker.modes, holds: locker.holds}, nil
+	}
+}
+
+func newImmutableBackup(t *testing.T) *etcdguardianv1alpha1.EtcdBackup {
+	backup := newTestBackup(t, etcdguardianv1alpha1.ReplicationPolicyAll, t.TempDir())
+	backup.Spec.Replicas[0].StorageLocation.Immutability = &etcdguardianv1alpha1.ImmutabilityConfig{
+		Mode: etcdguardianv1alpha1.ImmutabilityModeCompliance,
+	}
+	backup.Spec.RetentionPolicy = &etcdguardianv1alpha1.RetentionPolicy{
+		MaxAge: &metav1.Duration{Duration: 720 * time.Hour},
+	}
+	backup.Status.StartTime = &metav1.Time{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	return backup
+}
+
+func TestReplicator_LocksImmutableDestinations(t *testing.T) {
+	backup := newImmutableBackup(t)
+	backup.Spec.LegalHold = true
+	replicator := NewReplicator(nil, logr.Discard())
+	locker := &lockingStorage{retainUntil: map[string]time.Time{}, modes: map[string]etcdguardianv1alpha1.ImmutabilityMode{}, holds: map[string]bool{}}
+	useLockingStorage(replicator, locker)
+
+	if err := replicator.Replicate(context.Background(), backup, writeTestSnapshot(t)); err != nil {
+		t.Fatalf("Replicate failed: %v", err)
+	}
+
+	retainUntil := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	replica := backup.Status.Replicas[1]
+	if replica.Phase != etcdguardianv1alpha1.ReplicaPhaseCompleted || replica.RetainUntil == nil || !replica.RetainUntil.Time.Equal(retainUntil) || !replica.LegalHold {
+		t.Fatalf("Unexpected replica status %+v", replica)
+	}
+	for _, remotePath := range []string{replica.Location, replica.ManifestLocation} {
+		if !locker.retainUntil[remotePath].Equal(retainUntil) || locker.modes[remotePath] != etcdguardianv1alpha1.ImmutabilityModeCompliance || !locker.holds[remotePath] {
+			t.Errorf("Expected %s to be locked until %s with a legal hold", remotePath, retainUntil)
+		}
+	}
+
+	primary := backup.Status.Replicas[0]
+	if primary.RetainUntil != nil || primary.LegalHold || len(locker.retainUntil) != 2 {
+		t.Errorf("Expected the primary not to be locked, got %+v", primary)
+	}
+}
+
+func TestReplicator_SyncLegalHold(t *testing.T) {
+	backup := newImmutableBackup(t)
+	replicator := NewReplicator(nil, logr.Discard())
+	locker := &lockingStorage{retainUntil: map[string]time.Time{}, modes: map[string]etcdguardianv1alpha1.ImmutabilityMode{}, holds: map[string]bool{}}
+	useLockingStorage(replicator, locker)
+
+	if err := replicator.Replicate(context.Background(), backup, writeTestSnapshot(t)); err != nil {
+		t.Fatalf("Replicate failed: %v", err)
+	}
+	if LegalHoldPending(backup) || len(locker.holds) != 0 {
+		t.Fatalf("Expected no legal hold, got %v", locker.holds)
+	}
+
+	backup.Spec.LegalHold = true
+	if !LegalHoldPending(backup) {
+		t.Fatal("Expected the legal hold to be pending")
+	}
+	if err := replicator.SyncLegalHold(context.Background(), backup); err != nil {
+		t.Fatalf("SyncLegalHold failed: %v", err)
+	}
+	replica := backup.Status.Replicas[1]
+	if !replica.LegalHold || !locker.holds[replica.Location] || !locker.holds[replica.ManifestLocation] || LegalHoldPending(backup) {
+		t.Errorf("Expected the legal hold to be placed, got %+v, %v", replica, locker.holds)
+	}
+
+	backup.Spec.LegalHold = false
+	if err := replicator.SyncLegalHold(context.Background(), backup); err != nil {
+		t.Fatalf("SyncLegalHold failed: %v", err)
+	}
+	if backup.Status.Replicas[1].LegalHold || locker.holds[replica.Location] {
+		t.Errorf("Expected the legal hold to be released, got %v", locker.holds)
+	}
+}
+
+func TestValidateImmutability(t *testing.T) {
+	backup := newImmutableBackup(t)
+	if err := ValidateImmutability(backup, testTargets(t, backup)); err == nil {
+		t.Error("Expected error for an immutable Filesystem location")
+	}
+
+	backup.Spec.Replicas[0].StorageLocation.Provider = etcdguardianv1alpha1.StorageProviderOSS
+	if err := ValidateImmutability(backup, testTargets(t, backup)); err != nil {
+		t.Errorf("Expected valid immutability settings: %v", err)
+	}
+
+	backup.Spec.LegalHold = true
+	if err := ValidateImmutability(backup, testTargets(t, backup)); err == nil {
+		t.Error("Expected error for a legal hold in OSS")
+	}
+
+	backup.Spec.Replicas[0].StorageLocation.Provider = etcdguardianv1alpha1.StorageProviderAzure
+	backup.Spec.Replicas[0].StorageLocation.Immutability.Mode = etcdguardianv1alpha1.ImmutabilityModeGovernance
+	if err := ValidateImmutability(backup, testTargets(t, backup)); err != nil {
+		t.Errorf("Expected valid immutability settings: %v", err)
+	}
+
+	backup.Spec.Replicas[0].StorageLocation.Provider = etcdguardianv1alpha1.StorageProviderS3
+	if err := ValidateImmutability(backup, testTargets(t, backup)); err == nil {
+		t.Error("Expected error for an immutable S3 location")
+	}
+
+	backup.Spec.Replicas[0].StorageLocation.Provider = etcdguardianv1alpha1.StorageProviderAzure
+	backup.Spec.RetentionPolicy = nil
+	if err := ValidateImmutability(backup, testTargets(t, backup)); err == nil {
+		t.Error("Expected error for an immutable location without maxAge")
+	}
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/storage"
+)
+
+// uploadResumable uploads a snapshot as a multipart upload whose session is
+// kept in the backup status. The session of an upload interrupted by an
+// operator restart is picked up from the status and resumed.
+func (r *Replicator) uploadResumable(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup, target Target, uploader storage.ResumableUploader, snapshotPath string) (string, error) {
+	session := etcdguardianv1alpha1.UploadSession{Destination: target.Name}
+	for _, existing := range backup.Status.UploadSessions {
+		if existing.Destination == target.Name {
+			session = *existing.DeepCopy()
+		}
+	}
+
+	location, err := uploader.UploadResumable(ctx, snapshotPath, backup, &session, func(session *etcdguardianv1alpha1.UploadSession) error {
+		setUploadSession(backup, *session.DeepCopy())
+		return r.checkpoint(ctx, backup)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	removeUploadSession(backup, target.Name)
+	return location, nil
+}
+
+// checkpoint persists the destinations, upload sessions and storage attempts
+// of the backup status so that upload sessions survive an operator restart.
+// The cleaner of orphaned uploads updates the status concurrently, so the
+// backup is fetched again on conflict.
+func (r *Replicator) checkpoint(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup) error {
+	if r.client == nil {
+		return nil
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &etcdguardianv1alpha1.EtcdBackup{}
+		if err := r.client.Get(ctx, client.ObjectKeyFromObject(backup), latest); err != nil {
+			return err
+		}
+		latest.Status.Replicas = backup.Status.Replicas
+		latest.Status.UploadSessions = backup.Status.UploadSessions
+		latest.Status.StorageAttempts = backup.Status.StorageAttempts
+		if err := r.client.Status().Update(ctx, latest); err != nil {
+			return err
+		}
+		// The caller updates the status of its copy later
+		backup.ResourceVersion = latest.ResourceVersion
+		return nil
+	})
+}
+
+// clearUploadSessions drops the upload sessions of a backup that is done
+// uploading. The backup is fetched again on conflict, and left alone if it
+// started uploading again since it was listed.
+func (r *Replicator) clearUploadSessions(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &etcdguardianv1alpha1.EtcdBackup{}
+		if err := r.client.Get(ctx, client.ObjectKeyFromObject(backup), latest); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if isUploading(latest) || len(latest.Status.UploadSessions) == 0 {
+			return nil
+		}
+		latest.Status.UploadSessions = nil
+		return r.client.Status().Update(ctx, latest)
+	})
+}
+
+// setUploadSession stores the session of a destination in the backup status
+func setUploadSession(backup *etcdguardianv1alpha1.EtcdBackup, session etcdguardianv1alpha1.UploadSession) {
+	for i := range backup.Status.UploadSessions {
+		if backup.Status.UploadSessions[i].Destination == session.Destination {
+			backup.Status.UploadSessions[i] = session
+			return
+		}
+	}
+	backup.Status.UploadSessions = append(backup.Status.UploadSessions, session)
+}
+
+// removeUploadSession drops the session of a destination from the backup status
+func removeUploadSession(backup *etcdguardianv1alpha1.EtcdBackup, destination string) {
+	sessions := []etcdguardianv1alpha1.UploadSession{}
+	for _, session := range backup.Status.UploadSessions {
+		if session.Destination != destination {
+			sessions = append(sessions, session)
+		}
+	}
+	if len(sessions) == 0 {
+		sessions = nil
+	}
+	backup.Status.UploadSessions = sessions
+}
+
+// AbortOrphanedUploads aborts the multipart uploads in the storage
+// destinations of the given backups that no backup still uploading tracks
+// and that were initiated before the grace period. Sessions of backups that
+// are done uploading are dropped from their status. It returns the number
+// of uploads aborted.
+func (r *Replicator) AbortOrphanedUploads(ctx context.Context, backups []etcdguardianv1alpha1.EtcdBackup, gracePeriod time.Duration) (int, error) {
+	type destination struct {
+		location  etcdguardianv1alpha1.StorageLocation
+		namespace string
+	}
+
+	active := map[string]bool{}
+	destinations := map[string]destination{}
+	for i := range backups {
+		backup := &backups[i]
+		if isUploading(backup) {
+			for _, session := range backup.Status.UploadSessions {
+				active[session.RemotePath+"\x00"+session.UploadID] = true
+			}
+		}
+		targets, err := Targets(ctx, r.client, backup)
+		if err != nil {
+			// Uploads of the backup stay tracked through its sessions
+			r.log.Error(err, "Failed to resolve storage destinations", "backup", client.ObjectKeyFromObject(backup))
+			continue
+		}
+		for _, target := range targets {
+			location := target.Location
+			key := fmt.Sprintf("%s/%s/%s/%s/%s/%s", backup.Namespace, location.Provider, location.Endpoint, location.Bucket, location.Prefix, location.CredentialsSecret)
+			destinations[key] = destination{location: location, namespace: backup.Namespace}
+		}
+	}
+
+	aborted := 0
+	failures := []string{}
+	for _, dest := range destinations {
+		backend, err := r.newStorage(dest.location.Provider, dest.location, r.client, dest.namespace)
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		uploader, ok := backend.(storage.ResumableUploader)
+		if !ok {
+			continue
+		}
+
+		uploads, err := uploader.ListUploads(ctx)
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		for _, upload := range uploads {
+			if active[upload.RemotePath+"\x00"+upload.UploadID] || time.Since(upload.Initiated) < gracePeriod {
+				continue
+			}
+			if err := uploader.AbortUpload(ctx, upload.RemotePath, upload.UploadID); err != nil {
+				failures = append(failures, err.Error())
+				continue
+			}
+			r.log.Info("Aborted orphaned upload", "location", upload.RemotePath, "uploadID", upload.UploadID)
+			aborted++
+		}
+	}
+	if len(failures) > 0 {
+		return aborted, fmt.Errorf("failed to clean up uploads: %s", strings.Join(failures, "; "))
+	}
+
+	for i := range backups {
+		backup := &backups[i]
+		if !isUploading(backup) && len(backup.Status.UploadSessions) > 0 {
+			if err := r.clearUploadSessions(ctx, backup); err != nil {
+				return aborted, fmt.Errorf("failed to clear upload sessions of %s/%s: %w", backup.Namespace, backup.Name, err)
+			}
+		}
+	}
+	return aborted, nil
+}
+
+// isUploading reports whether a backup may still upload snapshots, which
+// includes completed backups with replicas pending
+func isUploading(backup *etcdguardianv1alpha1.EtcdBackup) bool {
+	switch backup.Status.Phase {
+	case etcdguardianv1alpha1.BackupPhaseCompleted:
+		return HasPendingReplicas(backup)
+	case etcdguardianv1alpha1.BackupPhaseFailed:
+		return false
+	}
+	return true
+}
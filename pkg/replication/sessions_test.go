@@ -0,0 +1,293 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/storage"
+)
+
+// resumableStorage adds an in-memory multipart upload API of two parts per
+// snapshot to the Filesystem provider
+type resumableStorage struct {
+	storage.Storage
+
+	uploads       map[string]storage.PendingUpload
+	nextID        int
+	uploadedParts int
+	// failAfter is the number of parts uploaded before an upload fails, or
+	// zero to never fail
+	failAfter int
+}
+
+func (s *resumableStorage) UploadResumable(ctx context.Context, localPath string, backup *etcdguardianv1alpha1.EtcdBackup, session *etcdguardianv1alpha1.UploadSession, checkpoint func(*etcdguardianv1alpha1.UploadSession) error) (string, error) {
+	if _, ok := s.uploads[session.UploadID]; !ok {
+		s.nextID++
+		session.UploadID = fmt.Sprintf("upload-%d", s.nextID)
+		session.RemotePath = "file://" + localPath
+		session.CompletedParts = nil
+		s.uploads[session.UploadID] = storage.PendingUpload{RemotePath: session.RemotePath, UploadID: session.UploadID, Initiated: time.Now()}
+		if err := checkpoint(session); err != nil {
+			return "", err
+		}
+	}
+
+	for number := int32(len(session.CompletedParts) + 1); number <= 2; number++ {
+		if s.failAfter > 0 && s.uploadedParts == s.failAfter {
+			return "", fmt.Errorf("connection reset")
+		}
+		s.uploadedParts++
+		session.CompletedParts = append(session.CompletedParts, etcdguardianv1alpha1.CompletedPart{PartNumber: number, ETag: "etag"})
+		if err := checkpoint(session); err != nil {
+			return "", err
+		}
+	}
+
+	delete(s.uploads, session.UploadID)
+	return s.Upload(ctx, localPath, backup)
+}
+
+func (s *resumableStorage) ListUploads(context.Context) ([]storage.PendingUpload, error) {
+	uploads := []storage.PendingUpload{}
+	for _, upload := range s.uploads {
+		uploads = append(uploads, upload)
+	}
+	return uploads, nil
+}
+
+func (s *resumableStorage) AbortUpload(_ context.Context, _, uploadID string) error {
+	delete(s.uploads, uploadID)
+	return nil
+}
+
+// useResumableStorage makes the replicator create resumableStorage backends,
+// one per bucket
+func useResumableStorage(replicator *Replicator, backends map[string]*resumableStorage) {
+	replicator.newStorage = func(provider etcdguardianv1alpha1.StorageProvider, location etcdguardianv1alpha1.StorageLocation, k8sClient client.Client, namespace string) (storage.Storage, error) {
+		if backend, ok := backends[location.Bucket]; ok {
+			return backend, nil
+		}
+		backend, err := storage.NewStorage(provider, location, k8sClient, namespace)
+		if err != nil {
+			return nil, err
+		}
+		backends[location.Bucket] = &resumableStorage{Storage: backend, uploads: map[string]storage.PendingUpload{}}
+		return backends[location.Bucket], nil
+	}
+}
+
+func TestReplicator_ResumesUploadSession(t *testing.T) {
+	backup := newTestBackup(t, etcdguardianv1alpha1.ReplicationPolicyAll)
+	k8sClient := newFakeClient(backup)
+	ctx := context.Background()
+	snapshotPath := writeTestSnapshot(t)
+
+	backends := map[string]*resumableStorage{}
+	replicator := NewReplicator(k8sClient, logr.Discard())
+	useResumableStorage(replicator, backends)
+	backend, err := replicator.newStorage(etcdguardianv1alpha1.StorageProviderFilesystem, *backup.Spec.StorageLocation, nil, "")
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	backend.(*resumableStorage).failAfter = 1
+
+	if err := replicator.Replicate(ctx, backup, snapshotPath); err != nil {
+		t.Fatalf("Replicate failed: %v", err)
+	}
+	if backup.Status.Replicas[0].Phase != etcdguardianv1alpha1.ReplicaPhaseFailed {
+		t.Fatalf("Expected interrupted upload, got %+v", backup.Status.Replicas[0])
+	}
+
+	// The operator restarts and reads the status persisted during the upload
+	persisted := &etcdguardianv1alpha1.EtcdBackup{}
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(backup), persisted); err != nil {
+		t.Fatalf("Failed to get backup: %v", err)
+	}
+	if len(persisted.Status.UploadSessions) != 1 || len(persisted.Status.UploadSessions[0].CompletedParts) != 1 {
+		t.Fatalf("Expected persisted session with one part, got %+v", persisted.Status.UploadSessions)
+	}
+	if persisted.Status.UploadSessions[0].Destination != PrimaryName {
+		t.Errorf("Expected session of the primary, got %s", persisted.Status.UploadSessions[0].Destination)
+	}
+
+	backends[backup.Spec.StorageLocation.Bucket].failAfter = 0
+	replicator = NewReplicator(k8sClient, logr.Discard())
+	useResumableStorage(replicator, backends)
+	if err := replicator.Replicate(ctx, persisted, snapshotPath); err != nil {
+		t.Fatalf("Replicate failed: %v", err)
+	}
+	if complete, err := Evaluate(persisted); err != nil || !complete {
+		t.Fatalf("Expected complete backup, got %v, %v", complete, err)
+	}
+	if uploaded := backends[backup.Spec.StorageLocation.Bucket].uploadedParts; uploaded != 2 {
+		t.Errorf("Expected the resumed upload to send only the missing part, got %d parts in total", uploaded)
+	}
+	if len(persisted.Status.UploadSessions) != 0 {
+		t.Errorf("Expected session to be dropped after the upload, got %+v", persisted.Status.UploadSessions)
+	}
+}
+
+func TestReplicator_AbortOrphanedUploads(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	running := newTestBackup(t, etcdguardianv1alpha1.ReplicationPolicyAll)
+	running.Spec.StorageLocation = filesystemLocation(root)
+	running.Status.Phase = etcdguardianv1alpha1.BackupPhaseSnapshotting
+	running.Status.UploadSessions = []etcdguardianv1alpha1.UploadSession{{Destination: PrimaryName, RemotePath: "file:///running", UploadID: "running"}}
+
+	failed := running.DeepCopy()
+	failed.Name = "failed"
+	failed.Status.Phase = etcdguardianv1alpha1.BackupPhaseFailed
+	failed.Status.UploadSessions = []etcdguardianv1alpha1.UploadSession{{Destination: PrimaryName, RemotePath: "file:///failed", UploadID: "failed"}}
+
+	k8sClient := newFakeClient(running, failed)
+	replicator := NewReplicator(k8sClient, logr.Discard())
+	backends := map[string]*resumableStorage{}
+	useResumableStorage(replicator, backends)
+	if _, err := replicator.newStorage(etcdguardianv1alpha1.StorageProviderFilesystem, *running.Spec.StorageLocation, nil, ""); err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	backends[root].uploads = map[string]storage.PendingUpload{
+		"running": {RemotePath: "file:///running", UploadID: "running", Initiated: old},
+		"failed":  {RemotePath: "file:///failed", UploadID: "failed", Initiated: old},
+		"recent":  {RemotePath: "file:///recent", UploadID: "recent", Initiated: time.Now()},
+	}
+
+	backups := []etcdguardianv1alpha1.EtcdBackup{*running, *failed}
+	aborted, err := replicator.AbortOrphanedUploads(ctx, backups, time.Hour)
+	if err != nil {
+		t.Fatalf("AbortOrphanedUploads failed: %v", err)
+	}
+	if aborted != 1 {
+		t.Errorf("Expected 1 aborted upload, got %d", aborted)
+	}
+	if _, ok := backends[root].uploads["failed"]; ok {
+		t.Error("Expected upload of the failed backup to be aborted")
+	}
+	if len(backends[root].uploads) != 2 {
+		t.Errorf("Expected tracked and recent uploads to be kept, got %+v", backends[root].uploads)
+	}
+
+	persisted := &etcdguardianv1alpha1.EtcdBackup{}
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(failed), persisted); err != nil {
+		t.Fatalf("Failed to get backup: %v", err)
+	}
+	if len(persisted.Status.UploadSessions) != 0 {
+		t.Errorf("Expected sessions of the failed backup to be cleared, got %+v", persisted.Status.UploadSessions)
+	}
+}
+
+// updateMessage changes the status of a backup behind the back of the
+// replicator, leaving the copies it holds stale
+func updateMessage(t *testing.T, k8sClient client.Client, backup *etcdguardianv1alpha1.EtcdBackup, message string) {
+	t.Helper()
+	latest := &etcdguardianv1alpha1.EtcdBackup{}
+	if err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(backup), latest); err != nil {
+		t.Fatalf("Failed to get backup: %v", err)
+	}
+	latest.Status.Message = message
+	if err := k8sClient.Status().Update(context.Background(), latest); err != nil {
+		t.Fatalf("Failed to update backup: %v", err)
+	}
+}
+
+func TestReplicator_CheckpointsStaleBackup(t *testing.T) {
+	backup := newTestBackup(t, etcdguardianv1alpha1.ReplicationPolicyAll)
+	k8sClient := newFakeClient(backup)
+	ctx := context.Background()
+	updateMessage(t, k8sClient, backup, "updated concurrently")
+
+	backends := map[string]*resumableStorage{}
+	replicator := NewReplicator(k8sClient, logr.Discard())
+	useResumableStorage(replicator, backends)
+	backend, err := replicator.newStorage(etcdguardianv1alpha1.StorageProviderFilesystem, *backup.Spec.StorageLocation, nil, "")
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	backend.(*resumableStorage).failAfter = 1
+
+	if err := replicator.Replicate(ctx, backup, writeTestSnapshot(t)); err != nil {
+		t.Fatalf("Replicate failed: %v", err)
+	}
+
+	persisted := &etcdguardianv1alpha1.EtcdBackup{}
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(backup), persisted); err != nil {
+		t.Fatalf("Failed to get backup: %v", err)
+	}
+	if len(persisted.Status.UploadSessions) != 1 || len(persisted.Status.UploadSessions[0].CompletedParts) != 1 {
+		t.Fatalf("Expected the session to be checkpointed despite the conflict, got %+v", persisted.Status.UploadSessions)
+	}
+	if persisted.Status.Message != "updated concurrently" {
+		t.Errorf("Expected the concurrent update to be kept, got %q", persisted.Status.Message)
+	}
+	if backup.ResourceVersion != persisted.ResourceVersion {
+		t.Errorf("Expected the resource version of the checkpoint, got %s instead of %s", backup.ResourceVersion, persisted.ResourceVersion)
+	}
+}
+
+func TestReplicator_AbortOrphanedUploadsOfStaleBackups(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	failed := newTestBackup(t, etcdguardianv1alpha1.ReplicationPolicyAll)
+	failed.Spec.StorageLocation = filesystemLocation(root)
+	failed.Status.Phase = etcdguardianv1alpha1.BackupPhaseFailed
+	failed.Status.UploadSessions = []etcdguardianv1alpha1.UploadSession{{Destination: PrimaryName, RemotePath: "file:///failed", UploadID: "failed"}}
+
+	retried := failed.DeepCopy()
+	retried.Name = "retried"
+	retried.Status.UploadSessions = []etcdguardianv1alpha1.UploadSession{{Destination: PrimaryName, RemotePath: "file:///retried", UploadID: "retried"}}
+
+	k8sClient := newFakeClient(failed, retried)
+	backups := []etcdguardianv1alpha1.EtcdBackup{*failed, *retried}
+	// After the backups were listed, one is updated and the other is retried
+	updateMessage(t, k8sClient, failed, "updated concurrently")
+	latest := &etcdguardianv1alpha1.EtcdBackup{}
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(retried), latest); err != nil {
+		t.Fatalf("Failed to get backup: %v", err)
+	}
+	latest.Status.Phase = etcdguardianv1alpha1.BackupPhaseSnapshotting
+	if err := k8sClient.Status().Update(ctx, latest); err != nil {
+		t.Fatalf("Failed to update backup: %v", err)
+	}
+
+	replicator := NewReplicator(k8sClient, logr.Discard())
+	useResumableStorage(replicator, map[string]*resumableStorage{})
+	if _, err := replicator.AbortOrphanedUploads(ctx, backups, time.Hour); err != nil {
+		t.Fatalf("AbortOrphanedUploads failed: %v", err)
+	}
+
+	for name, expected := range map[string]int{failed.Name: 0, retried.Name: 1} {
+		persisted := &etcdguardianv1alpha1.EtcdBackup{}
+		if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: failed.Namespace, Name: name}, persisted); err != nil {
+			t.Fatalf("Failed to get backup: %v", err)
+		}
+		if len(persisted.Status.UploadSessions) != expected {
+			t.Errorf("Expected %d sessions of %s, got %+v", expected, name, persisted.Status.UploadSessions)
+		}
+	}
+}
@@ -30,23 +30,38 @@ import (
 // SnapshotEngine handles etcd snapshot operations
 type SnapshotEngine struct {
 	log logr.Logger
+
+	// dir is the directory snapshots are written to
+	dir string
 }
 
 // NewSnapshotEngine creates a new snapshot engine
 func NewSnapshotEngine(log logr.Logger) *SnapshotEngine {
 	return &SnapshotEngine{
 		log: log,
+		dir: os.TempDir(),
 	}
 }
 
+// WithDir sets the directory snapshots are written to. Snapshots kept on a
+// persistent volume survive operator restarts, which lets interrupted
+// uploads resume.
+func (s *SnapshotEngine) WithDir(dir string) *SnapshotEngine {
+	if dir != "" {
+		s.dir = dir
+	}
+	return s
+}
+
 // TakeFullSnapshot takes a full etcd snapshot
 func (s *SnapshotEngine) TakeFullSnapshot(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup) (snapshotPath string, size int64, revision int64, err error) {
 	s.log.Info("Taking full etcd snapshot", "backup", backup.Name)
 
-	// Create temporary directory for snapshot
-	tmpDir := os.TempDir()
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return "", 0, 0, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
 	timestamp := time.Now().Format("20060102-150405")
-	snapshotPath = filepath.Join(tmpDir, fmt.Sprintf("etcd-snapshot-%s-%s.db", backup.Name, timestamp))
+	snapshotPath = filepath.Join(s.dir, fmt.Sprintf("etcd-snapshot-%s-%s.db", backup.Name, timestamp))
 
 	// TODO: Implement actual etcd snapshot using etcd client
 	// For now, create a placeholder file
@@ -0,0 +1,140 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+)
+
+// internalPrefix is where the operator keeps objects of its own below the
+// prefix of a location. They are no snapshots and never listed as such.
+const internalPrefix = ".etcdguardian/"
+
+// accessProbeKey is the probe object of access checks below the prefix of
+// the location. It is the same object for every check, so that locations
+// retaining it keep a single one.
+const accessProbeKey = internalPrefix + "access-check/probe"
+
+// accessProbeContent is the content of the probe object
+var accessProbeContent = []byte("etcdguardian access check")
+
+// AccessError is the error of an access check, naming the access that failed
+type AccessError struct {
+	// Access is write, read, list or delete
+	Access string
+	Err    error
+}
+
+func (e *AccessError) Error() string {
+	return fmt.Sprintf("%s access failed: %v", e.Access, e.Err)
+}
+
+func (e *AccessError) Unwrap() error {
+	return e.Err
+}
+
+// CheckAccess checks that objects can be written, read, listed and deleted
+// below the prefix of a storage location, using a probe object that is
+// deleted again. The error returned is an *AccessError.
+//
+// Immutable locations may retain the probe object like any other, so a
+// delete refused because the object is locked passes the check and leaves
+// the probe object to the retention of the bucket. Any other delete error
+// fails the check. A probe object retained by an earlier check is only read
+// and listed, as it can be neither overwritten nor deleted, and overwriting
+// it would retain another version.
+func CheckAccess(ctx context.Context, store ObjectStore, location etcdguardianv1alpha1.StorageLocation) error {
+	key := path.Join(location.Prefix, accessProbeKey)
+
+	if location.Immutability != nil {
+		_, err := store.StatObject(ctx, key)
+		if err == nil {
+			return checkReadAndList(ctx, store, key)
+		}
+		if !errors.Is(err, ErrObjectNotFound) {
+			return &AccessError{Access: "read", Err: err}
+		}
+	}
+
+	if _, err := store.PutObject(ctx, key, bytes.NewReader(accessProbeContent), PutOptions{Size: int64(len(accessProbeContent))}); err != nil {
+		return &AccessError{Access: "write", Err: err}
+	}
+	if err := checkReadAndList(ctx, store, key); err != nil {
+		_ = store.DeleteObject(ctx, key)
+		return err
+	}
+
+	if err := store.DeleteObject(ctx, key); err != nil {
+		if location.Immutability != nil && Classify(err) == ErrorClassObjectLocked {
+			return nil
+		}
+		return &AccessError{Access: "delete", Err: err}
+	}
+	return nil
+}
+
+// checkReadAndList reads the probe object back and finds it in the listing
+// of its prefix
+func checkReadAndList(ctx context.Context, store ObjectStore, key string) error {
+	if err := checkRead(ctx, store, key, accessProbeContent); err != nil {
+		return &AccessError{Access: "read", Err: err}
+	}
+
+	objects, err := store.ListObjects(ctx, path.Dir(key)+"/")
+	if err == nil {
+		err = fmt.Errorf("probe object %s is not listed", key)
+		for _, object := range objects {
+			if object.Key == key {
+				err = nil
+			}
+		}
+	}
+	if err != nil {
+		return &AccessError{Access: "list", Err: err}
+	}
+	return nil
+}
+
+// checkRead reads the probe object back through a temporary file
+func checkRead(ctx context.Context, store ObjectStore, key string, data []byte) error {
+	file, err := os.CreateTemp("", "etcdguardian-access-check-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	size, err := store.GetObject(ctx, key, file)
+	if err != nil {
+		return err
+	}
+	read := make([]byte, size)
+	if _, err := file.ReadAt(read, 0); err != nil {
+		return fmt.Errorf("failed to read temporary file: %w", err)
+	}
+	if !bytes.Equal(read, data) {
+		return fmt.Errorf("probe object %s has unexpected content", key)
+	}
+	return nil
+}
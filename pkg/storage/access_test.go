@@ -0,0 +1,136 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+)
+
+// denyingStore fails the deletion of objects
+type denyingStore struct {
+	ObjectStore
+}
+
+func (s *denyingStore) DeleteObject(ctx context.Context, key string) error {
+	return &os.PathError{Op: "remove", Path: key, Err: os.ErrPermission}
+}
+
+func TestCheckAccess(t *testing.T) {
+	store, root := newTestFilesystemStorage(t)
+
+	if err := CheckAccess(context.Background(), store, etcdguardianv1alpha1.StorageLocation{Prefix: "etcd"}); err != nil {
+		t.Fatalf("CheckAccess failed: %v", err)
+	}
+	objects, err := store.ListObjects(context.Background(), "etcd/")
+	if err != nil {
+		t.Fatalf("ListObjects failed: %v", err)
+	}
+	if len(objects) != 0 {
+		t.Errorf("Expected the probe object to be deleted from %s, got %+v", root, objects)
+	}
+}
+
+func TestCheckAccess_ReportsFailedAccess(t *testing.T) {
+	store, _ := newTestFilesystemStorage(t)
+
+	err := CheckAccess(context.Background(), &denyingStore{ObjectStore: store}, etcdguardianv1alpha1.StorageLocation{Prefix: "etcd"})
+	var accessErr *AccessError
+	if !errors.As(err, &accessErr) || accessErr.Access != "delete" {
+		t.Fatalf("Expected the delete access to fail, got %v", err)
+	}
+	if class := Classify(err); class != ErrorClassAuth {
+		t.Errorf("Expected an auth error, got %s", class)
+	}
+}
+
+func TestCheckAccess_ImmutableLocation(t *testing.T) {
+	t.Cleanup(ResetMemoryStorage)
+	ctx := context.Background()
+	location := etcdguardianv1alpha1.StorageLocation{
+		Provider:     ProviderMemory,
+		Bucket:       "worm",
+		Prefix:       "etcd",
+		Immutability: &etcdguardianv1alpha1.ImmutabilityConfig{Mode: etcdguardianv1alpha1.ImmutabilityModeCompliance},
+	}
+	store, err := NewObjectStore(location.Provider, location, nil, "default")
+	if err != nil {
+		t.Fatalf("NewObjectStore failed: %v", err)
+	}
+
+	// The bucket retains the probe object
+	faults := MemoryFaults("worm")
+	faults.Add(Fault{Operations: []FaultOperation{FaultDelete}, Err: &InjectedError{Class: ErrorClassObjectLocked}})
+	if err := CheckAccess(ctx, store, location); err != nil {
+		t.Fatalf("Expected the retained probe object to pass the check, got %v", err)
+	}
+	// The retained probe object cannot be overwritten either
+	faults.Add(Fault{Operations: []FaultOperation{FaultPut}, Err: &InjectedError{Class: ErrorClassObjectLocked}})
+	if err := CheckAccess(ctx, store, location); err != nil {
+		t.Fatalf("Expected the retained probe object to pass the check again, got %v", err)
+	}
+	objects, err := store.ListObjects(ctx, "etcd/")
+	if err != nil {
+		t.Fatalf("ListObjects failed: %v", err)
+	}
+	if len(objects) != 1 {
+		t.Errorf("Expected a single retained probe object, got %+v", objects)
+	}
+	backend, err := NewStorage(location.Provider, location, nil, "default")
+	if err != nil {
+		t.Fatalf("NewStorage failed: %v", err)
+	}
+	if snapshots, err := backend.List(ctx, ""); err != nil || len(snapshots) != 0 {
+		t.Errorf("Expected the probe object not to be listed as a snapshot, got %+v (%v)", snapshots, err)
+	}
+
+	// Delete errors of the following checks leave the probe object behind,
+	// remove it as its retention would end
+	removeProbe := func() {
+		faults.Clear()
+		if err := store.DeleteObject(ctx, objects[0].Key); err != nil {
+			t.Fatalf("DeleteObject failed: %v", err)
+		}
+	}
+
+	removeProbe()
+	mutable := location
+	mutable.Immutability = nil
+	faults.Add(Fault{Operations: []FaultOperation{FaultDelete}, Err: &InjectedError{Class: ErrorClassObjectLocked}})
+	var accessErr *AccessError
+	if err := CheckAccess(ctx, store, mutable); !errors.As(err, &accessErr) || accessErr.Access != "delete" {
+		t.Errorf("Expected the delete access of a mutable location to fail, got %v", err)
+	}
+
+	// Errors other than locked objects fail immutable locations too
+	removeProbe()
+	faults.Add(Fault{Operations: []FaultOperation{FaultDelete}, Err: &InjectedError{Class: ErrorClassAuth}})
+	if err := CheckAccess(ctx, store, location); !errors.As(err, &accessErr) || accessErr.Access != "delete" {
+		t.Errorf("Expected a denied delete to fail the check, got %v", err)
+	}
+
+	// Transient errors fail immutable locations too
+	removeProbe()
+	faults.Add(Fault{Operations: []FaultOperation{FaultDelete}, Err: &InjectedError{Class: ErrorClassRetryable}})
+	if err := CheckAccess(ctx, store, location); !errors.As(err, &accessErr) || accessErr.Access != "delete" {
+		t.Errorf("Expected a transient delete error to fail the check, got %v", err)
+	}
+}
@@ -0,0 +1,520 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// azureBlockSize is the size of each staged block
+	azureBlockSize = 16 * 1024 * 1024
+)
+
+// Keys read from the Azure credentials secret
+const (
+	azureStorageAccountKey    = "storage-account"
+	azureStorageAccountKeyKey = "storage-account-key"
+	azureSASTokenKey          = "sas-token"
+	azureClientIDKey          = "client-id"
+	azureTenantIDKey          = "tenant-id"
+)
+
+// azureStorageAccountEnv names the storage account when the credentials
+// secret does not, e.g. with workload identity
+const azureStorageAccountEnv = "AZURE_STORAGE_ACCOUNT"
+
+// azureSASClockSkew backdates the start of a SAS to tolerate clock skew
+// between the operator and the storage service
+const azureSASClockSkew = 5 * time.Minute
+
+// AzureStorage implements Azure Blob storage. The bucket of the storage
+// location is the blob container.
+type AzureStorage struct {
+	location  etcdguardianv1alpha1.StorageLocation
+	client    client.Client
+	namespace string
+
+	blockSize int
+	transport policy.Transporter
+
+	mu            sync.Mutex
+	serviceClient *service.Client
+	// workloadIdentity is set when the service client authenticates with
+	// workload identity rather than a shared key or SAS token
+	workloadIdentity bool
+}
+
+// NewAzureStorage creates a new Azure Blob storage backend
+func NewAzureStorage(location etcdguardianv1alpha1.StorageLocation, k8sClient client.Client, namespace string) (*AzureStorage, error) {
+	return &AzureStorage{
+		location:  location,
+		client:    k8sClient,
+		namespace: namespace,
+		blockSize: azureBlockSize,
+	}, nil
+}
+
+// Upload uploads a snapshot to Azure Blob storage
+func (a *AzureStorage) Upload(ctx context.Context, localPath string, backup *etcdguardianv1alpha1.EtcdBackup) (string, error) {
+	return putFile(ctx, a, a.location.Prefix, localPath, backup)
+}
+
+// Download downloads a snapshot from Azure Blob storage
+func (a *AzureStorage) Download(ctx context.Context, remotePath, localPath string) error {
+	return getFile(ctx, a, remotePath, localPath)
+}
+
+// List lists snapshots in Azure Blob storage
+func (a *AzureStorage) List(ctx context.Context, prefix string) ([]SnapshotMetadata, error) {
+	return listSnapshots(ctx, a, a.location.Prefix, prefix)
+}
+
+// Delete deletes a snapshot from Azure Blob storage
+func (a *AzureStorage) Delete(ctx context.Context, remotePath string) error {
+	return deleteObject(ctx, a, remotePath)
+}
+
+// GetMetadata gets snapshot metadata from Azure Blob storage
+func (a *AzureStorage) GetMetadata(ctx context.Context, remotePath string) (*SnapshotMetadata, error) {
+	return statSnapshot(ctx, a, remotePath)
+}
+
+// PutObject streams an object to Azure Blob storage as staged blocks and
+// commits the block list once every block is stored
+func (a *AzureStorage) PutObject(ctx context.Context, key string, r io.Reader, opts PutOptions) (*ObjectInfo, error) {
+	containerClient, err := a.getContainer(ctx, a.location.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	remotePath := a.remotePath(key)
+	blobClient := containerClient.NewBlockBlobClient(key)
+	reader := newStreamReader(ctx, r, opts)
+
+	blockIDs := []string{}
+	buffer := make([]byte, a.blockSize)
+	for {
+		n, err := io.ReadFull(reader, buffer)
+		if n > 0 {
+			blockID := azureBlockID(len(blockIDs))
+			body := streaming.NopCloser(bytes.NewReader(buffer[:n]))
+			if _, err := blobClient.StageBlock(ctx, blockID, body, nil); err != nil {
+				return nil, fmt.Errorf("failed to stage block %d of %s: %w", len(blockIDs), remotePath, err)
+			}
+			blockIDs = append(blockIDs, blockID)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload to %s: %w", remotePath, err)
+		}
+	}
+
+	metadata := map[string]*string{}
+	for name, value := range opts.objectMetadata() {
+		value := value
+		metadata[azureMetadataName(name)] = &value
+	}
+	if _, err := blobClient.CommitBlockList(ctx, blockIDs, &blockblob.CommitBlockListOptions{Metadata: metadata}); err != nil {
+		return nil, fmt.Errorf("failed to commit block list of %s: %w", remotePath, err)
+	}
+
+	return a.StatObject(ctx, key)
+}
+
+// GetObject downloads a blob to w
+func (a *AzureStorage) GetObject(ctx context.Context, key string, w io.WriterAt) (int64, error) {
+	containerClient, err := a.getContainer(ctx, a.location.Bucket)
+	if err != nil {
+		return 0, err
+	}
+
+	response, err := containerClient.NewBlobClient(key).DownloadStream(ctx, nil)
+	if err != nil {
+		return 0, a.objectError(key, err)
+	}
+	defer response.Body.Close()
+
+	return io.Copy(io.NewOffsetWriter(w, 0), response.Body)
+}
+
+// StatObject returns the properties and metadata of a blob
+func (a *AzureStorage) StatObject(ctx context.Context, key string) (*ObjectInfo, error) {
+	containerClient, err := a.getContainer(ctx, a.location.Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	properties, err := containerClient.NewBlobClient(key).GetProperties(ctx, nil)
+	if err != nil {
+		return nil, a.objectError(key, err)
+	}
+
+	object := &ObjectInfo{
+		Key:      key,
+		Location: a.remotePath(key),
+		Metadata: azureObjectMetadata(properties.Metadata),
+	}
+	if properties.ContentLength != nil {
+		object.Size = *properties.ContentLength
+	}
+	if properties.CreationTime != nil {
+		object.Created = *properties.CreationTime
+	}
+	return object, nil
+}
+
+// ListObjects lists the blobs whose name starts with prefix
+func (a *AzureStorage) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	containerClient, err := a.getContainer(ctx, a.location.Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	pager := containerClient.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{
+		Prefix:  &prefix,
+		Include: container.ListBlobsInclude{Metadata: true},
+	})
+
+	objects := []ObjectInfo{}
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list azure://%s: %w", a.location.Bucket, err)
+		}
+		for _, item := range page.Segment.BlobItems {
+			if item.Name == nil || item.Properties == nil {
+				continue
+			}
+			object := ObjectInfo{
+				Key:      *item.Name,
+				Location: a.remotePath(*item.Name),
+				Metadata: azureObjectMetadata(item.Metadata),
+			}
+			if item.Properties.ContentLength != nil {
+				object.Size = *item.Properties.ContentLength
+			}
+			if item.Properties.CreationTime != nil {
+				object.Created = *item.Properties.CreationTime
+			}
+			objects = append(objects, object)
+		}
+	}
+	return objects, nil
+}
+
+// DeleteObject deletes a blob
+func (a *AzureStorage) DeleteObject(ctx context.Context, key string) error {
+	containerClient, err := a.getContainer(ctx, a.location.Bucket)
+	if err != nil {
+		return err
+	}
+
+	if _, err := containerClient.NewBlobClient(key).Delete(ctx, nil); err != nil {
+		return a.objectError(key, err)
+	}
+	return nil
+}
+
+// LockObject sets a version-level immutability policy on a blob. Compliance
+// maps to a locked policy, which cannot be shortened or removed, and
+// Governance to an unlocked one. The container must have version-level
+// immutability support enabled.
+func (a *AzureStorage) LockObject(ctx context.Context, remotePath string, mode etcdguardianv1alpha1.ImmutabilityMode, retainUntil time.Time) error {
+	blobClient, key, err := a.blobClient(ctx, remotePath)
+	if err != nil {
+		return err
+	}
+
+	setting := blob.ImmutabilityPolicySettingUnlocked
+	if mode == etcdguardianv1alpha1.ImmutabilityModeCompliance {
+		setting = blob.ImmutabilityPolicySettingLocked
+	}
+	if _, err := blobClient.SetImmutabilityPolicy(ctx, retainUntil, &blob.SetImmutabilityPolicyOptions{Mode: &setting}); err != nil {
+		return fmt.Errorf("failed to set immutability policy: %w", a.objectError(key, err))
+	}
+	return nil
+}
+
+// SetLegalHold places or releases the legal hold of a blob
+func (a *AzureStorage) SetLegalHold(ctx context.Context, remotePath string, hold bool) error {
+	blobClient, key, err := a.blobClient(ctx, remotePath)
+	if err != nil {
+		return err
+	}
+
+	if _, err := blobClient.SetLegalHold(ctx, hold, nil); err != nil {
+		return fmt.Errorf("failed to set legal hold: %w", a.objectError(key, err))
+	}
+	return nil
+}
+
+// SetTier moves a blob to the Cool or Archive access tier
+func (a *AzureStorage) SetTier(ctx context.Context, remotePath string, tier etcdguardianv1alpha1.StorageTier) error {
+	accessTier, err := azureAccessTier(tier)
+	if err != nil {
+		return err
+	}
+	blobClient, key, err := a.blobClient(ctx, remotePath)
+	if err != nil {
+		return err
+	}
+
+	if _, err := blobClient.SetTier(ctx, accessTier, nil); err != nil {
+		return fmt.Errorf("failed to set access tier %s: %w", accessTier, a.objectError(key, err))
+	}
+	return nil
+}
+
+// Rehydrate moves an archived blob back to the Hot tier, which takes up to
+// several hours. Blobs in other tiers are readable right away.
+func (a *AzureStorage) Rehydrate(ctx context.Context, remotePath string) (bool, error) {
+	blobClient, key, err := a.blobClient(ctx, remotePath)
+	if err != nil {
+		return false, err
+	}
+	properties, err := blobClient.GetProperties(ctx, nil)
+	if err != nil {
+		return false, a.objectError(key, err)
+	}
+
+	if properties.AccessTier == nil || blob.AccessTier(*properties.AccessTier) != blob.AccessTierArchive {
+		return true, nil
+	}
+	if properties.ArchiveStatus != nil && strings.HasPrefix(*properties.ArchiveStatus, "rehydrate-pending") {
+		return false, nil
+	}
+	priority := blob.RehydratePriorityStandard
+	if _, err := blobClient.SetTier(ctx, blob.AccessTierHot, &blob.SetTierOptions{RehydratePriority: &priority}); err != nil {
+		return false, fmt.Errorf("failed to rehydrate archived blob: %w", a.objectError(key, err))
+	}
+	return false, nil
+}
+
+// PresignURL returns the URL of a blob with a read-only SAS. The SAS is
+// signed with the shared key of the account, or with a user delegation key
+// under workload identity. A SAS token in the credentials secret cannot sign
+// another SAS.
+func (a *AzureStorage) PresignURL(ctx context.Context, remotePath string, expiry time.Duration) (string, error) {
+	blobClient, key, err := a.blobClient(ctx, remotePath)
+	if err != nil {
+		return "", err
+	}
+
+	start := time.Now().UTC().Add(-azureSASClockSkew)
+	expiresOn := time.Now().UTC().Add(expiry)
+	permissions := sas.BlobPermissions{Read: true}
+
+	a.mu.Lock()
+	serviceClient, workloadIdentity := a.serviceClient, a.workloadIdentity
+	a.mu.Unlock()
+	if !workloadIdentity {
+		signedURL, err := blobClient.GetSASURL(permissions, expiresOn, &blob.GetSASURLOptions{StartTime: &start})
+		if err != nil {
+			return "", fmt.Errorf("failed to presign %s, which needs a shared key or workload identity: %w", remotePath, err)
+		}
+		return signedURL, nil
+	}
+
+	credential, err := serviceClient.GetUserDelegationCredential(ctx, service.KeyInfo{
+		Start:  to.Ptr(start.Format(sas.TimeFormat)),
+		Expiry: to.Ptr(expiresOn.Format(sas.TimeFormat)),
+	}, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user delegation key: %w", a.objectError(key, err))
+	}
+	values := sas.BlobSignatureValues{
+		StartTime:     start,
+		ExpiryTime:    expiresOn,
+		Permissions:   permissions.String(),
+		ContainerName: a.location.Bucket,
+		BlobName:      key,
+	}
+	parameters, err := values.SignWithUserDelegation(credential)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign %s: %w", remotePath, err)
+	}
+	return blobClient.URL() + "?" + parameters.Encode(), nil
+}
+
+// azureAccessTier returns the Azure access tier of a storage tier
+func azureAccessTier(tier etcdguardianv1alpha1.StorageTier) (blob.AccessTier, error) {
+	switch tier {
+	case etcdguardianv1alpha1.StorageTierInfrequentAccess:
+		return blob.AccessTierCool, nil
+	case etcdguardianv1alpha1.StorageTierArchive:
+		return blob.AccessTierArchive, nil
+	}
+	return "", fmt.Errorf("unsupported storage tier %q", tier)
+}
+
+// blobClient returns the client of the blob a remote path addresses
+func (a *AzureStorage) blobClient(ctx context.Context, remotePath string) (*blob.Client, string, error) {
+	key, err := a.ObjectKey(remotePath)
+	if err != nil {
+		return nil, "", err
+	}
+	containerClient, err := a.getContainer(ctx, a.location.Bucket)
+	if err != nil {
+		return nil, "", err
+	}
+	return containerClient.NewBlobClient(key), key, nil
+}
+
+// ObjectKey returns the blob name a remote path addresses, which must be in
+// the container of the location
+func (a *AzureStorage) ObjectKey(remotePath string) (string, error) {
+	containerName, key, err := splitRemotePath(remotePath, "azure", a.location.Bucket)
+	if err != nil {
+		return "", err
+	}
+	if containerName != a.location.Bucket {
+		return "", fmt.Errorf("remote path %q is not in container %s", remotePath, a.location.Bucket)
+	}
+	return key, nil
+}
+
+// objectError wraps an error of a blob operation, marking missing blobs
+func (a *AzureStorage) objectError(key string, err error) error {
+	if bloberror.HasCode(err, bloberror.BlobNotFound, bloberror.ContainerNotFound) {
+		return fmt.Errorf("%s: %w", a.remotePath(key), ErrObjectNotFound)
+	}
+	return fmt.Errorf("%s: %w", a.remotePath(key), err)
+}
+
+// remotePath returns the remote path of a blob name
+func (a *AzureStorage) remotePath(key string) string {
+	return fmt.Sprintf("azure://%s/%s", a.location.Bucket, key)
+}
+
+// azureBlockID returns the fixed-width base64 block ID of the nth block
+func azureBlockID(n int) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("block-%08d", n)))
+}
+
+// azureMetadataName converts a metadata key to a valid Azure metadata name,
+// which must be a C# identifier
+func azureMetadataName(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// azureObjectMetadata converts Azure blob metadata back to snapshot metadata keys
+func azureObjectMetadata(metadata map[string]*string) map[string]string {
+	result := map[string]string{}
+	for name, value := range metadata {
+		if value != nil {
+			result[strings.ReplaceAll(strings.ToLower(name), "_", "-")] = *value
+		}
+	}
+	return result
+}
+
+// getContainer returns a client of the named container, creating the service
+// client on first use. The credentials secret may hold a shared key or a SAS
+// token; otherwise workload identity is used.
+func (a *AzureStorage) getContainer(ctx context.Context, name string) (*container.Client, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.serviceClient != nil {
+		return a.serviceClient.NewContainerClient(name), nil
+	}
+
+	data := map[string][]byte{}
+	if a.location.CredentialsSecret != "" {
+		var err error
+		if data, err = loadCredentials(ctx, a.client, a.namespace, a.location.CredentialsSecret); err != nil {
+			return nil, err
+		}
+	}
+
+	account := string(data[azureStorageAccountKey])
+	if account == "" {
+		account = os.Getenv(azureStorageAccountEnv)
+	}
+
+	serviceURL := a.location.Endpoint
+	if serviceURL == "" {
+		if account == "" {
+			return nil, fmt.Errorf("no azure storage account: set %s in the credentials secret or an endpoint", azureStorageAccountKey)
+		}
+		serviceURL = fmt.Sprintf("https://%s.blob.core.windows.net/", account)
+	}
+
+	clientOptions := azcore.ClientOptions{}
+	if a.transport != nil {
+		clientOptions.Transport = a.transport
+	}
+	options := &service.ClientOptions{ClientOptions: clientOptions}
+
+	var serviceClient *service.Client
+	var err error
+	switch {
+	case len(data[azureStorageAccountKeyKey]) > 0:
+		if account == "" {
+			return nil, fmt.Errorf("credentials secret %s has a shared key but no %s", a.location.CredentialsSecret, azureStorageAccountKey)
+		}
+		credential, credErr := service.NewSharedKeyCredential(account, string(data[azureStorageAccountKeyKey]))
+		if credErr != nil {
+			return nil, fmt.Errorf("invalid azure shared key: %w", credErr)
+		}
+		serviceClient, err = service.NewClientWithSharedKeyCredential(serviceURL, credential, options)
+	case len(data[azureSASTokenKey]) > 0:
+		sasURL := strings.TrimSuffix(serviceURL, "?") + "?" + strings.TrimPrefix(string(data[azureSASTokenKey]), "?")
+		serviceClient, err = service.NewClientWithNoCredential(sasURL, options)
+	default:
+		credential, credErr := azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+			ClientOptions: clientOptions,
+			ClientID:      string(data[azureClientIDKey]),
+			TenantID:      string(data[azureTenantIDKey]),
+		})
+		if credErr != nil {
+			return nil, fmt.Errorf("failed to create azure workload identity credential: %w", credErr)
+		}
+		serviceClient, err = service.NewClient(serviceURL, credential, options)
+		a.workloadIdentity = true
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create azure blob client: %w", err)
+	}
+
+	a.serviceClient = serviceClient
+	return serviceClient.NewContainerClient(name), nil
+}
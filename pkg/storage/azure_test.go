@@ -0,0 +1,615 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const azureTestAccount = "devstoreaccount1"
+
+// fakeAzureBlob is a committed block blob stored by fakeAzureServer
+type fakeAzureBlob struct {
+	data     []byte
+	metadata map[string]string
+	created  time.Time
+	// Immutability policy and legal hold of the blob
+	immutableUntil time.Time
+	policyMode     string
+	legalHold      bool
+	// Access tier, empty for the default, and rehydration of the blob
+	tier          string
+	archiveStatus string
+}
+
+// fakeAzureServer implements the subset of the Blob service REST API used by
+// AzureStorage, addressed Azurite-style as /<account>/<container>/<blob>, and
+// the Azure AD token endpoints used by workload identity
+type fakeAzureServer struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	blobs         map[string]*fakeAzureBlob
+	blocks        map[string]map[string][]byte
+	pageSize      int
+	authorization []string
+	queries       []string
+	stagedBlocks  int
+	assertions    []string
+}
+
+func newFakeAzureServer(t *testing.T, tls bool) *fakeAzureServer {
+	f := &fakeAzureServer{
+		blobs:    map[string]*fakeAzureBlob{},
+		blocks:   map[string]map[string][]byte{},
+		pageSize: 5000,
+	}
+	if tls {
+		f.Server = httptest.NewTLSServer(http.HandlerFunc(f.serveHTTP))
+	} else {
+		f.Server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	}
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeAzureServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.URL.Path, "/"+azureTestAccount+"/") {
+		f.serveAAD(w, r)
+		return
+	}
+	f.authorization = append(f.authorization, r.Header.Get("Authorization"))
+	f.queries = append(f.queries, r.URL.RawQuery)
+
+	containerName, name, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"+azureTestAccount+"/"), "/")
+	query := r.URL.Query()
+	switch {
+	case name == "" && query.Get("comp") == "list":
+		f.list(w, containerName, query.Get("prefix"), query.Get("marker"))
+	case r.Method == http.MethodPut && query.Get("comp") == "block":
+		data, _ := io.ReadAll(r.Body)
+		if f.blocks[name] == nil {
+			f.blocks[name] = map[string][]byte{}
+		}
+		f.blocks[name][query.Get("blockid")] = data
+		f.stagedBlocks++
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPut && query.Get("comp") == "blocklist":
+		var blockList struct {
+			Latest []string `xml:"Latest"`
+		}
+		if err := xml.NewDecoder(r.Body).Decode(&blockList); err != nil {
+			writeAzureError(w, http.StatusBadRequest, "InvalidXmlDocument")
+			return
+		}
+		blob := &fakeAzureBlob{metadata: map[string]string{}, created: time.Now()}
+		for _, id := range blockList.Latest {
+			block, ok := f.blocks[name][id]
+			if !ok {
+				writeAzureError(w, http.StatusBadRequest, "InvalidBlockList")
+				return
+			}
+			blob.data = append(blob.data, block...)
+		}
+		for header, values := range r.Header {
+			if strings.HasPrefix(strings.ToLower(header), "x-ms-meta-") {
+				blob.metadata[strings.ToLower(header[len("x-ms-meta-"):])] = values[0]
+			}
+		}
+		f.blobs[name] = blob
+		delete(f.blocks, name)
+		w.Header().Set("ETag", `"0x1"`)
+		w.Header().Set("Last-Modified", blob.created.UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPut && (query.Get("comp") == "immutabilityPolicies" || query.Get("comp") == "legalhold"):
+		blob, ok := f.blobs[name]
+		if !ok {
+			writeAzureError(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		if query.Get("comp") == "legalhold" {
+			blob.legalHold = r.Header.Get("x-ms-legal-hold") == "true"
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		until, err := time.Parse(time.RFC1123, r.Header.Get("x-ms-immutability-policy-until-date"))
+		if err != nil {
+			writeAzureError(w, http.StatusBadRequest, "InvalidHeaderValue")
+			return
+		}
+		blob.immutableUntil = until
+		blob.policyMode = r.Header.Get("x-ms-immutability-policy-mode")
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodPut && query.Get("comp") == "tier":
+		blob, ok := f.blobs[name]
+		if !ok {
+			writeAzureError(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		tier := r.Header.Get("x-ms-access-tier")
+		if blob.tier == "Archive" && tier != "Archive" {
+			blob.archiveStatus = "rehydrate-pending-to-" + strings.ToLower(tier)
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		blob.tier = tier
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodDelete:
+		blob, ok := f.blobs[name]
+		if !ok {
+			writeAzureError(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		if blob.legalHold || time.Now().Before(blob.immutableUntil) {
+			writeAzureError(w, http.StatusConflict, "BlobImmutableDueToPolicy")
+			return
+		}
+		delete(f.blobs, name)
+		w.WriteHeader(http.StatusAccepted)
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		blob, ok := f.blobs[name]
+		if !ok {
+			writeAzureError(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		for key, value := range blob.metadata {
+			w.Header()["x-ms-meta-"+key] = []string{value}
+		}
+		w.Header().Set("x-ms-blob-type", "BlockBlob")
+		if blob.tier != "" {
+			w.Header().Set("x-ms-access-tier", blob.tier)
+		}
+		if blob.archiveStatus != "" {
+			w.Header().Set("x-ms-archive-status", blob.archiveStatus)
+		}
+		w.Header().Set("x-ms-creation-time", blob.created.UTC().Format(http.TimeFormat))
+		w.Header().Set("Last-Modified", blob.created.UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Length", fmt.Sprint(len(blob.data)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(blob.data)
+		}
+	default:
+		writeAzureError(w, http.StatusMethodNotAllowed, "UnsupportedHttpVerb")
+	}
+}
+
+// serveAAD answers the Azure AD metadata and token requests made when
+// exchanging a federated token
+func (f *fakeAzureServer) serveAAD(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/oauth2/v2.0/token"):
+		_ = r.ParseForm()
+		f.assertions = append(f.assertions, r.PostForm.Get("client_assertion"))
+		writeJSON(w, http.StatusOK, map[string]interface{}{"access_token": "test-token", "token_type": "Bearer", "expires_in": 3600})
+	case r.URL.Path == "/common/discovery/instance":
+		host := strings.TrimPrefix(f.URL, "https://")
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"tenant_discovery_endpoint": f.URL + "/" + os.Getenv("AZURE_TENANT_ID") + "/v2.0/.well-known/openid-configuration",
+			"api-version":               "1.1",
+			"metadata":                  []map[string]interface{}{{"preferred_network": host, "preferred_cache": host, "aliases": []string{host}}},
+		})
+	case strings.HasSuffix(r.URL.Path, "/.well-known/openid-configuration"):
+		tenant := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")[0]
+		base := f.URL + "/" + tenant
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"token_endpoint":         base + "/oauth2/v2.0/token",
+			"authorization_endpoint": base + "/oauth2/v2.0/authorize",
+			"issuer":                 base + "/v2.0",
+		})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeAzureServer) list(w http.ResponseWriter, containerName, prefix, marker string) {
+	type blobProperties struct {
+		CreationTime  string `xml:"Creation-Time"`
+		LastModified  string `xml:"Last-Modified"`
+		ContentLength int    `xml:"Content-Length"`
+		BlobType      string `xml:"BlobType"`
+	}
+	type metadataEntry struct {
+		XMLName xml.Name
+		Value   string `xml:",chardata"`
+	}
+	type blobItem struct {
+		Name       string          `xml:"Name"`
+		Properties blobProperties  `xml:"Properties"`
+		Metadata   []metadataEntry `xml:"Metadata>any"`
+	}
+	type enumerationResults struct {
+		XMLName       xml.Name   `xml:"EnumerationResults"`
+		ContainerName string     `xml:"ContainerName,attr"`
+		Prefix        string     `xml:"Prefix"`
+		Blobs         []blobItem `xml:"Blobs>Blob"`
+		NextMarker    string     `xml:"NextMarker"`
+	}
+
+	names := []string{}
+	for name := range f.blobs {
+		if strings.HasPrefix(name, prefix) && name > marker {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	result := enumerationResults{ContainerName: containerName, Prefix: prefix}
+	if len(names) > f.pageSize {
+		names = names[:f.pageSize]
+		result.NextMarker = names[len(names)-1]
+	}
+	for _, name := range names {
+		blob := f.blobs[name]
+		item := blobItem{Name: name, Properties: blobProperties{
+			CreationTime:  blob.created.UTC().Format(http.TimeFormat),
+			LastModified:  blob.created.UTC().Format(http.TimeFormat),
+			ContentLength: len(blob.data),
+			BlobType:      "BlockBlob",
+		}}
+		for key, value := range blob.metadata {
+			item.Metadata = append(item.Metadata, metadataEntry{XMLName: xml.Name{Local: key}, Value: value})
+		}
+		result.Blobs = append(result.Blobs, item)
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(result)
+}
+
+// azureTestTransport sends every request to the fake server, including
+// instance discovery requests addressed to the public Azure AD host
+type azureTestTransport struct {
+	server *fakeAzureServer
+}
+
+func (t *azureTestTransport) Do(req *http.Request) (*http.Response, error) {
+	target, err := url.Parse(t.server.URL)
+	if err != nil {
+		return nil, err
+	}
+	req.URL.Scheme, req.URL.Host, req.Host = target.Scheme, target.Host, target.Host
+	return t.server.Client().Do(req)
+}
+
+func writeAzureError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("x-ms-error-code", code)
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
+
+func newTestAzureStorage(t *testing.T, server *fakeAzureServer, k8sClient client.Client, secret string) *AzureStorage {
+	t.Helper()
+
+	storage, err := NewAzureStorage(etcdguardianv1alpha1.StorageLocation{
+		Provider:          etcdguardianv1alpha1.StorageProviderAzure,
+		Bucket:            "backups",
+		Prefix:            "etcd",
+		Endpoint:          server.URL + "/" + azureTestAccount,
+		CredentialsSecret: secret,
+	}, k8sClient, "default")
+	if err != nil {
+		t.Fatalf("NewAzureStorage failed: %v", err)
+	}
+	storage.blockSize = 256 * 1024
+	storage.transport = &azureTestTransport{server: server}
+	return storage
+}
+
+func TestAzureStorage_UploadDownload(t *testing.T) {
+	server := newFakeAzureServer(t, false)
+	k8sClient := newFakeClient(credentialsSecret("azure-credentials", map[string]string{
+		azureStorageAccountKey:    azureTestAccount,
+		azureStorageAccountKeyKey: base64.StdEncoding.EncodeToString([]byte("account-key")),
+	}))
+	storage := newTestAzureStorage(t, server, k8sClient, "azure-credentials")
+
+	snapshotPath, data := writeSnapshot(t, 600*1024)
+	ctx := context.Background()
+
+	location, err := storage.Upload(ctx, snapshotPath, testBackup("nightly"))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if location != "azure://backups/etcd/default/nightly/etcd-snapshot.db" {
+		t.Errorf("Unexpected location %q", location)
+	}
+	if server.stagedBlocks != 3 {
+		t.Errorf("Expected 3 staged blocks, got %d", server.stagedBlocks)
+	}
+	if !strings.HasPrefix(server.authorization[0], "SharedKey "+azureTestAccount+":") {
+		t.Errorf("Expected shared key authorization, got %q", server.authorization[0])
+	}
+
+	metadata, err := storage.GetMetadata(ctx, location)
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if metadata.Size != int64(len(data)) {
+		t.Errorf("Expected size %d, got %d", len(data), metadata.Size)
+	}
+	if metadata.EtcdRevision != 42 {
+		t.Errorf("Expected revision 42, got %d", metadata.EtcdRevision)
+	}
+
+	downloadPath := filepath.Join(t.TempDir(), "restored.db")
+	if err := storage.Download(ctx, location, downloadPath); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	downloaded, err := os.ReadFile(downloadPath)
+	if err != nil {
+		t.Fatalf("Failed to read download: %v", err)
+	}
+	if !bytes.Equal(downloaded, data) {
+		t.Error("Downloaded snapshot does not match uploaded data")
+	}
+}
+
+func TestAzureStorage_ListAndDelete(t *testing.T) {
+	server := newFakeAzureServer(t, false)
+	server.pageSize = 2
+	k8sClient := newFakeClient(credentialsSecret("azure-credentials", map[string]string{
+		azureSASTokenKey: "?sv=2021-08-06&sp=racwdl&sig=c2lnbmF0dXJl",
+	}))
+	storage := newTestAzureStorage(t, server, k8sClient, "azure-credentials")
+	ctx := context.Background()
+
+	snapshotPath, _ := writeSnapshot(t, 1024)
+	for _, name := range []string{"daily", "daily-2", "hourly"} {
+		if _, err := storage.Upload(ctx, snapshotPath, testBackup(name)); err != nil {
+			t.Fatalf("Upload of %s failed: %v", name, err)
+		}
+	}
+	server.blobs["other/unrelated.db"] = &fakeAzureBlob{created: time.Now()}
+
+	snapshots, err := storage.List(ctx, "")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(snapshots) != 3 {
+		t.Fatalf("Expected 3 snapshots across pages, got %d", len(snapshots))
+	}
+	if snapshots[0].EtcdRevision != 42 {
+		t.Errorf("Expected listed metadata, got %+v", snapshots[0])
+	}
+
+	snapshots, err = storage.List(ctx, "default/daily/")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].Path != "azure://backups/etcd/default/daily/etcd-snapshot.db" {
+		t.Fatalf("Unexpected snapshots for prefix: %+v", snapshots)
+	}
+
+	if err := storage.Delete(ctx, snapshots[0].Path); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := storage.GetMetadata(ctx, snapshots[0].Path); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected not found error for deleted snapshot, got %v", err)
+	}
+
+	for i, query := range server.queries {
+		if !strings.Contains(query, "sig=") {
+			t.Errorf("Request %d is not signed with the SAS token: %q", i, query)
+		}
+		if server.authorization[i] != "" {
+			t.Errorf("Request %d has unexpected authorization %q", i, server.authorization[i])
+		}
+	}
+}
+
+func TestAzureStorage_LockObject(t *testing.T) {
+	server := newFakeAzureServer(t, false)
+	k8sClient := newFakeClient(credentialsSecret("azure-credentials", map[string]string{
+		azureStorageAccountKey:    azureTestAccount,
+		azureStorageAccountKeyKey: base64.StdEncoding.EncodeToString([]byte("account-key")),
+	}))
+	storage := newTestAzureStorage(t, server, k8sClient, "azure-credentials")
+	ctx := context.Background()
+
+	snapshotPath, _ := writeSnapshot(t, 1024)
+	location, err := storage.Upload(ctx, snapshotPath, testBackup("nightly"))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+
+	retainUntil := time.Now().Add(720 * time.Hour).Truncate(time.Second)
+	if err := storage.LockObject(ctx, location, etcdguardianv1alpha1.ImmutabilityModeCompliance, retainUntil); err != nil {
+		t.Fatalf("LockObject failed: %v", err)
+	}
+	blob := server.blobs["etcd/default/nightly/etcd-snapshot.db"]
+	if !blob.immutableUntil.Equal(retainUntil) || blob.policyMode != "Locked" {
+		t.Errorf("Expected locked policy until %s, got %s %s", retainUntil, blob.policyMode, blob.immutableUntil)
+	}
+	if err := storage.Delete(ctx, location); err == nil {
+		t.Error("Expected delete of an immutable blob to fail")
+	}
+
+	if err := storage.LockObject(ctx, location, etcdguardianv1alpha1.ImmutabilityModeGovernance, retainUntil); err != nil {
+		t.Fatalf("LockObject failed: %v", err)
+	}
+	if blob.policyMode != "Unlocked" {
+		t.Errorf("Expected unlocked policy for governance mode, got %s", blob.policyMode)
+	}
+
+	if err := storage.SetLegalHold(ctx, location, true); err != nil || !blob.legalHold {
+		t.Fatalf("Expected legal hold to be placed: %v", err)
+	}
+	if err := storage.SetLegalHold(ctx, location, false); err != nil || blob.legalHold {
+		t.Fatalf("Expected legal hold to be released: %v", err)
+	}
+
+	if err := storage.SetLegalHold(ctx, "azure://backups/etcd/missing.db", true); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("Expected ErrObjectNotFound, got %v", err)
+	}
+}
+
+func TestAzureStorage_Tiering(t *testing.T) {
+	server := newFakeAzureServer(t, false)
+	k8sClient := newFakeClient(credentialsSecret("azure-credentials", map[string]string{
+		azureStorageAccountKey:    azureTestAccount,
+		azureStorageAccountKeyKey: base64.StdEncoding.EncodeToString([]byte("account-key")),
+	}))
+	storage := newTestAzureStorage(t, server, k8sClient, "azure-credentials")
+	ctx := context.Background()
+
+	snapshotPath, _ := writeSnapshot(t, 1024)
+	location, err := storage.Upload(ctx, snapshotPath, testBackup("nightly"))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	blob := server.blobs["etcd/default/nightly/etcd-snapshot.db"]
+
+	if err := storage.SetTier(ctx, location, etcdguardianv1alpha1.StorageTierInfrequentAccess); err != nil || blob.tier != "Cool" {
+		t.Fatalf("Expected the blob in the Cool tier, got %q: %v", blob.tier, err)
+	}
+	if ready, err := storage.Rehydrate(ctx, location); err != nil || !ready {
+		t.Fatalf("Expected a Cool blob to be readable, got %v, %v", ready, err)
+	}
+
+	if err := storage.SetTier(ctx, location, etcdguardianv1alpha1.StorageTierArchive); err != nil || blob.tier != "Archive" {
+		t.Fatalf("Expected the blob in the Archive tier, got %q: %v", blob.tier, err)
+	}
+	if ready, err := storage.Rehydrate(ctx, location); err != nil || ready {
+		t.Fatalf("Expected the archived blob to be rehydrating, got %v, %v", ready, err)
+	}
+	if blob.archiveStatus != "rehydrate-pending-to-hot" {
+		t.Fatalf("Expected rehydration to Hot, got %q", blob.archiveStatus)
+	}
+	if ready, err := storage.Rehydrate(ctx, location); err != nil || ready {
+		t.Fatalf("Expected the archived blob to be rehydrating, got %v, %v", ready, err)
+	}
+
+	blob.tier, blob.archiveStatus = "Hot", ""
+	if ready, err := storage.Rehydrate(ctx, location); err != nil || !ready {
+		t.Fatalf("Expected the rehydrated blob to be readable, got %v, %v", ready, err)
+	}
+}
+
+func TestAzureStorage_PresignURL(t *testing.T) {
+	server := newFakeAzureServer(t, false)
+	k8sClient := newFakeClient(
+		credentialsSecret("azure-credentials", map[string]string{
+			azureStorageAccountKey:    azureTestAccount,
+			azureStorageAccountKeyKey: base64.StdEncoding.EncodeToString([]byte("account-key")),
+		}),
+		credentialsSecret("azure-sas", map[string]string{azureSASTokenKey: "sv=2021-08-06&sig=abc"}),
+	)
+	storage := newTestAzureStorage(t, server, k8sClient, "azure-credentials")
+	ctx := context.Background()
+
+	snapshotPath, data := writeSnapshot(t, 1024)
+	location, err := storage.Upload(ctx, snapshotPath, testBackup("nightly"))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+
+	signedURL, err := storage.PresignURL(ctx, location, time.Hour)
+	if err != nil {
+		t.Fatalf("PresignURL failed: %v", err)
+	}
+	parsed, err := url.Parse(signedURL)
+	if err != nil {
+		t.Fatalf("Invalid presigned URL %q: %v", signedURL, err)
+	}
+	if query := parsed.Query(); query.Get("sp") != "r" || query.Get("sig") == "" || query.Get("se") == "" {
+		t.Fatalf("Expected a read-only SAS, got %q", signedURL)
+	}
+
+	response, err := http.Get(signedURL)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer response.Body.Close()
+	downloaded, _ := io.ReadAll(response.Body)
+	if response.StatusCode != http.StatusOK || !bytes.Equal(downloaded, data) {
+		t.Errorf("Expected the snapshot from the presigned URL, got status %d and %d bytes", response.StatusCode, len(downloaded))
+	}
+
+	sasStorage := newTestAzureStorage(t, server, k8sClient, "azure-sas")
+	if _, err := sasStorage.PresignURL(ctx, location, time.Hour); err == nil {
+		t.Errorf("Expected a SAS token not to sign another SAS")
+	}
+}
+
+func TestAzureStorage_WorkloadIdentity(t *testing.T) {
+	server := newFakeAzureServer(t, true)
+
+	// The workload identity webhook projects a federated token and sets
+	// these variables on the pod
+	tokenFile := filepath.Join(t.TempDir(), "azure-identity-token")
+	if err := os.WriteFile(tokenFile, []byte("federated-token"), 0o600); err != nil {
+		t.Fatalf("Failed to write token: %v", err)
+	}
+	t.Setenv("AZURE_AUTHORITY_HOST", server.URL)
+	t.Setenv("AZURE_CLIENT_ID", "client-id")
+	t.Setenv("AZURE_TENANT_ID", "tenant-id")
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", tokenFile)
+
+	storage := newTestAzureStorage(t, server, nil, "")
+	if _, err := storage.List(context.Background(), ""); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(server.assertions) == 0 || server.assertions[0] != "federated-token" {
+		t.Errorf("Expected federated token exchange, got %v", server.assertions)
+	}
+	if server.authorization[0] != "Bearer test-token" {
+		t.Errorf("Expected bearer authorization, got %q", server.authorization[0])
+	}
+}
+
+func TestAzureStorage_MissingAccount(t *testing.T) {
+	k8sClient := newFakeClient(credentialsSecret("azure-credentials", map[string]string{
+		azureStorageAccountKeyKey: base64.StdEncoding.EncodeToString([]byte("account-key")),
+	}))
+	storage, err := NewAzureStorage(etcdguardianv1alpha1.StorageLocation{
+		Provider:          etcdguardianv1alpha1.StorageProviderAzure,
+		Bucket:            "backups",
+		CredentialsSecret: "azure-credentials",
+	}, k8sClient, "default")
+	if err != nil {
+		t.Fatalf("NewAzureStorage failed: %v", err)
+	}
+	t.Setenv(azureStorageAccountEnv, "")
+
+	if _, err := storage.List(context.Background(), ""); err == nil {
+		t.Error("Expected error without a storage account")
+	}
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// TestObjectStoreConformance runs the same checks against every backend
+// that implements ObjectStore. S3 has no client yet and is not covered.
+func TestObjectStoreConformance(t *testing.T) {
+	backends := map[string]func(t *testing.T) ObjectStore{
+		"filesystem": func(t *testing.T) ObjectStore {
+			storage, _ := newTestFilesystemStorage(t)
+			return storage
+		},
+		"oss": func(t *testing.T) ObjectStore {
+			server := newFakeOSSServer(t)
+			k8sClient := newFakeClient(credentialsSecret("oss-credentials", map[string]string{
+				ossAccessKeyIDKey:     "test-ak",
+				ossAccessKeySecretKey: "test-sk",
+			}))
+			return newTestOSSStorage(t, server, k8sClient, "oss-credentials")
+		},
+		"gcs": func(t *testing.T) ObjectStore {
+			server := newFakeGCSServer(t)
+			k8sClient := newFakeClient(credentialsSecret("gcs-credentials", map[string]string{
+				gcsServiceAccountKey: serviceAccountKey(t, server),
+			}))
+			return newTestGCSStorage(t, server, k8sClient, "gcs-credentials")
+		},
+		"azure": func(t *testing.T) ObjectStore {
+			server := newFakeAzureServer(t, false)
+			k8sClient := newFakeClient(credentialsSecret("azure-credentials", map[string]string{
+				azureStorageAccountKey:    azureTestAccount,
+				azureStorageAccountKeyKey: base64.StdEncoding.EncodeToString([]byte("account-key")),
+			}))
+			return newTestAzureStorage(t, server, k8sClient, "azure-credentials")
+		},
+		"memory": func(t *testing.T) ObjectStore {
+			return newTestMemoryStorage(t)
+		},
+		"sftp": func(t *testing.T) ObjectStore {
+			server := newFakeSFTPServer(t)
+			return newTestSFTPStorage(t, server, newFakeClient(sftpPasswordSecret(server)))
+		},
+	}
+
+	for name, newStore := range backends {
+		t.Run(name, func(t *testing.T) {
+			runObjectStoreConformance(t, newStore)
+		})
+	}
+}
+
+// runObjectStoreConformance checks the behaviour every ObjectStore must have
+func runObjectStoreConformance(t *testing.T, newStore func(t *testing.T) ObjectStore) {
+	ctx := context.Background()
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		store := newStore(t)
+		// Larger than the part and chunk sizes of the test backends
+		data := conformanceData(600 * 1024)
+		digest := sha256.Sum256(data)
+		checksum := hex.EncodeToString(digest[:])
+
+		object, err := store.PutObject(ctx, "etcd/conformance/snapshot.db", bytes.NewReader(data), PutOptions{
+			Size:     int64(len(data)),
+			SHA256:   checksum,
+			Metadata: snapshotObjectMetadata(testBackup("nightly")),
+		})
+		if err != nil {
+			t.Fatalf("PutObject failed: %v", err)
+		}
+		if object.Key != "etcd/conformance/snapshot.db" || object.Size != int64(len(data)) {
+			t.Errorf("Unexpected object %+v", object)
+		}
+
+		if got := getObject(t, store, object.Key); !bytes.Equal(got, data) {
+			t.Errorf("Downloaded content does not match the uploaded content")
+		}
+
+		stat, err := store.StatObject(ctx, object.Key)
+		if err != nil {
+			t.Fatalf("StatObject failed: %v", err)
+		}
+		if stat.Size != int64(len(data)) || stat.Location != object.Location {
+			t.Errorf("Unexpected object %+v", stat)
+		}
+		if stat.Metadata[metadataSHA256] != checksum || stat.Metadata[metadataBackupName] != "nightly" {
+			t.Errorf("Unexpected metadata %v", stat.Metadata)
+		}
+
+		key, err := store.ObjectKey(object.Location)
+		if err != nil || key != object.Key {
+			t.Errorf("Expected key %q for %q, got %q (%v)", object.Key, object.Location, key, err)
+		}
+	})
+
+	t.Run("UnknownSize", func(t *testing.T) {
+		store := newStore(t)
+		data := conformanceData(300 * 1024)
+
+		// Hide the Seeker of the reader, as of a stream from a pipe
+		object, err := store.PutObject(ctx, "etcd/conformance/stream.db", io.MultiReader(bytes.NewReader(data)), PutOptions{})
+		if err != nil {
+			t.Fatalf("PutObject failed: %v", err)
+		}
+		if object.Size != int64(len(data)) {
+			t.Errorf("Expected size %d, got %d", len(data), object.Size)
+		}
+		if got := getObject(t, store, object.Key); !bytes.Equal(got, data) {
+			t.Errorf("Downloaded content does not match the uploaded content")
+		}
+	})
+
+	t.Run("EmptyObject", func(t *testing.T) {
+		store := newStore(t)
+
+		object, err := store.PutObject(ctx, "etcd/conformance/empty", io.MultiReader(), PutOptions{})
+		if err != nil {
+			t.Fatalf("PutObject failed: %v", err)
+		}
+		if object.Size != 0 {
+			t.Errorf("Expected empty object, got %+v", object)
+		}
+		if got := getObject(t, store, object.Key); len(got) != 0 {
+			t.Errorf("Expected no content, got %d bytes", len(got))
+		}
+	})
+
+	t.Run("RejectsMismatchedContent", func(t *testing.T) {
+		store := newStore(t)
+		data := conformanceData(150 * 1024)
+		digest := sha256.Sum256(data)
+
+		cases := map[string]PutOptions{
+			"checksum": {Size: int64(len(data)), SHA256: hex.EncodeToString(make([]byte, sha256.Size))},
+			"size":     {Size: int64(len(data)) + 1, SHA256: hex.EncodeToString(digest[:])},
+		}
+		for name, opts := range cases {
+			key := "etcd/conformance/mismatch-" + name
+			if _, err := store.PutObject(ctx, key, io.MultiReader(bytes.NewReader(data)), opts); err == nil {
+				t.Errorf("Expected PutObject with a wrong %s to fail", name)
+			}
+			if _, err := store.StatObject(ctx, key); !errors.Is(err, ErrObjectNotFound) {
+				t.Errorf("Expected no object after a wrong %s, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("CancelledContext", func(t *testing.T) {
+		store := newStore(t)
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		data := conformanceData(150 * 1024)
+		if _, err := store.PutObject(cancelled, "etcd/conformance/cancelled", bytes.NewReader(data), PutOptions{Size: int64(len(data))}); err == nil {
+			t.Fatal("Expected PutObject with a cancelled context to fail")
+		}
+		if _, err := store.StatObject(ctx, "etcd/conformance/cancelled"); !errors.Is(err, ErrObjectNotFound) {
+			t.Errorf("Expected no object after cancelling, got %v", err)
+		}
+	})
+
+	t.Run("ListObjects", func(t *testing.T) {
+		store := newStore(t)
+		for _, key := range []string{"etcd/conformance/list/a", "etcd/conformance/list/nested/b", "etcd/conformance/other"} {
+			if _, err := store.PutObject(ctx, key, bytes.NewReader([]byte(key)), PutOptions{}); err != nil {
+				t.Fatalf("PutObject of %s failed: %v", key, err)
+			}
+		}
+
+		objects, err := store.ListObjects(ctx, "etcd/conformance/list/")
+		if err != nil {
+			t.Fatalf("ListObjects failed: %v", err)
+		}
+		keys := []string{}
+		for _, object := range objects {
+			keys = append(keys, object.Key)
+			if object.Size != int64(len(object.Key)) {
+				t.Errorf("Unexpected size %d of %s", object.Size, object.Key)
+			}
+		}
+		sort.Strings(keys)
+		if len(keys) != 2 || keys[0] != "etcd/conformance/list/a" || keys[1] != "etcd/conformance/list/nested/b" {
+			t.Errorf("Unexpected keys %v", keys)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		store := newStore(t)
+		if _, err := store.StatObject(ctx, "etcd/conformance/missing"); !errors.Is(err, ErrObjectNotFound) {
+			t.Errorf("Expected ErrObjectNotFound, got %v", err)
+		}
+
+		if _, err := store.PutObject(ctx, "etcd/conformance/deleted", bytes.NewReader([]byte("data")), PutOptions{}); err != nil {
+			t.Fatalf("PutObject failed: %v", err)
+		}
+		if err := store.DeleteObject(ctx, "etcd/conformance/deleted"); err != nil {
+			t.Fatalf("DeleteObject failed: %v", err)
+		}
+		if _, err := store.StatObject(ctx, "etcd/conformance/deleted"); !errors.Is(err, ErrObjectNotFound) {
+			t.Errorf("Expected ErrObjectNotFound after delete, got %v", err)
+		}
+	})
+}
+
+func conformanceData(size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i % 253)
+	}
+	return data
+}
+
+// getObject downloads an object through a temporary file
+func getObject(t *testing.T, store ObjectStore, key string) []byte {
+	t.Helper()
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "object"))
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	defer file.Close()
+
+	size, err := store.GetObject(context.Background(), key, file)
+	if err != nil {
+		t.Fatalf("GetObject of %s failed: %v", key, err)
+	}
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if size != int64(len(data)) {
+		t.Errorf("GetObject returned size %d for %d bytes", size, len(data))
+	}
+	return data
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+)
+
+// ValidateCredentials reads the credentials secret of a storage location and
+// checks that it holds what the provider needs, so that a backup fails on a
+// broken secret before its snapshot is taken. A missing secret is reported
+// with the NotFound error of the API server wrapped.
+func ValidateCredentials(ctx context.Context, k8sClient client.Client, namespace string, location etcdguardianv1alpha1.StorageLocation) error {
+	if sse := location.ServerSideEncryption; sse != nil && sse.Mode == etcdguardianv1alpha1.ServerSideEncryptionSSEC {
+		if _, err := loadCustomerKey(ctx, k8sClient, namespace, location); err != nil {
+			return err
+		}
+	}
+
+	name := location.CredentialsSecret
+	if name == "" {
+		if location.Provider == etcdguardianv1alpha1.StorageProviderSFTP {
+			return fmt.Errorf("SFTP storage location needs a credentials secret")
+		}
+		return nil
+	}
+	data, err := loadCredentials(ctx, k8sClient, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	switch location.Provider {
+	case etcdguardianv1alpha1.StorageProviderOSS:
+		if len(data[ossAccessKeyIDKey]) > 0 {
+			if len(data[ossAccessKeySecretKey]) == 0 {
+				return fmt.Errorf("credentials secret %s has no %s", name, ossAccessKeySecretKey)
+			}
+			return nil
+		}
+		if _, ok := data[ossRAMRoleNameKey]; !ok {
+			return fmt.Errorf("credentials secret %s has neither %s nor %s", name, ossAccessKeyIDKey, ossRAMRoleNameKey)
+		}
+	case etcdguardianv1alpha1.StorageProviderAzure:
+		if key := data[azureStorageAccountKeyKey]; len(key) > 0 {
+			if len(data[azureStorageAccountKey]) == 0 {
+				return fmt.Errorf("credentials secret %s has a shared key but no %s", name, azureStorageAccountKey)
+			}
+			if _, err := base64.StdEncoding.DecodeString(string(key)); err != nil {
+				return fmt.Errorf("%s of credentials secret %s is not base64 encoded", azureStorageAccountKeyKey, name)
+			}
+		} else if token := data[azureSASTokenKey]; len(token) > 0 {
+			query, err := url.ParseQuery(strings.TrimPrefix(string(token), "?"))
+			if err != nil || query.Get("sig") == "" {
+				return fmt.Errorf("%s of credentials secret %s is not a signed SAS token", azureSASTokenKey, name)
+			}
+		}
+	case etcdguardianv1alpha1.StorageProviderGCS:
+		key, ok := data[gcsServiceAccountKey]
+		if !ok {
+			return fmt.Errorf("credentials secret %s has no %s", name, gcsServiceAccountKey)
+		}
+		var account struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(key, &account); err != nil || account.Type == "" {
+			return fmt.Errorf("%s of credentials secret %s is not a service account key", gcsServiceAccountKey, name)
+		}
+	case etcdguardianv1alpha1.StorageProviderSFTP:
+		if len(data[sftpUsernameKey]) == 0 {
+			return fmt.Errorf("credentials secret %s has no %s", name, sftpUsernameKey)
+		}
+		if _, err := parseSFTPHostKeys(data[sftpHostKeyKey]); err != nil {
+			return fmt.Errorf("invalid %s in credentials secret %s: %w", sftpHostKeyKey, name, err)
+		}
+		privateKey := data[sftpPrivateKeyKey]
+		if len(privateKey) > 0 {
+			var err error
+			if passphrase := data[sftpPassphraseKey]; len(passphrase) > 0 {
+				_, err = ssh.ParsePrivateKeyWithPassphrase(privateKey, passphrase)
+			} else {
+				_, err = ssh.ParsePrivateKey(privateKey)
+			}
+			if err != nil {
+				return fmt.Errorf("invalid %s in credentials secret %s: %w", sftpPrivateKeyKey, name, err)
+			}
+		} else if len(data[sftpPasswordKey]) == 0 {
+			return fmt.Errorf("credentials secret %s has neither %s nor %s", name, sftpPrivateKeyKey, sftpPasswordKey)
+		}
+	}
+	return nil
+}
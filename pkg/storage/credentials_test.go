@@ -0,0 +1,96 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+)
+
+func TestValidateCredentials(t *testing.T) {
+	_, privateKey := newTestKey(t)
+	hostKey := string(ssh.MarshalAuthorizedKey(newTestSigner(t).PublicKey()))
+
+	tests := map[string]struct {
+		provider etcdguardianv1alpha1.StorageProvider
+		data     map[string]string
+		valid    bool
+	}{
+		"oss access key":           {etcdguardianv1alpha1.StorageProviderOSS, map[string]string{ossAccessKeyIDKey: "id", ossAccessKeySecretKey: "secret"}, true},
+		"oss ram role":             {etcdguardianv1alpha1.StorageProviderOSS, map[string]string{ossRAMRoleNameKey: "backup"}, true},
+		"oss without secret key":   {etcdguardianv1alpha1.StorageProviderOSS, map[string]string{ossAccessKeyIDKey: "id"}, false},
+		"oss empty":                {etcdguardianv1alpha1.StorageProviderOSS, map[string]string{}, false},
+		"azure shared key":         {etcdguardianv1alpha1.StorageProviderAzure, map[string]string{azureStorageAccountKey: "account", azureStorageAccountKeyKey: "a2V5"}, true},
+		"azure key not base64":     {etcdguardianv1alpha1.StorageProviderAzure, map[string]string{azureStorageAccountKey: "account", azureStorageAccountKeyKey: "not base64!"}, false},
+		"azure key no account":     {etcdguardianv1alpha1.StorageProviderAzure, map[string]string{azureStorageAccountKeyKey: "a2V5"}, false},
+		"azure sas token":          {etcdguardianv1alpha1.StorageProviderAzure, map[string]string{azureSASTokenKey: "?sv=2022-11-02&sig=abc"}, true},
+		"azure unsigned sas":       {etcdguardianv1alpha1.StorageProviderAzure, map[string]string{azureSASTokenKey: "sv=2022-11-02"}, false},
+		"azure workload identity":  {etcdguardianv1alpha1.StorageProviderAzure, map[string]string{azureClientIDKey: "client"}, true},
+		"gcs service account":      {etcdguardianv1alpha1.StorageProviderGCS, map[string]string{gcsServiceAccountKey: `{"type":"service_account"}`}, true},
+		"gcs invalid json":         {etcdguardianv1alpha1.StorageProviderGCS, map[string]string{gcsServiceAccountKey: "{"}, false},
+		"gcs missing key":          {etcdguardianv1alpha1.StorageProviderGCS, map[string]string{}, false},
+		"sftp private key":         {etcdguardianv1alpha1.StorageProviderSFTP, map[string]string{sftpUsernameKey: "backup", sftpHostKeyKey: hostKey, sftpPrivateKeyKey: privateKey}, true},
+		"sftp invalid private key": {etcdguardianv1alpha1.StorageProviderSFTP, map[string]string{sftpUsernameKey: "backup", sftpHostKeyKey: hostKey, sftpPrivateKeyKey: "key"}, false},
+		"sftp no host key":         {etcdguardianv1alpha1.StorageProviderSFTP, map[string]string{sftpUsernameKey: "backup", sftpPasswordKey: "secret"}, false},
+		"sftp no auth":             {etcdguardianv1alpha1.StorageProviderSFTP, map[string]string{sftpUsernameKey: "backup", sftpHostKeyKey: hostKey}, false},
+		"s3":                       {etcdguardianv1alpha1.StorageProviderS3, map[string]string{}, true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			k8sClient := newFakeClient(credentialsSecret("credentials", tt.data))
+			location := etcdguardianv1alpha1.StorageLocation{Provider: tt.provider, Bucket: "backups", CredentialsSecret: "credentials"}
+			err := ValidateCredentials(context.Background(), k8sClient, "default", location)
+			if tt.valid && err != nil {
+				t.Errorf("Expected valid credentials, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Errorf("Expected invalid credentials")
+			}
+		})
+	}
+}
+
+func TestValidateCredentials_MissingSecret(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := newFakeClient()
+
+	location := etcdguardianv1alpha1.StorageLocation{Provider: etcdguardianv1alpha1.StorageProviderOSS, Bucket: "backups", CredentialsSecret: "credentials"}
+	if err := ValidateCredentials(ctx, k8sClient, "default", location); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected a NotFound error, got %v", err)
+	}
+
+	location.CredentialsSecret = ""
+	if err := ValidateCredentials(ctx, k8sClient, "default", location); err != nil {
+		t.Errorf("Expected locations without a secret to pass, got %v", err)
+	}
+	location.Provider = etcdguardianv1alpha1.StorageProviderSFTP
+	if err := ValidateCredentials(ctx, k8sClient, "default", location); err == nil {
+		t.Errorf("Expected SFTP to need a secret")
+	}
+
+	location.Provider = etcdguardianv1alpha1.StorageProviderGCS
+	location.ServerSideEncryption = &etcdguardianv1alpha1.ServerSideEncryption{Mode: etcdguardianv1alpha1.ServerSideEncryptionSSEC, CustomerKeySecret: "sse"}
+	if err := ValidateCredentials(ctx, k8sClient, "default", location); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected the missing customer key secret to be reported, got %v", err)
+	}
+}
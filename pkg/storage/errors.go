@@ -0,0 +1,216 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/pkg/sftp"
+	"google.golang.org/api/googleapi"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ErrRetentionPolicyInsufficient is the error of locking an object in a
+// bucket whose retention policy does not retain it long enough. Creating
+// and extending bucket policies is left to administrators, as they can never
+// be removed or shortened.
+var ErrRetentionPolicyInsufficient = errors.New("retention policy of the bucket is insufficient")
+
+// ErrorClass is the class of a storage error, which decides whether the
+// operation is retried
+type ErrorClass string
+
+const (
+	// ErrorClassRetryable errors are transient, such as timeouts, throttling
+	// and server errors, and the operation is retried
+	ErrorClassRetryable ErrorClass = "Retryable"
+	// ErrorClassAuth errors are rejected or missing credentials
+	ErrorClassAuth ErrorClass = "Auth"
+	// ErrorClassNotFound errors are missing buckets or objects
+	ErrorClassNotFound ErrorClass = "NotFound"
+	// ErrorClassQuota errors are exhausted storage quotas or space
+	ErrorClassQuota ErrorClass = "Quota"
+	// ErrorClassRetention errors are retention policies of buckets that are
+	// missing or too short to retain an object as long as required
+	ErrorClassRetention ErrorClass = "Retention"
+	// ErrorClassObjectLocked errors are writes and deletes of objects that
+	// a retention policy or legal hold protects
+	ErrorClassObjectLocked ErrorClass = "ObjectLocked"
+	// ErrorClassPermanent errors are any other errors that retrying does
+	// not resolve, such as invalid requests
+	ErrorClassPermanent ErrorClass = "Permanent"
+)
+
+// Retryable reports whether an operation failing with an error of the class
+// may succeed when retried
+func (c ErrorClass) Retryable() bool {
+	return c == ErrorClassRetryable
+}
+
+// Reason returns the machine-readable reason reported for an operation that
+// failed with an error of the class
+func (c ErrorClass) Reason() string {
+	switch c {
+	case ErrorClassRetryable:
+		return "StorageUnavailable"
+	case ErrorClassAuth:
+		return "StorageAuthFailed"
+	case ErrorClassNotFound:
+		return "StorageNotFound"
+	case ErrorClassQuota:
+		return "StorageQuotaExceeded"
+	case ErrorClassRetention:
+		return "RetentionPolicyInsufficient"
+	case ErrorClassObjectLocked:
+		return "StorageObjectLocked"
+	}
+	return "StorageFailed"
+}
+
+// Error codes of the provider APIs that report an exhausted quota
+var quotaErrorCodes = map[string]bool{
+	"QuotaExceeded":              true,
+	"QuotaExceed":                true,
+	"InsufficientStorage":        true,
+	"AccountCapacityExceeded":    true,
+	"ContainerQuotaExceeded":     true,
+	"StorageQuotaExceeded":       true,
+	"InsufficientAccountQuota":   true,
+	"BucketStorageQuotaExceeded": true,
+}
+
+// Error codes of the provider APIs that refuse to change an object under
+// retention or legal hold
+var objectLockedErrorCodes = map[string]bool{
+	"FileImmutable":               true,
+	"BlobImmutableDueToPolicy":    true,
+	"BlobImmutableDueToLegalHold": true,
+	"retentionPolicyNotMet":       true,
+}
+
+// Classify returns the class of an error returned by a storage backend
+func Classify(err error) ErrorClass {
+	if err == nil {
+		return ""
+	}
+
+	var opErr *OperationError
+	if errors.As(err, &opErr) {
+		return opErr.Class
+	}
+	var injected *InjectedError
+	if errors.As(err, &injected) {
+		return injected.Class
+	}
+
+	if errors.Is(err, ErrRetentionPolicyInsufficient) {
+		return ErrorClassRetention
+	}
+
+	// Missing objects first: backends wrap the provider error on not found
+	if errors.Is(err, ErrObjectNotFound) || errors.Is(err, fs.ErrNotExist) {
+		return ErrorClassNotFound
+	}
+
+	var ossErr oss.ServiceError
+	if errors.As(err, &ossErr) {
+		return classifyStatus(ossErr.StatusCode, ossErr.Code)
+	}
+	var azureErr *azcore.ResponseError
+	if errors.As(err, &azureErr) {
+		return classifyStatus(azureErr.StatusCode, azureErr.ErrorCode)
+	}
+	var googleErr *googleapi.Error
+	if errors.As(err, &googleErr) {
+		code := ""
+		for _, item := range googleErr.Errors {
+			// Request rate limits of a project, not the stored bytes
+			if item.Reason == "quotaExceeded" || item.Reason == "rateLimitExceeded" {
+				return ErrorClassRetryable
+			}
+			if code == "" {
+				code = item.Reason
+			}
+		}
+		return classifyStatus(googleErr.Code, code)
+	}
+	var sftpErr *sftp.StatusError
+	if errors.As(err, &sftpErr) {
+		switch sftpErr.FxCode() {
+		case sftp.ErrSSHFxNoSuchFile:
+			return ErrorClassNotFound
+		case sftp.ErrSSHFxPermissionDenied:
+			return ErrorClassAuth
+		case sftp.ErrSSHFxNoConnection, sftp.ErrSSHFxConnectionLost:
+			return ErrorClassRetryable
+		}
+	}
+
+	// Credentials secrets are read from the API server
+	if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) || apierrors.IsUnauthorized(err) {
+		return ErrorClassAuth
+	}
+	if errors.Is(err, fs.ErrPermission) || errors.Is(err, syscall.EACCES) || errors.Is(err, syscall.EPERM) {
+		return ErrorClassAuth
+	}
+	if errors.Is(err, syscall.ENOSPC) || errors.Is(err, syscall.EDQUOT) {
+		return ErrorClassQuota
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) || errors.Is(err, sftp.ErrSSHFxConnectionLost) {
+		return ErrorClassRetryable
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ErrorClassRetryable
+	}
+	// The ssh package reports rejected keys and passwords only as text
+	if strings.Contains(err.Error(), "ssh: unable to authenticate") {
+		return ErrorClassAuth
+	}
+	return ErrorClassPermanent
+}
+
+// classifyStatus returns the class of an HTTP error response of a provider
+func classifyStatus(statusCode int, code string) ErrorClass {
+	if objectLockedErrorCodes[code] {
+		return ErrorClassObjectLocked
+	}
+	if quotaErrorCodes[code] || statusCode == http.StatusInsufficientStorage {
+		return ErrorClassQuota
+	}
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return ErrorClassAuth
+	case statusCode == http.StatusNotFound:
+		return ErrorClassNotFound
+	case statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests || statusCode >= 500:
+		return ErrorClassRetryable
+	}
+	return ErrorClassPermanent
+}
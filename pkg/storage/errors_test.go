@@ -0,0 +1,74 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/pkg/sftp"
+	"google.golang.org/api/googleapi"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestClassify(t *testing.T) {
+	cases := map[string]struct {
+		err   error
+		class ErrorClass
+	}{
+		"missing object":         {fmt.Errorf("oss://bucket/key: %w", ErrObjectNotFound), ErrorClassNotFound},
+		"missing file":           {fmt.Errorf("open: %w", os.ErrNotExist), ErrorClassNotFound},
+		"oss forbidden":          {oss.ServiceError{StatusCode: 403, Code: "AccessDenied"}, ErrorClassAuth},
+		"oss unavailable":        {fmt.Errorf("upload: %w", oss.ServiceError{StatusCode: 503, Code: "ServiceUnavailable"}), ErrorClassRetryable},
+		"oss quota":              {oss.ServiceError{StatusCode: 403, Code: "QuotaExceeded"}, ErrorClassQuota},
+		"oss bad request":        {oss.ServiceError{StatusCode: 400, Code: "InvalidArgument"}, ErrorClassPermanent},
+		"azure throttled":        {&azcore.ResponseError{StatusCode: 429, ErrorCode: "ServerBusy"}, ErrorClassRetryable},
+		"azure missing":          {&azcore.ResponseError{StatusCode: 404, ErrorCode: "ContainerNotFound"}, ErrorClassNotFound},
+		"azure capacity":         {&azcore.ResponseError{StatusCode: 409, ErrorCode: "AccountCapacityExceeded"}, ErrorClassQuota},
+		"azure immutable":        {&azcore.ResponseError{StatusCode: 409, ErrorCode: "BlobImmutableDueToPolicy"}, ErrorClassObjectLocked},
+		"oss immutable":          {oss.ServiceError{StatusCode: 409, Code: "FileImmutable"}, ErrorClassObjectLocked},
+		"gcs retained":           {&googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "retentionPolicyNotMet"}}}, ErrorClassObjectLocked},
+		"gcs unauthorized":       {&googleapi.Error{Code: 401}, ErrorClassAuth},
+		"gcs rate limited":       {&googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}}}, ErrorClassRetryable},
+		"sftp permission denied": {&sftp.StatusError{Code: uint32(sftp.ErrSSHFxPermissionDenied)}, ErrorClassAuth},
+		"sftp connection lost":   {fmt.Errorf("write: %w", sftp.ErrSSHFxConnectionLost), ErrorClassRetryable},
+		"ssh rejected":           {errors.New("ssh: handshake failed: ssh: unable to authenticate"), ErrorClassAuth},
+		"missing secret":         {fmt.Errorf("failed to get credentials secret: %w", apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "creds")), ErrorClassAuth},
+		"disk full":              {fmt.Errorf("write: %w", syscall.ENOSPC), ErrorClassQuota},
+		"connection reset":       {fmt.Errorf("read: %w", syscall.ECONNRESET), ErrorClassRetryable},
+		"timeout":                {fmt.Errorf("upload: %w", context.DeadlineExceeded), ErrorClassRetryable},
+		"cancelled":              {context.Canceled, ErrorClassPermanent},
+		"operation":              {fmt.Errorf("upload: %w", &OperationError{Class: ErrorClassQuota, Err: errors.New("full")}), ErrorClassQuota},
+		"unknown":                {errors.New("checksum mismatch"), ErrorClassPermanent},
+	}
+
+	for name, tc := range cases {
+		if class := Classify(tc.err); class != tc.class {
+			t.Errorf("%s: expected %s, got %s", name, tc.class, class)
+		}
+	}
+	if class := Classify(nil); class != "" {
+		t.Errorf("Expected no class for nil, got %s", class)
+	}
+}
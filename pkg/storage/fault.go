@@ -0,0 +1,466 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+)
+
+// FaultOperation names the kind of storage operation a fault applies to.
+// The Storage and ObjectStore methods map to them: Upload and PutObject
+// are puts, Download and GetObject gets, GetMetadata and StatObject stats.
+type FaultOperation string
+
+const (
+	FaultPut    FaultOperation = "Put"
+	FaultGet    FaultOperation = "Get"
+	FaultStat   FaultOperation = "Stat"
+	FaultList   FaultOperation = "List"
+	FaultDelete FaultOperation = "Delete"
+)
+
+// Fault describes a failure injected into storage operations. A fault
+// applies to the operations it matches, and its effects combine: the
+// operation is delayed by the latency first, then performed with the
+// partial write or corruption, and finally fails with Err if set.
+type Fault struct {
+	// Operations the fault applies to, all operations when empty
+	Operations []FaultOperation
+
+	// Key restricts the fault to objects whose key or remote path contains
+	// it, and listings whose prefix does. Empty matches every object.
+	Key string
+
+	// Latency delays the operation, or until the context is cancelled
+	Latency time.Duration
+
+	// Err is returned by the operation. With a partial write or corruption
+	// the operation is still performed before it fails, as when a
+	// connection drops after the backend committed the object.
+	Err error
+
+	// PartialWrite stores only the first PartialWrite bytes of the content
+	// of a put, which the backend accepts as the complete object. Zero
+	// writes the whole content.
+	PartialWrite int64
+
+	// Corrupt flips a byte of the content that is put or got
+	Corrupt bool
+
+	// Times is how often the fault fires before it is spent, every time
+	// when zero
+	Times int
+}
+
+// InjectedError is an error for Fault.Err that Classify reports as the
+// given class, to exercise the retries and failure reasons of callers
+type InjectedError struct {
+	Class ErrorClass
+}
+
+func (e *InjectedError) Error() string {
+	return fmt.Sprintf("injected %s storage error", e.Class)
+}
+
+// FaultSet is a set of faults that may change while backends use it
+type FaultSet struct {
+	mu     sync.Mutex
+	faults []*faultState
+}
+
+// faultState is a fault with the number of times it fired
+type faultState struct {
+	Fault
+	fired int
+}
+
+// Add adds a fault to the set
+func (s *FaultSet) Add(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &faultState{Fault: fault})
+}
+
+// Clear removes every fault from the set
+func (s *FaultSet) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Fired returns how often the faults of the set fired in total
+func (s *FaultSet) Fired() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fired := 0
+	for _, fault := range s.faults {
+		fired += fault.fired
+	}
+	return fired
+}
+
+// take returns the combined effect of the faults matching an operation on
+// an object, and counts them as fired
+func (s *FaultSet) take(operation FaultOperation, object string) Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	effect := Fault{}
+	for _, fault := range s.faults {
+		if !fault.matches(operation, object) {
+			continue
+		}
+		fault.fired++
+		effect.Latency += fault.Latency
+		if effect.Err == nil {
+			effect.Err = fault.Err
+		}
+		if fault.PartialWrite > 0 && (effect.PartialWrite == 0 || fault.PartialWrite < effect.PartialWrite) {
+			effect.PartialWrite = fault.PartialWrite
+		}
+		effect.Corrupt = effect.Corrupt || fault.Corrupt
+	}
+	return effect
+}
+
+// matches reports whether a fault that is not spent applies to an operation
+func (f *faultState) matches(operation FaultOperation, object string) bool {
+	if f.Times > 0 && f.fired >= f.Times {
+		return false
+	}
+	if f.Key != "" && !strings.Contains(object, f.Key) {
+		return false
+	}
+	if len(f.Operations) == 0 {
+		return true
+	}
+	for _, faultOperation := range f.Operations {
+		if faultOperation == operation {
+			return true
+		}
+	}
+	return false
+}
+
+// FaultInjectingStorage wraps a storage backend and injects the faults of
+// its fault set into every operation. It implements ObjectStore when the
+// wrapped backend does; the other optional interfaces of the wrapped
+// backend are not exposed.
+type FaultInjectingStorage struct {
+	backend Storage
+	faults  *FaultSet
+}
+
+// NewFaultInjectingStorage wraps a storage backend with the faults of a set
+func NewFaultInjectingStorage(backend Storage, faults *FaultSet) *FaultInjectingStorage {
+	return &FaultInjectingStorage{backend: backend, faults: faults}
+}
+
+// inject applies the latency of the faults matching an operation and
+// returns their effect
+func (f *FaultInjectingStorage) inject(ctx context.Context, operation FaultOperation, object string) (Fault, error) {
+	effect := f.faults.take(operation, object)
+	if effect.Latency > 0 {
+		timer := time.NewTimer(effect.Latency)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return effect, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return effect, nil
+}
+
+// Upload uploads a snapshot, truncated or corrupted as injected
+func (f *FaultInjectingStorage) Upload(ctx context.Context, localPath string, backup *etcdguardianv1alpha1.EtcdBackup) (string, error) {
+	effect, err := f.inject(ctx, FaultPut, snapshotKey("", backup, localPath))
+	if err != nil {
+		return "", err
+	}
+
+	if effect.PartialWrite > 0 || effect.Corrupt {
+		// The file keeps its name, which is part of the snapshot key
+		dir, err := os.MkdirTemp("", "etcdguardian-fault-")
+		if err != nil {
+			return "", err
+		}
+		defer os.RemoveAll(dir)
+		faulty := filepath.Join(dir, filepath.Base(localPath))
+		if err := copyFaulty(localPath, faulty, effect); err != nil {
+			return "", err
+		}
+		localPath = faulty
+	}
+
+	location, err := f.backend.Upload(ctx, localPath, backup)
+	if err != nil {
+		return "", err
+	}
+	return location, effect.Err
+}
+
+// Download downloads a snapshot, corrupted as injected
+func (f *FaultInjectingStorage) Download(ctx context.Context, remotePath, localPath string) error {
+	effect, err := f.inject(ctx, FaultGet, remotePath)
+	if err != nil {
+		return err
+	}
+	if err := f.backend.Download(ctx, remotePath, localPath); err != nil {
+		return err
+	}
+	if effect.Corrupt {
+		if err := corruptFile(localPath); err != nil {
+			return err
+		}
+	}
+	return effect.Err
+}
+
+// List lists snapshots
+func (f *FaultInjectingStorage) List(ctx context.Context, prefix string) ([]SnapshotMetadata, error) {
+	effect, err := f.inject(ctx, FaultList, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if effect.Err != nil {
+		return nil, effect.Err
+	}
+	return f.backend.List(ctx, prefix)
+}
+
+// Delete deletes a snapshot
+func (f *FaultInjectingStorage) Delete(ctx context.Context, remotePath string) error {
+	effect, err := f.inject(ctx, FaultDelete, remotePath)
+	if err != nil {
+		return err
+	}
+	if effect.Err != nil {
+		return effect.Err
+	}
+	return f.backend.Delete(ctx, remotePath)
+}
+
+// GetMetadata gets snapshot metadata
+func (f *FaultInjectingStorage) GetMetadata(ctx context.Context, remotePath string) (*SnapshotMetadata, error) {
+	effect, err := f.inject(ctx, FaultStat, remotePath)
+	if err != nil {
+		return nil, err
+	}
+	if effect.Err != nil {
+		return nil, effect.Err
+	}
+	return f.backend.GetMetadata(ctx, remotePath)
+}
+
+// objectStore returns the wrapped backend as an ObjectStore
+func (f *FaultInjectingStorage) objectStore() (ObjectStore, error) {
+	store, ok := f.backend.(ObjectStore)
+	if !ok {
+		return nil, fmt.Errorf("wrapped storage backend does not support streaming")
+	}
+	return store, nil
+}
+
+// PutObject stores an object, truncated or corrupted as injected. A
+// truncated object is stored without its expected size and checksum, so
+// that the backend accepts it.
+func (f *FaultInjectingStorage) PutObject(ctx context.Context, key string, r io.Reader, opts PutOptions) (*ObjectInfo, error) {
+	store, err := f.objectStore()
+	if err != nil {
+		return nil, err
+	}
+	effect, err := f.inject(ctx, FaultPut, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if effect.PartialWrite > 0 {
+		r = io.LimitReader(r, effect.PartialWrite)
+		opts.Size, opts.SHA256 = 0, ""
+	}
+	if effect.Corrupt {
+		r = &corruptingReader{r: r}
+	}
+	object, err := store.PutObject(ctx, key, r, opts)
+	if err != nil {
+		return nil, err
+	}
+	return object, effect.Err
+}
+
+// GetObject writes the content of an object to w, corrupted as injected
+func (f *FaultInjectingStorage) GetObject(ctx context.Context, key string, w io.WriterAt) (int64, error) {
+	store, err := f.objectStore()
+	if err != nil {
+		return 0, err
+	}
+	effect, err := f.inject(ctx, FaultGet, key)
+	if err != nil {
+		return 0, err
+	}
+
+	if effect.Corrupt {
+		w = &corruptingWriterAt{w: w}
+	}
+	n, err := store.GetObject(ctx, key, w)
+	if err != nil {
+		return n, err
+	}
+	return n, effect.Err
+}
+
+// StatObject returns the attributes and user metadata of an object
+func (f *FaultInjectingStorage) StatObject(ctx context.Context, key string) (*ObjectInfo, error) {
+	store, err := f.objectStore()
+	if err != nil {
+		return nil, err
+	}
+	effect, err := f.inject(ctx, FaultStat, key)
+	if err != nil {
+		return nil, err
+	}
+	if effect.Err != nil {
+		return nil, effect.Err
+	}
+	return store.StatObject(ctx, key)
+}
+
+// ListObjects lists the objects whose key starts with prefix
+func (f *FaultInjectingStorage) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	store, err := f.objectStore()
+	if err != nil {
+		return nil, err
+	}
+	effect, err := f.inject(ctx, FaultList, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if effect.Err != nil {
+		return nil, effect.Err
+	}
+	return store.ListObjects(ctx, prefix)
+}
+
+// DeleteObject deletes an object
+func (f *FaultInjectingStorage) DeleteObject(ctx context.Context, key string) error {
+	store, err := f.objectStore()
+	if err != nil {
+		return err
+	}
+	effect, err := f.inject(ctx, FaultDelete, key)
+	if err != nil {
+		return err
+	}
+	if effect.Err != nil {
+		return effect.Err
+	}
+	return store.DeleteObject(ctx, key)
+}
+
+// ObjectKey returns the key of the object a remote path addresses
+func (f *FaultInjectingStorage) ObjectKey(remotePath string) (string, error) {
+	store, err := f.objectStore()
+	if err != nil {
+		return "", err
+	}
+	return store.ObjectKey(remotePath)
+}
+
+// corruptingReader flips the first byte it reads
+type corruptingReader struct {
+	r    io.Reader
+	done bool
+}
+
+func (c *corruptingReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	if n > 0 && !c.done {
+		b[0] ^= 0xff
+		c.done = true
+	}
+	return n, err
+}
+
+// corruptingWriterAt flips the first byte of the content it writes
+type corruptingWriterAt struct {
+	w io.WriterAt
+}
+
+func (c *corruptingWriterAt) WriteAt(b []byte, off int64) (int, error) {
+	if off == 0 && len(b) > 0 {
+		corrupted := append([]byte{}, b...)
+		corrupted[0] ^= 0xff
+		return c.w.WriteAt(corrupted, off)
+	}
+	return c.w.WriteAt(b, off)
+}
+
+// copyFaulty copies a file, truncated and corrupted as a fault requires
+func copyFaulty(source, target string, effect Fault) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer in.Close()
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = in
+	if effect.PartialWrite > 0 {
+		r = io.LimitReader(r, effect.PartialWrite)
+	}
+	if effect.Corrupt {
+		r = &corruptingReader{r: r}
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// corruptFile flips the first byte of a file
+func corruptFile(name string) error {
+	file, err := os.OpenFile(name, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	b := make([]byte, 1)
+	if _, err := file.ReadAt(b, 0); err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+	b[0] ^= 0xff
+	_, err = file.WriteAt(b, 0)
+	return err
+}
@@ -0,0 +1,274 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+)
+
+// filesystemTempSuffix marks files that are still being written
+const filesystemTempSuffix = ".tmp"
+
+// FilesystemStorage stores snapshots on a mounted volume, such as a PVC or a
+// hostPath. The bucket of the storage location is the root directory, and
+// snapshots use the same key layout as the object storage providers with the
+// user metadata kept in a JSON sidecar next to each snapshot.
+type FilesystemStorage struct {
+	root   string
+	prefix string
+}
+
+// NewFilesystemStorage creates a new filesystem storage backend
+func NewFilesystemStorage(location etcdguardianv1alpha1.StorageLocation) (*FilesystemStorage, error) {
+	if !filepath.IsAbs(location.Bucket) {
+		return nil, fmt.Errorf("filesystem storage root %q must be an absolute path", location.Bucket)
+	}
+	return &FilesystemStorage{
+		root:   filepath.Clean(location.Bucket),
+		prefix: location.Prefix,
+	}, nil
+}
+
+// Upload copies a snapshot below the root directory
+func (f *FilesystemStorage) Upload(ctx context.Context, localPath string, backup *etcdguardianv1alpha1.EtcdBackup) (string, error) {
+	return putFile(ctx, f, f.prefix, localPath, backup)
+}
+
+// Download copies a snapshot from the root directory
+func (f *FilesystemStorage) Download(ctx context.Context, remotePath, localPath string) error {
+	return getFile(ctx, f, remotePath, localPath)
+}
+
+// List lists snapshots below the root directory
+func (f *FilesystemStorage) List(ctx context.Context, prefix string) ([]SnapshotMetadata, error) {
+	return listSnapshots(ctx, f, f.prefix, prefix)
+}
+
+// Delete deletes a snapshot and its metadata
+func (f *FilesystemStorage) Delete(ctx context.Context, remotePath string) error {
+	return deleteObject(ctx, f, remotePath)
+}
+
+// GetMetadata gets snapshot metadata from the file and its sidecar
+func (f *FilesystemStorage) GetMetadata(ctx context.Context, remotePath string) (*SnapshotMetadata, error) {
+	return statSnapshot(ctx, f, remotePath)
+}
+
+// PutObject writes an object below the root directory. The file only
+// appears under its final name once it and its metadata are fully written.
+func (f *FilesystemStorage) PutObject(ctx context.Context, key string, r io.Reader, opts PutOptions) (*ObjectInfo, error) {
+	target := f.filePath(key)
+	remotePath := "file://" + target
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create directory for %s: %w", remotePath, err)
+	}
+
+	metadata, err := json.Marshal(opts.objectMetadata())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode metadata: %w", err)
+	}
+	if err := writeFileAtomic(target+metadataSidecarSuffix, bytes.NewReader(metadata)); err != nil {
+		return nil, err
+	}
+
+	if err := writeFileAtomic(target, newStreamReader(ctx, r, opts)); err != nil {
+		return nil, fmt.Errorf("failed to upload to %s: %w", remotePath, err)
+	}
+
+	return f.StatObject(ctx, key)
+}
+
+// GetObject copies an object from the root directory to w
+func (f *FilesystemStorage) GetObject(ctx context.Context, key string, w io.WriterAt) (int64, error) {
+	file, err := os.Open(f.filePath(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, fmt.Errorf("file://%s: %w", f.filePath(key), ErrObjectNotFound)
+		}
+		return 0, err
+	}
+	defer file.Close()
+
+	return io.Copy(io.NewOffsetWriter(w, 0), newStreamReader(ctx, file, PutOptions{}))
+}
+
+// StatObject returns the attributes of a file and the metadata in its sidecar
+func (f *FilesystemStorage) StatObject(ctx context.Context, key string) (*ObjectInfo, error) {
+	object, err := f.objectInfo(f.filePath(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("file://%s: %w", f.filePath(key), ErrObjectNotFound)
+		}
+		return nil, err
+	}
+	return object, nil
+}
+
+// ListObjects lists the files below the root directory whose key starts
+// with prefix
+func (f *FilesystemStorage) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	// Only walk the deepest directory that can contain matching keys
+	walkRoot := f.root
+	if dir := path.Dir(prefix + "x"); dir != "." {
+		walkRoot = f.filePath(dir)
+	}
+
+	objects := []ObjectInfo{}
+	err := filepath.WalkDir(walkRoot, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() || !isFilesystemObject(entry.Name()) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(f.root, filePath)
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(filepath.ToSlash(rel), prefix) {
+			return nil
+		}
+
+		object, err := f.objectInfo(filePath)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, *object)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", f.root, err)
+	}
+	return objects, nil
+}
+
+// DeleteObject deletes a file and its metadata, then removes any directories
+// left empty below the root
+func (f *FilesystemStorage) DeleteObject(ctx context.Context, key string) error {
+	target := f.filePath(key)
+
+	if err := os.Remove(target); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("file://%s: %w", target, ErrObjectNotFound)
+		}
+		return fmt.Errorf("failed to delete file://%s: %w", target, err)
+	}
+	if err := os.Remove(target + metadataSidecarSuffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete metadata of file://%s: %w", target, err)
+	}
+
+	for dir := filepath.Dir(target); dir != f.root; dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// ObjectKey returns the key of the file a remote path addresses
+func (f *FilesystemStorage) ObjectKey(remotePath string) (string, error) {
+	target, err := f.resolve(remotePath)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(f.root, target)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// objectInfo builds the object attributes of a file
+func (f *FilesystemStorage) objectInfo(filePath string) (*ObjectInfo, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := filepath.Rel(f.root, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	object := &ObjectInfo{
+		Key:      filepath.ToSlash(rel),
+		Location: "file://" + filePath,
+		Size:     info.Size(),
+		Created:  info.ModTime(),
+		Metadata: map[string]string{},
+	}
+
+	// Files copied in by hand may have no sidecar
+	data, err := os.ReadFile(filePath + metadataSidecarSuffix)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return object, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &object.Metadata); err != nil {
+		return nil, fmt.Errorf("invalid metadata sidecar of %s: %w", filePath, err)
+	}
+
+	return object, nil
+}
+
+// resolve returns the file addressed by a remote path, which must lie below
+// the root directory
+func (f *FilesystemStorage) resolve(remotePath string) (string, error) {
+	var target string
+	if rest, ok := strings.CutPrefix(remotePath, "file://"); ok {
+		target = filepath.Clean(rest)
+	} else if strings.Contains(remotePath, "://") {
+		return "", fmt.Errorf("remote path %q is not a file path", remotePath)
+	} else {
+		target = f.filePath(remotePath)
+	}
+
+	rel, err := filepath.Rel(f.root, target)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("remote path %q is outside of %s", remotePath, f.root)
+	}
+	return target, nil
+}
+
+// filePath returns the file of an object key
+func (f *FilesystemStorage) filePath(key string) string {
+	return filepath.Join(f.root, filepath.FromSlash(path.Clean("/"+key)))
+}
+
+// isFilesystemObject reports whether a file name is an object rather than a
+// metadata sidecar or a partially written file
+func isFilesystemObject(name string) bool {
+	return !strings.HasSuffix(name, metadataSidecarSuffix) && !strings.HasSuffix(name, filesystemTempSuffix)
+}
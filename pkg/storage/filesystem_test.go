@@ -0,0 +1,189 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+)
+
+func newTestFilesystemStorage(t *testing.T) (*FilesystemStorage, string) {
+	t.Helper()
+
+	root := t.TempDir()
+	storage, err := NewStorage(etcdguardianv1alpha1.StorageProviderFilesystem, etcdguardianv1alpha1.StorageLocation{
+		Provider: etcdguardianv1alpha1.StorageProviderFilesystem,
+		Bucket:   root,
+		Prefix:   "etcd",
+	}, nil, "default")
+	if err != nil {
+		t.Fatalf("NewStorage failed: %v", err)
+	}
+	return storage.(*FilesystemStorage), root
+}
+
+func TestFilesystemStorage_UploadDownload(t *testing.T) {
+	storage, root := newTestFilesystemStorage(t)
+	snapshotPath, data := writeSnapshot(t, 64*1024)
+	ctx := context.Background()
+
+	location, err := storage.Upload(ctx, snapshotPath, testBackup("nightly"))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	target := filepath.Join(root, "etcd", "default", "nightly", "etcd-snapshot.db")
+	if location != "file://"+target {
+		t.Errorf("Unexpected location %q", location)
+	}
+	if _, err := os.Stat(target + metadataSidecarSuffix); err != nil {
+		t.Errorf("Expected metadata sidecar: %v", err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(target))
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), filesystemTempSuffix) {
+			t.Errorf("Temporary file %s left behind", entry.Name())
+		}
+	}
+
+	metadata, err := storage.GetMetadata(ctx, location)
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if metadata.Size != int64(len(data)) {
+		t.Errorf("Expected size %d, got %d", len(data), metadata.Size)
+	}
+	if metadata.EtcdRevision != 42 {
+		t.Errorf("Expected revision 42, got %d", metadata.EtcdRevision)
+	}
+
+	downloadPath := filepath.Join(t.TempDir(), "restored.db")
+	if err := storage.Download(ctx, location, downloadPath); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	downloaded, err := os.ReadFile(downloadPath)
+	if err != nil {
+		t.Fatalf("Failed to read download: %v", err)
+	}
+	if !bytes.Equal(downloaded, data) {
+		t.Error("Downloaded snapshot does not match uploaded data")
+	}
+}
+
+func TestFilesystemStorage_ListAndDelete(t *testing.T) {
+	storage, root := newTestFilesystemStorage(t)
+	ctx := context.Background()
+
+	snapshotPath, _ := writeSnapshot(t, 1024)
+	for _, name := range []string{"daily", "daily-2", "hourly"} {
+		if _, err := storage.Upload(ctx, snapshotPath, testBackup(name)); err != nil {
+			t.Fatalf("Upload of %s failed: %v", name, err)
+		}
+	}
+
+	// Neither interrupted uploads nor files outside the prefix are snapshots
+	if err := os.WriteFile(filepath.Join(root, "etcd", "default", "hourly", "etcd-snapshot.db.123.tmp"), []byte("partial"), 0o600); err != nil {
+		t.Fatalf("Failed to write temporary file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "other"), 0o750); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "other", "unrelated.db"), []byte("other"), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	snapshots, err := storage.List(ctx, "")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(snapshots) != 3 {
+		t.Fatalf("Expected 3 snapshots, got %+v", snapshots)
+	}
+
+	snapshots, err = storage.List(ctx, "default/daily/")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	dailyDir := filepath.Join(root, "etcd", "default", "daily")
+	if len(snapshots) != 1 || snapshots[0].Path != "file://"+filepath.Join(dailyDir, "etcd-snapshot.db") {
+		t.Fatalf("Unexpected snapshots for prefix: %+v", snapshots)
+	}
+
+	if err := storage.Delete(ctx, snapshots[0].Path); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := storage.GetMetadata(ctx, snapshots[0].Path); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected not found error for deleted snapshot, got %v", err)
+	}
+	if _, err := os.Stat(dailyDir); !os.IsNotExist(err) {
+		t.Errorf("Expected empty directory %s to be removed", dailyDir)
+	}
+
+	snapshots, err = storage.List(ctx, "missing/")
+	if err != nil || len(snapshots) != 0 {
+		t.Errorf("Expected no snapshots for missing prefix, got %+v, %v", snapshots, err)
+	}
+}
+
+func TestFilesystemStorage_SnapshotWithoutSidecar(t *testing.T) {
+	storage, root := newTestFilesystemStorage(t)
+
+	dir := filepath.Join(root, "etcd", "imported")
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "snapshot.db"), []byte("snapshot"), 0o600); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+
+	metadata, err := storage.GetMetadata(context.Background(), "etcd/imported/snapshot.db")
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if metadata.Size != 8 || metadata.EtcdRevision != 0 {
+		t.Errorf("Unexpected metadata %+v", metadata)
+	}
+}
+
+func TestFilesystemStorage_RejectsPathsOutsideRoot(t *testing.T) {
+	storage, root := newTestFilesystemStorage(t)
+	ctx := context.Background()
+
+	for _, remotePath := range []string{
+		"file://" + filepath.Dir(root) + "/other/etcd-snapshot.db",
+		"file://" + root,
+		"s3://bucket/etcd-snapshot.db",
+	} {
+		if err := storage.Delete(ctx, remotePath); err == nil {
+			t.Errorf("Expected error for %q", remotePath)
+		}
+	}
+
+	// Relative keys are confined to the root
+	if _, err := storage.resolve("../../etc/passwd"); err != nil {
+		t.Errorf("Expected relative key to resolve below the root: %v", err)
+	}
+
+	if _, err := NewFilesystemStorage(etcdguardianv1alpha1.StorageLocation{Bucket: "backups"}); err == nil {
+		t.Error("Expected error for relative root directory")
+	}
+}
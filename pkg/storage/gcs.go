@@ -0,0 +1,354 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	gcs "cloud.google.com/go/storage"
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// gcsChunkSize is the size of each request of a resumable upload
+	gcsChunkSize = 16 * 1024 * 1024
+
+	// gcsServiceAccountKey is the key of the service account JSON key in the
+	// credentials secret
+	gcsServiceAccountKey = "service-account.json"
+)
+
+// GCSStorage implements Google Cloud Storage
+type GCSStorage struct {
+	location  etcdguardianv1alpha1.StorageLocation
+	client    client.Client
+	namespace string
+
+	chunkSize int
+
+	mu        sync.Mutex
+	gcsClient *gcs.Client
+	// customerKey is the customer-supplied encryption key of SSE-C
+	customerKey []byte
+}
+
+// NewGCSStorage creates a new GCS storage backend
+func NewGCSStorage(location etcdguardianv1alpha1.StorageLocation, k8sClient client.Client, namespace string) (*GCSStorage, error) {
+	return &GCSStorage{
+		location:  location,
+		client:    k8sClient,
+		namespace: namespace,
+		chunkSize: gcsChunkSize,
+	}, nil
+}
+
+// Upload uploads a snapshot to GCS using a resumable upload
+func (g *GCSStorage) Upload(ctx context.Context, localPath string, backup *etcdguardianv1alpha1.EtcdBackup) (string, error) {
+	return putFile(ctx, g, g.location.Prefix, localPath, backup)
+}
+
+// Download downloads a snapshot from GCS
+func (g *GCSStorage) Download(ctx context.Context, remotePath, localPath string) error {
+	return getFile(ctx, g, remotePath, localPath)
+}
+
+// List lists snapshots in GCS
+func (g *GCSStorage) List(ctx context.Context, prefix string) ([]SnapshotMetadata, error) {
+	return listSnapshots(ctx, g, g.location.Prefix, prefix)
+}
+
+// Delete deletes a snapshot from GCS
+func (g *GCSStorage) Delete(ctx context.Context, remotePath string) error {
+	return deleteObject(ctx, g, remotePath)
+}
+
+// GetMetadata gets snapshot metadata from GCS
+func (g *GCSStorage) GetMetadata(ctx context.Context, remotePath string) (*SnapshotMetadata, error) {
+	return statSnapshot(ctx, g, remotePath)
+}
+
+// PutObject streams an object to GCS. Content larger than the chunk size is
+// sent as a resumable upload session.
+func (g *GCSStorage) PutObject(ctx context.Context, key string, r io.Reader, opts PutOptions) (*ObjectInfo, error) {
+	object, err := g.object(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	remotePath := g.remotePath(key)
+
+	// Cancelling the context aborts the upload session
+	uploadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	writer := object.NewWriter(uploadCtx)
+	writer.ChunkSize = g.chunkSize
+	writer.ContentType = "application/octet-stream"
+	writer.Metadata = opts.objectMetadata()
+	if sse := g.location.ServerSideEncryption; sse != nil && sse.Mode == etcdguardianv1alpha1.ServerSideEncryptionSSEKMS {
+		writer.KMSKeyName = sse.KMSKeyID
+	}
+
+	if _, err := io.Copy(writer, newStreamReader(ctx, r, opts)); err != nil {
+		cancel()
+		_ = writer.Close()
+		return nil, fmt.Errorf("failed to upload to %s: %w", remotePath, err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to upload to %s: %w", remotePath, err)
+	}
+
+	return gcsObjectInfo(writer.Attrs()), nil
+}
+
+// GetObject downloads an object from GCS to w
+func (g *GCSStorage) GetObject(ctx context.Context, key string, w io.WriterAt) (int64, error) {
+	object, err := g.object(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+
+	reader, err := object.NewReader(ctx)
+	if err != nil {
+		return 0, g.objectError(key, err)
+	}
+	defer reader.Close()
+
+	return io.Copy(io.NewOffsetWriter(w, 0), reader)
+}
+
+// StatObject returns the attributes of an object in GCS
+func (g *GCSStorage) StatObject(ctx context.Context, key string) (*ObjectInfo, error) {
+	object, err := g.object(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs, err := object.Attrs(ctx)
+	if err != nil {
+		return nil, g.objectError(key, err)
+	}
+	return gcsObjectInfo(attrs), nil
+}
+
+// ListObjects lists the objects in GCS whose key starts with prefix
+func (g *GCSStorage) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	gcsClient, err := g.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []ObjectInfo{}
+	iter := gcsClient.Bucket(g.location.Bucket).Objects(ctx, &gcs.Query{Prefix: prefix})
+	for {
+		attrs, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			return objects, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list gs://%s: %w", g.location.Bucket, err)
+		}
+		objects = append(objects, *gcsObjectInfo(attrs))
+	}
+}
+
+// DeleteObject deletes an object from GCS
+func (g *GCSStorage) DeleteObject(ctx context.Context, key string) error {
+	object, err := g.object(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if err := object.Delete(ctx); err != nil {
+		return g.objectError(key, err)
+	}
+	return nil
+}
+
+// VerifyEncryption checks that an object is encrypted with the customer-managed
+// or customer-supplied key the server-side encryption settings require.
+// GCS encrypts every object, with Google-managed keys by default.
+func (g *GCSStorage) VerifyEncryption(ctx context.Context, remotePath string) error {
+	sse := g.location.ServerSideEncryption
+	if sse == nil {
+		return nil
+	}
+
+	key, err := g.ObjectKey(remotePath)
+	if err != nil {
+		return err
+	}
+	object, err := g.object(ctx, key)
+	if err != nil {
+		return err
+	}
+	attrs, err := object.Attrs(ctx)
+	if err != nil {
+		return g.objectError(key, err)
+	}
+
+	switch sse.Mode {
+	case etcdguardianv1alpha1.ServerSideEncryptionSSEKMS:
+		// The key name of the object includes the key version
+		if attrs.KMSKeyName != sse.KMSKeyID && !strings.HasPrefix(attrs.KMSKeyName, sse.KMSKeyID+"/cryptoKeyVersions/") {
+			return fmt.Errorf("%s is encrypted with KMS key %q instead of %s", remotePath, attrs.KMSKeyName, sse.KMSKeyID)
+		}
+	case etcdguardianv1alpha1.ServerSideEncryptionSSEC:
+		digest := sha256.Sum256(g.customerKey)
+		if attrs.CustomerKeySHA256 != base64.StdEncoding.EncodeToString(digest[:]) {
+			return fmt.Errorf("%s is not encrypted with the customer-supplied key", remotePath)
+		}
+	}
+	return nil
+}
+
+// ObjectKey returns the key of the object a remote path addresses, which
+// must be in the bucket of the location
+func (g *GCSStorage) ObjectKey(remotePath string) (string, error) {
+	bucket, key, err := splitRemotePath(remotePath, "gs", g.location.Bucket)
+	if err != nil {
+		return "", err
+	}
+	if bucket != g.location.Bucket {
+		return "", fmt.Errorf("remote path %q is not in bucket %s", remotePath, g.location.Bucket)
+	}
+	return key, nil
+}
+
+// PresignURL returns a V4 signed URL of an object. The URL is signed with
+// the private key of the service account key when present, otherwise
+// through the IAM signBlob API, which the Workload Identity service account
+// needs the Service Account Token Creator role for.
+func (g *GCSStorage) PresignURL(ctx context.Context, remotePath string, expiry time.Duration) (string, error) {
+	key, err := g.ObjectKey(remotePath)
+	if err != nil {
+		return "", err
+	}
+	gcsClient, err := g.getClient(ctx)
+	if err != nil {
+		return "", err
+	}
+	if g.customerKey != nil {
+		// Reading the object takes the customer key in request headers,
+		// which a plain GET cannot send
+		return "", fmt.Errorf("objects encrypted with a customer-supplied key cannot be presigned")
+	}
+
+	signedURL, err := gcsClient.Bucket(g.location.Bucket).SignedURL(key, &gcs.SignedURLOptions{
+		Method:  "GET",
+		Expires: time.Now().Add(expiry),
+		Scheme:  gcs.SigningSchemeV4,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to presign %s: %w", remotePath, err)
+	}
+	return signedURL, nil
+}
+
+// gcsObjectInfo converts GCS object attributes
+func gcsObjectInfo(attrs *gcs.ObjectAttrs) *ObjectInfo {
+	return &ObjectInfo{
+		Key:      attrs.Name,
+		Location: fmt.Sprintf("gs://%s/%s", attrs.Bucket, attrs.Name),
+		Size:     attrs.Size,
+		Created:  attrs.Created,
+		Metadata: attrs.Metadata,
+	}
+}
+
+// objectError wraps an error of an object operation, marking missing objects
+func (g *GCSStorage) objectError(key string, err error) error {
+	if errors.Is(err, gcs.ErrObjectNotExist) {
+		return fmt.Errorf("%s: %w", g.remotePath(key), ErrObjectNotFound)
+	}
+	return fmt.Errorf("%s: %w", g.remotePath(key), err)
+}
+
+// object returns the handle of an object in the bucket of the location
+func (g *GCSStorage) object(ctx context.Context, key string) (*gcs.ObjectHandle, error) {
+	gcsClient, err := g.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	object := gcsClient.Bucket(g.location.Bucket).Object(key)
+	if g.customerKey != nil {
+		object = object.Key(g.customerKey)
+	}
+	return object, nil
+}
+
+// remotePath returns the remote path of an object key
+func (g *GCSStorage) remotePath(key string) string {
+	return fmt.Sprintf("gs://%s/%s", g.location.Bucket, key)
+}
+
+// getClient lazily creates the GCS client on first use. A service account
+// key from the credentials secret is used when present, otherwise the
+// Application Default Credentials, which resolve to Workload Identity on GKE.
+func (g *GCSStorage) getClient(ctx context.Context) (*gcs.Client, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.gcsClient != nil {
+		return g.gcsClient, nil
+	}
+
+	options := []option.ClientOption{gcs.WithJSONReads()}
+	if g.location.Endpoint != "" {
+		options = append(options, option.WithEndpoint(g.location.Endpoint))
+	}
+
+	if g.location.CredentialsSecret != "" {
+		data, err := loadCredentials(ctx, g.client, g.namespace, g.location.CredentialsSecret)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := data[gcsServiceAccountKey]
+		if !ok {
+			return nil, fmt.Errorf("credentials secret %s has no %s", g.location.CredentialsSecret, gcsServiceAccountKey)
+		}
+		options = append(options, option.WithCredentialsJSON(key))
+	}
+
+	if sse := g.location.ServerSideEncryption; sse != nil && sse.Mode == etcdguardianv1alpha1.ServerSideEncryptionSSEC {
+		customerKey, err := loadCustomerKey(ctx, g.client, g.namespace, g.location)
+		if err != nil {
+			return nil, err
+		}
+		g.customerKey = customerKey
+	}
+
+	// The client outlives the reconcile request, so it must not be bound to
+	// the request context
+	gcsClient, err := gcs.NewClient(context.Background(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gcs client: %w", err)
+	}
+
+	g.gcsClient = gcsClient
+	return gcsClient, nil
+}
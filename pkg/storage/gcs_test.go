@@ -0,0 +1,501 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fakeGCSObject is an object stored by fakeGCSServer
+type fakeGCSObject struct {
+	data     []byte
+	metadata map[string]string
+	created  time.Time
+	// Encryption of the object with a customer-managed or -supplied key
+	kmsKeyName string
+	keySHA256  string
+}
+
+// fakeGCSUpload is an in-progress resumable upload session
+type fakeGCSUpload struct {
+	name       string
+	metadata   map[string]string
+	data       []byte
+	kmsKeyName string
+	keySHA256  string
+}
+
+// fakeGCSServer implements the subset of the GCS JSON API and OAuth token
+// endpoint used by GCSStorage
+type fakeGCSServer struct {
+	*httptest.Server
+
+	mu             sync.Mutex
+	objects        map[string]*fakeGCSObject
+	uploads        map[string]*fakeGCSUpload
+	nextUploadID   int
+	pageSize       int
+	authorization  []string
+	resumableChunk int
+}
+
+func newFakeGCSServer(t *testing.T) *fakeGCSServer {
+	f := &fakeGCSServer{
+		objects:  map[string]*fakeGCSObject{},
+		uploads:  map[string]*fakeGCSUpload{},
+		pageSize: 1000,
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeGCSServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/token" {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"access_token": "test-token", "token_type": "Bearer", "expires_in": 3600})
+		return
+	}
+	f.authorization = append(f.authorization, r.Header.Get("Authorization"))
+
+	escaped := r.URL.EscapedPath()
+	query := r.URL.Query()
+	switch {
+	case strings.HasPrefix(escaped, "/upload/storage/v1/b/"):
+		f.upload(w, r)
+	case strings.HasPrefix(escaped, "/storage/v1/b/"):
+		bucket, object, _ := strings.Cut(strings.TrimPrefix(escaped, "/storage/v1/b/"), "/o")
+		name, _ := url.PathUnescape(strings.TrimPrefix(object, "/"))
+		switch {
+		case name == "" && r.Method == http.MethodGet:
+			f.list(w, bucket, query)
+		case r.Method == http.MethodDelete:
+			if _, ok := f.objects[name]; !ok {
+				writeGCSError(w, http.StatusNotFound)
+				return
+			}
+			delete(f.objects, name)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet:
+			stored, ok := f.objects[name]
+			if !ok {
+				writeGCSError(w, http.StatusNotFound)
+				return
+			}
+			if stored.keySHA256 != r.Header.Get("X-Goog-Encryption-Key-Sha256") {
+				writeGCSError(w, http.StatusBadRequest)
+				return
+			}
+			if query.Get("alt") == "media" {
+				http.ServeContent(w, r, name, stored.created, bytes.NewReader(stored.data))
+				return
+			}
+			writeJSON(w, http.StatusOK, stored.resource(bucket, name))
+		default:
+			writeGCSError(w, http.StatusMethodNotAllowed)
+		}
+	default:
+		writeGCSError(w, http.StatusNotFound)
+	}
+}
+
+func (f *fakeGCSServer) upload(w http.ResponseWriter, r *http.Request) {
+	bucket := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/upload/storage/v1/b/"), "/o")
+	query := r.URL.Query()
+
+	switch {
+	case query.Get("uploadType") == "multipart":
+		_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		reader := multipart.NewReader(r.Body, params["boundary"])
+		var resource struct {
+			Name     string            `json:"name"`
+			Metadata map[string]string `json:"metadata"`
+		}
+		part, _ := reader.NextPart()
+		_ = json.NewDecoder(part).Decode(&resource)
+		part, _ = reader.NextPart()
+		data, _ := io.ReadAll(part)
+		stored := &fakeGCSObject{
+			data:       data,
+			metadata:   resource.Metadata,
+			created:    time.Now(),
+			kmsKeyName: gcsKeyVersion(query.Get("kmsKeyName")),
+			keySHA256:  r.Header.Get("X-Goog-Encryption-Key-Sha256"),
+		}
+		f.objects[resource.Name] = stored
+		writeJSON(w, http.StatusOK, stored.resource(bucket, resource.Name))
+	case query.Get("upload_id") == "":
+		var resource struct {
+			Name     string            `json:"name"`
+			Metadata map[string]string `json:"metadata"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&resource)
+		f.nextUploadID++
+		uploadID := strconv.Itoa(f.nextUploadID)
+		f.uploads[uploadID] = &fakeGCSUpload{
+			name:       resource.Name,
+			metadata:   resource.Metadata,
+			kmsKeyName: gcsKeyVersion(query.Get("kmsKeyName")),
+			keySHA256:  r.Header.Get("X-Goog-Encryption-Key-Sha256"),
+		}
+		w.Header().Set("Location", fmt.Sprintf("%s%s?uploadType=resumable&upload_id=%s", f.URL, r.URL.Path, uploadID))
+		w.WriteHeader(http.StatusOK)
+	default:
+		upload, ok := f.uploads[query.Get("upload_id")]
+		if !ok {
+			writeGCSError(w, http.StatusNotFound)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		upload.data = append(upload.data, data...)
+		f.resumableChunk++
+
+		// Content-Range is "bytes start-end/*" until the final chunk
+		// carries the total size. The client asks for "200 OK" with a
+		// status override instead of a bare 308.
+		if strings.HasSuffix(r.Header.Get("Content-Range"), "/*") {
+			w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(upload.data)-1))
+			w.Header().Set("X-Http-Status-Code-Override", "308")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		stored := &fakeGCSObject{
+			data:       upload.data,
+			metadata:   upload.metadata,
+			created:    time.Now(),
+			kmsKeyName: upload.kmsKeyName,
+			keySHA256:  upload.keySHA256,
+		}
+		f.objects[upload.name] = stored
+		delete(f.uploads, query.Get("upload_id"))
+		writeJSON(w, http.StatusOK, stored.resource(bucket, upload.name))
+	}
+}
+
+func (f *fakeGCSServer) list(w http.ResponseWriter, bucket string, query url.Values) {
+	names := []string{}
+	for name := range f.objects {
+		if strings.HasPrefix(name, query.Get("prefix")) && name > query.Get("pageToken") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	response := map[string]interface{}{"kind": "storage#objects"}
+	if len(names) > f.pageSize {
+		names = names[:f.pageSize]
+		response["nextPageToken"] = names[len(names)-1]
+	}
+	items := []map[string]interface{}{}
+	for _, name := range names {
+		items = append(items, f.objects[name].resource(bucket, name))
+	}
+	response["items"] = items
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (o *fakeGCSObject) resource(bucket, name string) map[string]interface{} {
+	resource := map[string]interface{}{
+		"kind":        "storage#object",
+		"bucket":      bucket,
+		"name":        name,
+		"size":        strconv.Itoa(len(o.data)),
+		"metadata":    o.metadata,
+		"timeCreated": o.created.UTC().Format(time.RFC3339Nano),
+		"updated":     o.created.UTC().Format(time.RFC3339Nano),
+	}
+	if o.kmsKeyName != "" {
+		resource["kmsKeyName"] = o.kmsKeyName
+	}
+	if o.keySHA256 != "" {
+		resource["customerEncryption"] = map[string]string{"encryptionAlgorithm": "AES256", "keySha256": o.keySHA256}
+	}
+	return resource
+}
+
+// gcsKeyVersion returns the name of the first version of a Cloud KMS key,
+// as GCS reports it for objects encrypted with the key
+func gcsKeyVersion(keyName string) string {
+	if keyName == "" {
+		return ""
+	}
+	return keyName + "/cryptoKeyVersions/1"
+}
+
+func writeGCSError(w http.ResponseWriter, status int) {
+	writeJSON(w, status, map[string]interface{}{
+		"error": map[string]interface{}{"code": status, "message": http.StatusText(status)},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// serviceAccountKey returns a service account JSON key whose token endpoint
+// is served by the fake server
+func serviceAccountKey(t *testing.T, server *fakeGCSServer) string {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	document, _ := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "etcdguardian-test",
+		"private_key_id": "test-key",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"client_email":   "etcd-backup@etcdguardian-test.iam.gserviceaccount.com",
+		"client_id":      "1234567890",
+		"token_uri":      server.URL + "/token",
+	})
+	return string(document)
+}
+
+func newTestGCSStorage(t *testing.T, server *fakeGCSServer, k8sClient client.Client, secret string) *GCSStorage {
+	t.Helper()
+
+	storage, err := NewGCSStorage(etcdguardianv1alpha1.StorageLocation{
+		Provider:          etcdguardianv1alpha1.StorageProviderGCS,
+		Bucket:            "backups",
+		Prefix:            "etcd",
+		Region:            "us-central1",
+		Endpoint:          server.URL + "/storage/v1/",
+		CredentialsSecret: secret,
+	}, k8sClient, "default")
+	if err != nil {
+		t.Fatalf("NewGCSStorage failed: %v", err)
+	}
+	storage.chunkSize = 256 * 1024
+	return storage
+}
+
+func TestGCSStorage_UploadDownload(t *testing.T) {
+	server := newFakeGCSServer(t)
+	k8sClient := newFakeClient(credentialsSecret("gcs-credentials", map[string]string{
+		gcsServiceAccountKey: serviceAccountKey(t, server),
+	}))
+	storage := newTestGCSStorage(t, server, k8sClient, "gcs-credentials")
+
+	snapshotPath, data := writeSnapshot(t, 600*1024)
+	ctx := context.Background()
+
+	location, err := storage.Upload(ctx, snapshotPath, testBackup("nightly"))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if location != "gs://backups/etcd/default/nightly/etcd-snapshot.db" {
+		t.Errorf("Unexpected location %q", location)
+	}
+	if server.resumableChunk != 3 {
+		t.Errorf("Expected 3 resumable upload chunks, got %d", server.resumableChunk)
+	}
+	if !strings.HasPrefix(server.authorization[0], "Bearer ") {
+		t.Errorf("Expected authenticated request, got %q", server.authorization[0])
+	}
+
+	metadata, err := storage.GetMetadata(ctx, location)
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if metadata.Size != int64(len(data)) {
+		t.Errorf("Expected size %d, got %d", len(data), metadata.Size)
+	}
+	if metadata.EtcdRevision != 42 {
+		t.Errorf("Expected revision 42, got %d", metadata.EtcdRevision)
+	}
+
+	downloadPath := filepath.Join(t.TempDir(), "restored.db")
+	if err := storage.Download(ctx, location, downloadPath); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	downloaded, err := os.ReadFile(downloadPath)
+	if err != nil {
+		t.Fatalf("Failed to read download: %v", err)
+	}
+	if !bytes.Equal(downloaded, data) {
+		t.Error("Downloaded snapshot does not match uploaded data")
+	}
+}
+
+func TestGCSStorage_ServerSideEncryption(t *testing.T) {
+	server := newFakeGCSServer(t)
+	customerKey := bytes.Repeat([]byte{7}, 32)
+	k8sClient := newFakeClient(
+		credentialsSecret("gcs-credentials", map[string]string{
+			gcsServiceAccountKey: serviceAccountKey(t, server),
+		}),
+		credentialsSecret("sse-key", map[string]string{
+			sseCustomerKeyKey: base64.StdEncoding.EncodeToString(customerKey),
+		}),
+	)
+	snapshotPath, data := writeSnapshot(t, 600*1024)
+	ctx := context.Background()
+	kmsKey := "projects/etcd/locations/global/keyRings/backups/cryptoKeys/snapshots"
+
+	plain := newTestGCSStorage(t, server, k8sClient, "gcs-credentials")
+	unencrypted, err := plain.Upload(ctx, snapshotPath, testBackup("plain"))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+
+	kms := newTestGCSStorage(t, server, k8sClient, "gcs-credentials")
+	kms.location.ServerSideEncryption = &etcdguardianv1alpha1.ServerSideEncryption{
+		Mode:     etcdguardianv1alpha1.ServerSideEncryptionSSEKMS,
+		KMSKeyID: kmsKey,
+	}
+	location, err := kms.Upload(ctx, snapshotPath, testBackup("kms"))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if err := kms.VerifyEncryption(ctx, location); err != nil {
+		t.Errorf("Expected the KMS key to be verified: %v", err)
+	}
+	if err := kms.VerifyEncryption(ctx, unencrypted); err == nil {
+		t.Error("Expected an object without the KMS key to fail verification")
+	}
+
+	csek := newTestGCSStorage(t, server, k8sClient, "gcs-credentials")
+	csek.location.ServerSideEncryption = &etcdguardianv1alpha1.ServerSideEncryption{
+		Mode:              etcdguardianv1alpha1.ServerSideEncryptionSSEC,
+		CustomerKeySecret: "sse-key",
+	}
+	location, err = csek.Upload(ctx, snapshotPath, testBackup("csek"))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if err := csek.VerifyEncryption(ctx, location); err != nil {
+		t.Errorf("Expected the customer-supplied key to be verified: %v", err)
+	}
+	if _, err := plain.GetMetadata(ctx, location); err == nil {
+		t.Error("Expected reading without the customer-supplied key to fail")
+	}
+
+	downloadPath := filepath.Join(t.TempDir(), "restored.db")
+	if err := csek.Download(ctx, location, downloadPath); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if downloaded, _ := os.ReadFile(downloadPath); !bytes.Equal(downloaded, data) {
+		t.Error("Downloaded snapshot does not match uploaded data")
+	}
+}
+
+func TestGCSStorage_ListAndDelete(t *testing.T) {
+	server := newFakeGCSServer(t)
+	server.pageSize = 2
+	k8sClient := newFakeClient(credentialsSecret("gcs-credentials", map[string]string{
+		gcsServiceAccountKey: serviceAccountKey(t, server),
+	}))
+	storage := newTestGCSStorage(t, server, k8sClient, "gcs-credentials")
+	ctx := context.Background()
+
+	snapshotPath, _ := writeSnapshot(t, 1024)
+	for _, name := range []string{"daily", "daily-2", "hourly"} {
+		if _, err := storage.Upload(ctx, snapshotPath, testBackup(name)); err != nil {
+			t.Fatalf("Upload of %s failed: %v", name, err)
+		}
+	}
+	server.objects["other/unrelated.db"] = &fakeGCSObject{created: time.Now()}
+
+	snapshots, err := storage.List(ctx, "")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(snapshots) != 3 {
+		t.Fatalf("Expected 3 snapshots across pages, got %d", len(snapshots))
+	}
+
+	snapshots, err = storage.List(ctx, "default/daily/")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].Path != "gs://backups/etcd/default/daily/etcd-snapshot.db" {
+		t.Fatalf("Unexpected snapshots for prefix: %+v", snapshots)
+	}
+
+	if err := storage.Delete(ctx, snapshots[0].Path); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := storage.GetMetadata(ctx, snapshots[0].Path); err == nil {
+		t.Error("Expected error for deleted snapshot")
+	}
+}
+
+func TestGCSStorage_ApplicationDefaultCredentials(t *testing.T) {
+	server := newFakeGCSServer(t)
+
+	// Workload Identity is resolved through Application Default Credentials;
+	// point them at a key file served by the fake token endpoint
+	keyFile := filepath.Join(t.TempDir(), "adc.json")
+	if err := os.WriteFile(keyFile, []byte(serviceAccountKey(t, server)), 0o600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", keyFile)
+
+	storage := newTestGCSStorage(t, server, nil, "")
+	if _, err := storage.List(context.Background(), ""); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if !strings.HasPrefix(server.authorization[0], "Bearer ") {
+		t.Errorf("Expected authenticated request, got %q", server.authorization[0])
+	}
+}
+
+func TestGCSStorage_MissingServiceAccountKey(t *testing.T) {
+	server := newFakeGCSServer(t)
+	k8sClient := newFakeClient(credentialsSecret("gcs-credentials", map[string]string{"other": "value"}))
+	storage := newTestGCSStorage(t, server, k8sClient, "gcs-credentials")
+
+	if _, err := storage.List(context.Background(), ""); err == nil {
+		t.Error("Expected error for secret without service account key")
+	}
+}
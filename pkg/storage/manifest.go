@@ -0,0 +1,189 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+)
+
+// ManifestSuffix is appended to the snapshot file name to name its manifest
+const ManifestSuffix = ".manifest.json"
+
+// maxManifestSize bounds the manifests read into memory
+const maxManifestSize = 1 << 20
+
+// Manifest describes a snapshot and the backup it belongs to. It is stored
+// next to the snapshot in every storage destination.
+type Manifest struct {
+	BackupName        string    `json:"backupName"`
+	BackupNamespace   string    `json:"backupNamespace"`
+	BackupMode        string    `json:"backupMode"`
+	EtcdRevision      int64     `json:"etcdRevision"`
+	Snapshot          string    `json:"snapshot"`
+	Size              int64     `json:"size"`
+	SHA256            string    `json:"sha256"`
+	CreationTimestamp time.Time `json:"creationTimestamp"`
+}
+
+// NewManifest creates the manifest of a local snapshot file
+func NewManifest(backup *etcdguardianv1alpha1.EtcdBackup, snapshotPath string) (*Manifest, error) {
+	file, err := os.Open(snapshotPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer file.Close()
+
+	digest := sha256.New()
+	size, err := io.Copy(digest, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash snapshot: %w", err)
+	}
+
+	created := time.Now().UTC()
+	if backup.Status.StartTime != nil {
+		created = backup.Status.StartTime.UTC()
+	}
+
+	return &Manifest{
+		BackupName:        backup.Name,
+		BackupNamespace:   backup.Namespace,
+		BackupMode:        string(backup.Spec.BackupMode),
+		EtcdRevision:      backup.Status.EtcdRevision,
+		Snapshot:          filepath.Base(snapshotPath),
+		Size:              size,
+		SHA256:            hex.EncodeToString(digest.Sum(nil)),
+		CreationTimestamp: created,
+	}, nil
+}
+
+// ManifestPath returns the path of the manifest of a snapshot
+func ManifestPath(snapshotPath string) string {
+	return snapshotPath + ManifestSuffix
+}
+
+// WriteFile writes the manifest next to the local snapshot it describes and
+// returns its path
+func (m *Manifest) WriteFile(snapshotPath string) (string, error) {
+	data, err := m.encode()
+	if err != nil {
+		return "", err
+	}
+	manifestPath := ManifestPath(snapshotPath)
+	if err := writeFileAtomic(manifestPath, bytes.NewReader(data)); err != nil {
+		return "", err
+	}
+	return manifestPath, nil
+}
+
+// encode returns the stored form of the manifest
+func (m *Manifest) encode() ([]byte, error) {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	return data, nil
+}
+
+// ReadManifest reads a manifest file
+func ReadManifest(manifestPath string) (*Manifest, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	return decodeManifest(data, manifestPath)
+}
+
+// decodeManifest parses the stored form of a manifest
+func decodeManifest(data []byte, name string) (*Manifest, error) {
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", name, err)
+	}
+	return manifest, nil
+}
+
+// PutManifest stores the manifest next to the stored snapshot it describes
+// and returns its location. The manifest is streamed to backends that
+// implement ObjectStore; other backends upload it from a file written next
+// to the local snapshot.
+func PutManifest(ctx context.Context, backend Storage, snapshotLocation, snapshotPath string, manifest *Manifest, backup *etcdguardianv1alpha1.EtcdBackup) (string, error) {
+	store, ok := backend.(ObjectStore)
+	if !ok {
+		manifestPath, err := manifest.WriteFile(snapshotPath)
+		if err != nil {
+			return "", err
+		}
+		defer os.Remove(manifestPath)
+		return backend.Upload(ctx, manifestPath, backup)
+	}
+
+	key, err := store.ObjectKey(snapshotLocation)
+	if err != nil {
+		return "", err
+	}
+	data, err := manifest.encode()
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(data)
+	object, err := store.PutObject(ctx, key+ManifestSuffix, bytes.NewReader(data), PutOptions{
+		Size:     int64(len(data)),
+		SHA256:   hex.EncodeToString(digest[:]),
+		Metadata: snapshotObjectMetadata(backup),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload manifest of %s: %w", snapshotLocation, err)
+	}
+	return object.Location, nil
+}
+
+// GetManifest reads the manifest at a remote path without a temporary
+// file. The backend must implement ObjectStore.
+func GetManifest(ctx context.Context, backend Storage, manifestLocation string) (*Manifest, error) {
+	store, ok := backend.(ObjectStore)
+	if !ok {
+		return nil, fmt.Errorf("storage backend does not support streaming")
+	}
+	key, err := store.ObjectKey(manifestLocation)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := &writerAtBuffer{limit: maxManifestSize}
+	if _, err := store.GetObject(ctx, key, buffer); err != nil {
+		return nil, fmt.Errorf("failed to download manifest %s: %w", manifestLocation, err)
+	}
+	return decodeManifest(buffer.Bytes(), manifestLocation)
+}
+
+// isManifestKey reports whether an object key names a manifest rather than
+// a snapshot
+func isManifestKey(key string) bool {
+	return strings.HasSuffix(key, ManifestSuffix)
+}
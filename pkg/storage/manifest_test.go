@@ -0,0 +1,134 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"testing"
+)
+
+func TestManifest_WriteAndRead(t *testing.T) {
+	snapshotPath, data := writeSnapshot(t, 4096)
+
+	manifest, err := NewManifest(testBackup("nightly"), snapshotPath)
+	if err != nil {
+		t.Fatalf("NewManifest failed: %v", err)
+	}
+	digest := sha256.Sum256(data)
+	if manifest.SHA256 != hex.EncodeToString(digest[:]) || manifest.Size != int64(len(data)) {
+		t.Errorf("Unexpected manifest %+v", manifest)
+	}
+	if manifest.EtcdRevision != 42 || manifest.Snapshot != "etcd-snapshot.db" {
+		t.Errorf("Unexpected manifest %+v", manifest)
+	}
+
+	manifestPath, err := manifest.WriteFile(snapshotPath)
+	if err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	read, err := ReadManifest(manifestPath)
+	if err != nil {
+		t.Fatalf("ReadManifest failed: %v", err)
+	}
+	if *read != *manifest {
+		t.Errorf("Expected %+v, got %+v", manifest, read)
+	}
+}
+
+func TestManifest_NotListedAsSnapshot(t *testing.T) {
+	storage, _ := newTestFilesystemStorage(t)
+	snapshotPath, _ := writeSnapshot(t, 1024)
+	ctx := context.Background()
+
+	manifest, err := NewManifest(testBackup("nightly"), snapshotPath)
+	if err != nil {
+		t.Fatalf("NewManifest failed: %v", err)
+	}
+	manifestPath, err := manifest.WriteFile(snapshotPath)
+	if err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	for _, localPath := range []string{snapshotPath, manifestPath} {
+		if _, err := storage.Upload(ctx, localPath, testBackup("nightly")); err != nil {
+			t.Fatalf("Upload of %s failed: %v", localPath, err)
+		}
+	}
+
+	snapshots, err := storage.List(ctx, "")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].Name != "etcd-snapshot.db" {
+		t.Errorf("Expected only the snapshot to be listed, got %+v", snapshots)
+	}
+}
+
+// pathStorage hides the streaming interface of a backend
+type pathStorage struct {
+	Storage
+}
+
+func TestPutManifest_StreamsNextToSnapshot(t *testing.T) {
+	filesystem, _ := newTestFilesystemStorage(t)
+	snapshotPath, _ := writeSnapshot(t, 1024)
+	ctx := context.Background()
+	backup := testBackup("nightly")
+
+	manifest, err := NewManifest(backup, snapshotPath)
+	if err != nil {
+		t.Fatalf("NewManifest failed: %v", err)
+	}
+	snapshotLocation, err := filesystem.Upload(ctx, snapshotPath, backup)
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+
+	for name, backend := range map[string]Storage{"streaming": filesystem, "path": pathStorage{filesystem}} {
+		t.Run(name, func(t *testing.T) {
+			manifestLocation, err := PutManifest(ctx, backend, snapshotLocation, snapshotPath, manifest, backup)
+			if err != nil {
+				t.Fatalf("PutManifest failed: %v", err)
+			}
+			if manifestLocation != ManifestPath(snapshotLocation) {
+				t.Errorf("Expected the manifest at %s, got %s", ManifestPath(snapshotLocation), manifestLocation)
+			}
+			if _, err := os.Stat(ManifestPath(snapshotPath)); !os.IsNotExist(err) {
+				t.Errorf("Expected no manifest file to be left next to the snapshot, got %v", err)
+			}
+
+			read, err := GetManifest(ctx, filesystem, manifestLocation)
+			if err != nil {
+				t.Fatalf("GetManifest failed: %v", err)
+			}
+			if *read != *manifest {
+				t.Errorf("Expected %+v, got %+v", manifest, read)
+			}
+		})
+	}
+}
+
+func TestGetManifest_NotFound(t *testing.T) {
+	filesystem, _ := newTestFilesystemStorage(t)
+
+	_, err := GetManifest(context.Background(), filesystem, "etcd/default/nightly/missing.db"+ManifestSuffix)
+	if Classify(err) != ErrorClassNotFound {
+		t.Errorf("Expected a missing manifest to be classified as not found, got %v", err)
+	}
+}
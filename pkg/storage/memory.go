@@ -0,0 +1,258 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+)
+
+// ProviderMemory selects the Memory backend. It is not part of the API:
+// NewStorage builds it only once a test has called EnableMemoryStorage, so
+// that no production location can store snapshots that vanish on restart.
+const ProviderMemory etcdguardianv1alpha1.StorageProvider = "Memory"
+
+// memoryEnabled reports whether NewStorage builds the Memory backend
+var memoryEnabled atomic.Bool
+
+// EnableMemoryStorage lets NewStorage build the Memory backend. Only tests
+// call it, typically from TestMain.
+func EnableMemoryStorage() {
+	memoryEnabled.Store(true)
+}
+
+// memoryObject is an object stored in a memory bucket
+type memoryObject struct {
+	data     []byte
+	created  time.Time
+	metadata map[string]string
+}
+
+// memoryBucket holds the objects and the injected faults of a bucket of
+// the Memory provider
+type memoryBucket struct {
+	mu      sync.Mutex
+	objects map[string]*memoryObject
+	faults  *FaultSet
+}
+
+// memoryBuckets are the buckets of the Memory provider by name. They live
+// as long as the process, so that every backend created for a bucket sees
+// the same objects.
+var memoryBuckets = struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+}{buckets: map[string]*memoryBucket{}}
+
+// getMemoryBucket returns the named bucket, creating it on first use
+func getMemoryBucket(name string) *memoryBucket {
+	memoryBuckets.mu.Lock()
+	defer memoryBuckets.mu.Unlock()
+
+	bucket, ok := memoryBuckets.buckets[name]
+	if !ok {
+		bucket = &memoryBucket{objects: map[string]*memoryObject{}, faults: &FaultSet{}}
+		memoryBuckets.buckets[name] = bucket
+	}
+	return bucket
+}
+
+// MemoryFaults returns the faults injected into every backend of a bucket of
+// the Memory provider, including backends created before a fault is added
+func MemoryFaults(bucket string) *FaultSet {
+	return getMemoryBucket(bucket).faults
+}
+
+// ResetMemoryStorage discards the objects and faults of every bucket of the
+// Memory provider. Tests call it to start from empty buckets.
+func ResetMemoryStorage() {
+	memoryBuckets.mu.Lock()
+	defer memoryBuckets.mu.Unlock()
+	memoryBuckets.buckets = map[string]*memoryBucket{}
+}
+
+// MemoryStorage keeps snapshots in memory, addressed as
+// "memory://bucket/key". It is meant for tests of controllers built on
+// storage backends: backends of the same bucket share their objects within
+// the process, so a backup uploaded by one backend can be listed, verified
+// and restored through another, without any cloud account.
+type MemoryStorage struct {
+	name   string
+	prefix string
+	bucket *memoryBucket
+}
+
+// NewMemoryStorage creates a new memory storage backend
+func NewMemoryStorage(location etcdguardianv1alpha1.StorageLocation) (*MemoryStorage, error) {
+	if location.Bucket == "" {
+		return nil, fmt.Errorf("memory storage needs a bucket")
+	}
+	return &MemoryStorage{
+		name:   location.Bucket,
+		prefix: location.Prefix,
+		bucket: getMemoryBucket(location.Bucket),
+	}, nil
+}
+
+// newMemoryBackend creates the backend NewStorage returns for the Memory
+// provider, which injects the faults of its bucket
+func newMemoryBackend(location etcdguardianv1alpha1.StorageLocation) (Storage, error) {
+	memory, err := NewMemoryStorage(location)
+	if err != nil {
+		return nil, err
+	}
+	return NewFaultInjectingStorage(memory, memory.bucket.faults), nil
+}
+
+// Upload stores a snapshot in the bucket
+func (m *MemoryStorage) Upload(ctx context.Context, localPath string, backup *etcdguardianv1alpha1.EtcdBackup) (string, error) {
+	return putFile(ctx, m, m.prefix, localPath, backup)
+}
+
+// Download writes a snapshot of the bucket to a local file
+func (m *MemoryStorage) Download(ctx context.Context, remotePath, localPath string) error {
+	return getFile(ctx, m, remotePath, localPath)
+}
+
+// List lists the snapshots in the bucket
+func (m *MemoryStorage) List(ctx context.Context, prefix string) ([]SnapshotMetadata, error) {
+	return listSnapshots(ctx, m, m.prefix, prefix)
+}
+
+// Delete deletes a snapshot and its metadata
+func (m *MemoryStorage) Delete(ctx context.Context, remotePath string) error {
+	return deleteObject(ctx, m, remotePath)
+}
+
+// GetMetadata gets snapshot metadata
+func (m *MemoryStorage) GetMetadata(ctx context.Context, remotePath string) (*SnapshotMetadata, error) {
+	return statSnapshot(ctx, m, remotePath)
+}
+
+// PutObject stores an object once its content is complete and verified
+func (m *MemoryStorage) PutObject(ctx context.Context, key string, r io.Reader, opts PutOptions) (*ObjectInfo, error) {
+	data, err := io.ReadAll(newStreamReader(ctx, r, opts))
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload to %s: %w", m.remotePath(key), err)
+	}
+
+	object := &memoryObject{data: data, created: time.Now(), metadata: opts.objectMetadata()}
+	m.bucket.mu.Lock()
+	m.bucket.objects[key] = object
+	m.bucket.mu.Unlock()
+	return m.objectInfo(key, object), nil
+}
+
+// GetObject writes the content of an object to w
+func (m *MemoryStorage) GetObject(ctx context.Context, key string, w io.WriterAt) (int64, error) {
+	object, err := m.object(key)
+	if err != nil {
+		return 0, err
+	}
+	return io.Copy(io.NewOffsetWriter(w, 0), newStreamReader(ctx, bytes.NewReader(object.data), PutOptions{}))
+}
+
+// StatObject returns the attributes and user metadata of an object
+func (m *MemoryStorage) StatObject(ctx context.Context, key string) (*ObjectInfo, error) {
+	object, err := m.object(key)
+	if err != nil {
+		return nil, err
+	}
+	return m.objectInfo(key, object), nil
+}
+
+// ListObjects lists the objects whose key starts with prefix, sorted by key
+func (m *MemoryStorage) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	m.bucket.mu.Lock()
+	defer m.bucket.mu.Unlock()
+
+	objects := []ObjectInfo{}
+	for key, object := range m.bucket.objects {
+		if strings.HasPrefix(key, prefix) {
+			objects = append(objects, *m.objectInfo(key, object))
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return objects, nil
+}
+
+// DeleteObject deletes an object
+func (m *MemoryStorage) DeleteObject(ctx context.Context, key string) error {
+	m.bucket.mu.Lock()
+	defer m.bucket.mu.Unlock()
+
+	if _, ok := m.bucket.objects[key]; !ok {
+		return fmt.Errorf("%s: %w", m.remotePath(key), ErrObjectNotFound)
+	}
+	delete(m.bucket.objects, key)
+	return nil
+}
+
+// ObjectKey returns the key of the object a remote path addresses, which
+// must be in the bucket of the location
+func (m *MemoryStorage) ObjectKey(remotePath string) (string, error) {
+	bucket, key, err := splitRemotePath(remotePath, "memory", m.name)
+	if err != nil {
+		return "", err
+	}
+	if bucket != m.name {
+		return "", fmt.Errorf("remote path %q is not in bucket %s", remotePath, m.name)
+	}
+	return key, nil
+}
+
+// object returns a stored object
+func (m *MemoryStorage) object(key string) (*memoryObject, error) {
+	m.bucket.mu.Lock()
+	defer m.bucket.mu.Unlock()
+
+	object, ok := m.bucket.objects[key]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", m.remotePath(key), ErrObjectNotFound)
+	}
+	return object, nil
+}
+
+// objectInfo builds the attributes of an object. The metadata is copied so
+// that callers cannot change the stored object.
+func (m *MemoryStorage) objectInfo(key string, object *memoryObject) *ObjectInfo {
+	metadata := map[string]string{}
+	for name, value := range object.metadata {
+		metadata[name] = value
+	}
+	return &ObjectInfo{
+		Key:      key,
+		Location: m.remotePath(key),
+		Size:     int64(len(object.data)),
+		Created:  object.created,
+		Metadata: metadata,
+	}
+}
+
+// remotePath returns the remote path of an object key
+func (m *MemoryStorage) remotePath(key string) string {
+	return fmt.Sprintf("memory://%s/%s", m.name, key)
+}
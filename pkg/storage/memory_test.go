@@ -0,0 +1,186 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+)
+
+func TestMain(m *testing.M) {
+	EnableMemoryStorage()
+	os.Exit(m.Run())
+}
+
+// newTestMemoryStorage returns the backend NewStorage creates for a Memory
+// bucket of its own
+func newTestMemoryStorage(t *testing.T) *FaultInjectingStorage {
+	t.Helper()
+
+	bucket := strings.ReplaceAll(t.Name(), "/", "-")
+	t.Cleanup(func() {
+		memoryBuckets.mu.Lock()
+		delete(memoryBuckets.buckets, bucket)
+		memoryBuckets.mu.Unlock()
+	})
+	storage, err := NewStorage(ProviderMemory, etcdguardianv1alpha1.StorageLocation{
+		Provider: ProviderMemory,
+		Bucket:   bucket,
+		Prefix:   "etcd",
+	}, nil, "default")
+	if err != nil {
+		t.Fatalf("NewStorage failed: %v", err)
+	}
+	return storage.(*FaultInjectingStorage)
+}
+
+func TestMemoryStorage_SharedBucket(t *testing.T) {
+	storage := newTestMemoryStorage(t)
+	ctx := context.Background()
+
+	snapshotPath, data := writeSnapshot(t, 1024)
+	location, err := storage.Upload(ctx, snapshotPath, testBackup("nightly"))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if !strings.HasPrefix(location, "memory://") {
+		t.Errorf("Unexpected location %q", location)
+	}
+
+	// Another backend of the bucket sees the snapshot
+	other, err := NewMemoryStorage(etcdguardianv1alpha1.StorageLocation{Bucket: strings.ReplaceAll(t.Name(), "/", "-"), Prefix: "etcd"})
+	if err != nil {
+		t.Fatalf("NewMemoryStorage failed: %v", err)
+	}
+	snapshots, err := other.List(ctx, "")
+	if err != nil || len(snapshots) != 1 || snapshots[0].EtcdRevision != 42 {
+		t.Fatalf("Expected the snapshot in the listing, got %+v, %v", snapshots, err)
+	}
+	downloaded := filepath.Join(t.TempDir(), "restore.db")
+	if err := other.Download(ctx, location, downloaded); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if got, _ := os.ReadFile(downloaded); !bytes.Equal(got, data) {
+		t.Errorf("Downloaded snapshot does not match")
+	}
+
+	if _, err := NewMemoryStorage(etcdguardianv1alpha1.StorageLocation{}); err == nil {
+		t.Errorf("Expected error without a bucket")
+	}
+}
+
+func TestFaultInjectingStorage_Errors(t *testing.T) {
+	storage := newTestMemoryStorage(t)
+	faults := MemoryFaults(strings.ReplaceAll(t.Name(), "/", "-"))
+	ctx := context.Background()
+	snapshotPath, _ := writeSnapshot(t, 1024)
+
+	// A transient error on the first two uploads only
+	faults.Add(Fault{Operations: []FaultOperation{FaultPut}, Err: &InjectedError{Class: ErrorClassRetryable}, Times: 2})
+	for i := 0; i < 2; i++ {
+		if _, err := storage.Upload(ctx, snapshotPath, testBackup("nightly")); Classify(err) != ErrorClassRetryable {
+			t.Fatalf("Expected a retryable error, got %v", err)
+		}
+	}
+	location, err := storage.Upload(ctx, snapshotPath, testBackup("nightly"))
+	if err != nil {
+		t.Fatalf("Expected the fault to be spent: %v", err)
+	}
+	if faults.Fired() != 2 {
+		t.Errorf("Expected the fault to fire twice, got %d", faults.Fired())
+	}
+
+	// Faults restricted to a key leave other objects alone
+	faults.Add(Fault{Key: "other", Err: errors.New("boom")})
+	if _, err := storage.GetMetadata(ctx, location); err != nil {
+		t.Errorf("Expected the fault not to match: %v", err)
+	}
+	faults.Clear()
+	faults.Add(Fault{Key: "nightly", Operations: []FaultOperation{FaultDelete}, Err: &InjectedError{Class: ErrorClassAuth}})
+	if err := storage.Delete(ctx, location); Classify(err) != ErrorClassAuth {
+		t.Errorf("Expected an auth error, got %v", err)
+	}
+
+	// Latency gives way to the context
+	faults.Clear()
+	faults.Add(Fault{Operations: []FaultOperation{FaultList}, Latency: time.Hour})
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := storage.List(timeoutCtx, ""); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the latency to exceed the deadline, got %v", err)
+	}
+}
+
+func TestFaultInjectingStorage_PartialWriteAndCorruption(t *testing.T) {
+	storage := newTestMemoryStorage(t)
+	faults := MemoryFaults(strings.ReplaceAll(t.Name(), "/", "-"))
+	ctx := context.Background()
+	snapshotPath, data := writeSnapshot(t, 1024)
+
+	// A torn upload is stored truncated, and the caller sees the error of
+	// the dropped connection
+	faults.Add(Fault{Operations: []FaultOperation{FaultPut}, PartialWrite: 100, Err: &InjectedError{Class: ErrorClassRetryable}, Times: 1})
+	if _, err := storage.Upload(ctx, snapshotPath, testBackup("nightly")); err == nil {
+		t.Fatalf("Expected the torn upload to fail")
+	}
+	object, err := storage.StatObject(ctx, "etcd/default/nightly/etcd-snapshot.db")
+	if err != nil || object.Size != 100 {
+		t.Fatalf("Expected a truncated object, got %+v, %v", object, err)
+	}
+
+	location, err := storage.Upload(ctx, snapshotPath, testBackup("nightly"))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+
+	// Corruption on download
+	faults.Add(Fault{Operations: []FaultOperation{FaultGet}, Corrupt: true, Times: 1})
+	downloaded := filepath.Join(t.TempDir(), "restore.db")
+	if err := storage.Download(ctx, location, downloaded); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if got, _ := os.ReadFile(downloaded); len(got) != len(data) || bytes.Equal(got, data) {
+		t.Errorf("Expected a corrupted download of the same size")
+	}
+
+	// Corruption of a streamed put is caught by its checksum
+	faults.Add(Fault{Operations: []FaultOperation{FaultPut}, Corrupt: true})
+	digest := sha256.Sum256(data)
+	if _, err := storage.PutObject(ctx, "etcd/manifest.json", bytes.NewReader(data), PutOptions{SHA256: hex.EncodeToString(digest[:])}); err == nil {
+		t.Errorf("Expected the checksum to reject the corrupted content")
+	}
+}
+
+func TestNewStorage_MemoryNeedsTests(t *testing.T) {
+	memoryEnabled.Store(false)
+	defer memoryEnabled.Store(true)
+
+	_, err := NewStorage(ProviderMemory, etcdguardianv1alpha1.StorageLocation{Provider: ProviderMemory, Bucket: "production"}, nil, "default")
+	if err == nil || !strings.Contains(err.Error(), "unsupported storage provider") {
+		t.Errorf("Expected the Memory provider to be unsupported outside tests, got %v", err)
+	}
+}
@@ -0,0 +1,312 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrObjectNotFound is returned, wrapped, for operations on objects that do
+// not exist
+var ErrObjectNotFound = errors.New("object not found")
+
+// metadataSHA256 is the object metadata key of the content checksum
+const metadataSHA256 = "sha256"
+
+// ObjectStore is the streaming interface of the storage backends. Objects
+// are addressed by their key in the bucket of the storage location, and
+// their content is streamed without temporary files. Cancelling the context
+// stops a transfer.
+type ObjectStore interface {
+	// PutObject stores the content read from r under key. The object only
+	// becomes visible once the content is complete and matches the size and
+	// checksum of the options.
+	PutObject(ctx context.Context, key string, r io.Reader, opts PutOptions) (*ObjectInfo, error)
+
+	// GetObject writes the content of an object to w and returns its size
+	GetObject(ctx context.Context, key string, w io.WriterAt) (int64, error)
+
+	// StatObject returns the attributes and user metadata of an object
+	StatObject(ctx context.Context, key string) (*ObjectInfo, error)
+
+	// ListObjects lists the objects whose key starts with prefix
+	ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error)
+
+	// DeleteObject deletes an object
+	DeleteObject(ctx context.Context, key string) error
+
+	// ObjectKey returns the key of the object a remote path such as
+	// "oss://bucket/key" addresses. Paths without a scheme are keys.
+	ObjectKey(remotePath string) (string, error)
+}
+
+// PutOptions describes the content of an object being stored
+type PutOptions struct {
+	// Size of the content in bytes, or zero when unknown. Backends use it
+	// to choose between a single request and a multipart upload.
+	Size int64
+
+	// SHA256 is the expected hex digest of the content. It is stored with
+	// the object.
+	SHA256 string
+
+	// Metadata is stored as user metadata of the object
+	Metadata map[string]string
+}
+
+// objectMetadata returns the user metadata to store with the object
+func (o PutOptions) objectMetadata() map[string]string {
+	metadata := map[string]string{}
+	for name, value := range o.Metadata {
+		metadata[name] = value
+	}
+	if o.SHA256 != "" {
+		metadata[metadataSHA256] = o.SHA256
+	}
+	return metadata
+}
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	// Key of the object in the bucket
+	Key string
+
+	// Location is the remote path of the object
+	Location string
+
+	Size    int64
+	Created time.Time
+
+	// Metadata is the user metadata of the object. Listings leave it empty
+	// for backends that do not return it.
+	Metadata map[string]string
+}
+
+// NewObjectStore creates the streaming interface of a storage backend
+func NewObjectStore(provider etcdguardianv1alpha1.StorageProvider, location etcdguardianv1alpha1.StorageLocation, k8sClient client.Client, namespace string) (ObjectStore, error) {
+	backend, err := NewStorage(provider, location, k8sClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+	store, ok := backend.(ObjectStore)
+	if !ok {
+		return nil, fmt.Errorf("storage provider %s does not support streaming", provider)
+	}
+	return store, nil
+}
+
+// streamReader wraps content being transferred. It stops the transfer when
+// the context is cancelled, and fails the final read when the content does
+// not match the put options, so that backends never complete the object.
+type streamReader struct {
+	ctx    context.Context
+	r      io.Reader
+	opts   PutOptions
+	digest hash.Hash
+	n      int64
+	err    error
+}
+
+func newStreamReader(ctx context.Context, r io.Reader, opts PutOptions) *streamReader {
+	return &streamReader{ctx: ctx, r: r, opts: opts, digest: sha256.New()}
+}
+
+func (s *streamReader) Read(b []byte) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	if err := s.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := s.r.Read(b)
+	s.n += int64(n)
+	s.digest.Write(b[:n])
+	if err == io.EOF {
+		// Keep failing, callers such as io.ReadFull may drop one error
+		if s.err = s.verify(); s.err != nil {
+			return n, s.err
+		}
+	}
+	return n, err
+}
+
+// verify checks the complete content against the put options
+func (s *streamReader) verify() error {
+	if s.opts.Size > 0 && s.n != s.opts.Size {
+		return fmt.Errorf("content size %d does not match the expected %d", s.n, s.opts.Size)
+	}
+	if s.opts.SHA256 != "" && !strings.EqualFold(hex.EncodeToString(s.digest.Sum(nil)), s.opts.SHA256) {
+		return fmt.Errorf("content checksum %s does not match the expected %s", hex.EncodeToString(s.digest.Sum(nil)), s.opts.SHA256)
+	}
+	return nil
+}
+
+// writerAtBuffer collects the content of a small object in memory. Writes
+// past the limit fail, so that an unexpectedly large object is not read.
+type writerAtBuffer struct {
+	mu    sync.Mutex
+	data  []byte
+	limit int64
+}
+
+func (b *writerAtBuffer) WriteAt(p []byte, off int64) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	end := off + int64(len(p))
+	if off < 0 || end > b.limit {
+		return 0, fmt.Errorf("object exceeds %d bytes", b.limit)
+	}
+	if end > int64(len(b.data)) {
+		b.data = append(b.data, make([]byte, end-int64(len(b.data)))...)
+	}
+	return copy(b.data[off:], p), nil
+}
+
+// Bytes returns the content written so far
+func (b *writerAtBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.data
+}
+
+// The functions below implement Storage on top of an ObjectStore for the
+// backends that have no file specific transfer of their own.
+
+// putFile uploads a local snapshot to the key of its backup
+func putFile(ctx context.Context, store ObjectStore, locationPrefix, localPath string, backup *etcdguardianv1alpha1.EtcdBackup) (string, error) {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to stat snapshot: %w", err)
+	}
+
+	object, err := store.PutObject(ctx, snapshotKey(locationPrefix, backup, localPath), file, PutOptions{
+		Size:     info.Size(),
+		Metadata: snapshotObjectMetadata(backup),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload %s: %w", localPath, err)
+	}
+	return object.Location, nil
+}
+
+// getFile downloads an object to a local file, which only appears once
+// the download is complete
+func getFile(ctx context.Context, store ObjectStore, remotePath, localPath string) error {
+	key, err := store.ObjectKey(remotePath)
+	if err != nil {
+		return err
+	}
+
+	err = writeFileAtomicWith(localPath, func(file *os.File) error {
+		_, err := store.GetObject(ctx, key, file)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", remotePath, err)
+	}
+	return nil
+}
+
+// listSnapshots lists the snapshots below a prefix of the location prefix,
+// leaving out manifests, tombstones and the snapshots they mark, and the
+// internal objects of the operator, such as access check probes
+func listSnapshots(ctx context.Context, store ObjectStore, locationPrefix, prefix string) ([]SnapshotMetadata, error) {
+	objects, err := store.ListObjects(ctx, listPrefix(locationPrefix, prefix))
+	if err != nil {
+		return nil, err
+	}
+	internal := listPrefix(locationPrefix, internalPrefix)
+
+	deleted := map[string]bool{}
+	for _, object := range objects {
+		if isTombstoneKey(object.Key) {
+			deleted[strings.TrimSuffix(object.Key, TombstoneSuffix)] = true
+		}
+	}
+
+	snapshots := []SnapshotMetadata{}
+	for _, object := range objects {
+		if strings.HasSuffix(object.Key, "/") || strings.HasPrefix(object.Key, internal) ||
+			isManifestKey(object.Key) || isTombstoneKey(object.Key) || deleted[object.Key] {
+			continue
+		}
+		snapshots = append(snapshots, object.snapshotMetadata())
+	}
+	return snapshots, nil
+}
+
+// deleteObject deletes the object a remote path addresses
+func deleteObject(ctx context.Context, store ObjectStore, remotePath string) error {
+	key, err := store.ObjectKey(remotePath)
+	if err != nil {
+		return err
+	}
+	return store.DeleteObject(ctx, key)
+}
+
+// statSnapshot returns the snapshot metadata of the object a remote path
+// addresses
+func statSnapshot(ctx context.Context, store ObjectStore, remotePath string) (*SnapshotMetadata, error) {
+	key, err := store.ObjectKey(remotePath)
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := store.StatObject(ctx, key)
+	if err != nil {
+		if errors.Is(err, ErrObjectNotFound) {
+			return nil, fmt.Errorf("snapshot %s not found: %w", remotePath, err)
+		}
+		return nil, fmt.Errorf("failed to get metadata of %s: %w", remotePath, err)
+	}
+
+	metadata := object.snapshotMetadata()
+	return &metadata, nil
+}
+
+// snapshotMetadata converts object attributes to snapshot metadata
+func (o ObjectInfo) snapshotMetadata() SnapshotMetadata {
+	metadata := SnapshotMetadata{
+		Name:              path.Base(o.Key),
+		Path:              o.Location,
+		Size:              o.Size,
+		CreationTimestamp: o.Created.Unix(),
+	}
+	metadata.applyObjectMetadata(o.Metadata)
+	return metadata
+}
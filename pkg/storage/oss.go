@@ -17,19 +17,57 @@ limitations under the License.
 package storage
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
-	"path/filepath"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	// ossPartSize is the part size used for multipart uploads and ranged downloads
+	ossPartSize = 16 * 1024 * 1024
+
+	// ossRoutines is the number of parts transferred concurrently
+	ossRoutines = 4
+
+	// ossCheckpointSuffix is appended to the local file path to store the
+	// checkpoint used to resume interrupted transfers
+	ossCheckpointSuffix = ".oss.cp"
+
+	ossMetaPrefix = "X-Oss-Meta-"
+
+	// ossWormInProgress is the state of a bucket retention policy that is
+	// not locked yet
+	ossWormInProgress = "InProgress"
+
+	// ossRestoreHeader reports the restore of an archived object, such as
+	// ongoing-request="true"
+	ossRestoreHeader = "X-Oss-Restore"
+)
+
 // OSSStorage implements Alibaba Cloud OSS storage
 type OSSStorage struct {
 	location  etcdguardianv1alpha1.StorageLocation
 	client    client.Client
 	namespace string
+
+	partSize int64
+
+	mu     sync.Mutex
+	bucket *oss.Bucket
 }
 
 // NewOSSStorage creates a new OSS storage backend
@@ -38,39 +76,720 @@ func NewOSSStorage(location etcdguardianv1alpha1.StorageLocation, k8sClient clie
 		location:  location,
 		client:    k8sClient,
 		namespace: namespace,
+		partSize:  ossPartSize,
 	}, nil
 }
 
-// Upload uploads a snapshot to OSS
+// Upload uploads a snapshot to OSS using a multipart upload
 func (o *OSSStorage) Upload(ctx context.Context, localPath string, backup *etcdguardianv1alpha1.EtcdBackup) (string, error) {
-	// TODO: Implement actual OSS upload using Alibaba Cloud SDK
-	remotePath := filepath.Join(o.location.Prefix, backup.Namespace, backup.Name, filepath.Base(localPath))
-	fullPath := fmt.Sprintf("oss://%s/%s", o.location.Bucket, remotePath)
+	return o.UploadResumable(ctx, localPath, backup, &etcdguardianv1alpha1.UploadSession{}, nil)
+}
+
+// UploadResumable uploads a snapshot to OSS as a multipart upload tracked in
+// session. Parts already listed in the session are not uploaded again.
+func (o *OSSStorage) UploadResumable(ctx context.Context, localPath string, backup *etcdguardianv1alpha1.EtcdBackup, session *etcdguardianv1alpha1.UploadSession, checkpoint func(*etcdguardianv1alpha1.UploadSession) error) (string, error) {
+	bucket, err := o.getBucket(ctx)
+	if err != nil {
+		return "", err
+	}
+	if checkpoint == nil {
+		checkpoint = func(*etcdguardianv1alpha1.UploadSession) error { return nil }
+	}
+
+	key := snapshotKey(o.location.Prefix, backup, localPath)
+	remotePath := fmt.Sprintf("oss://%s/%s", o.location.Bucket, key)
+
+	chunks, err := oss.SplitFileByPartSize(localPath, o.partSize)
+	if err != nil {
+		return "", fmt.Errorf("failed to split %s into parts: %w", localPath, err)
+	}
+
+	if session.UploadID != "" && (session.RemotePath != remotePath || session.PartSize != o.partSize) {
+		// The session belongs to another snapshot, such as one retaken
+		// after the local file was lost
+		_ = o.AbortUpload(ctx, session.RemotePath, session.UploadID)
+		*session = etcdguardianv1alpha1.UploadSession{Destination: session.Destination}
+	}
+	if session.UploadID == "" {
+		imur, err := bucket.InitiateMultipartUpload(key, o.objectOptions(ctx, snapshotObjectMetadata(backup))...)
+		if err != nil {
+			return "", fmt.Errorf("failed to initiate upload to %s: %w", remotePath, err)
+		}
+		session.RemotePath = remotePath
+		session.UploadID = imur.UploadID
+		session.PartSize = o.partSize
+		session.CompletedParts = nil
+		session.StartTime = &metav1.Time{Time: time.Now()}
+		if err := checkpoint(session); err != nil {
+			return "", fmt.Errorf("failed to save upload session: %w", err)
+		}
+	}
+
+	imur := oss.InitiateMultipartUploadResult{Bucket: o.location.Bucket, Key: key, UploadID: session.UploadID}
+	if err := o.uploadParts(ctx, bucket, imur, localPath, chunks, session, checkpoint); err != nil {
+		var serviceErr oss.ServiceError
+		if errors.As(err, &serviceErr) && serviceErr.Code == "NoSuchUpload" {
+			// The upload was aborted or expired; start over next time
+			*session = etcdguardianv1alpha1.UploadSession{Destination: session.Destination}
+			_ = checkpoint(session)
+		}
+		return "", fmt.Errorf("failed to upload %s to %s: %w", localPath, remotePath, err)
+	}
 
-	// Placeholder: simulate upload
-	return fullPath, nil
+	parts := make([]oss.UploadPart, 0, len(session.CompletedParts))
+	for _, part := range session.CompletedParts {
+		parts = append(parts, oss.UploadPart{PartNumber: int(part.PartNumber), ETag: part.ETag})
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+	if _, err := bucket.CompleteMultipartUpload(imur, parts, oss.WithContext(ctx)); err != nil {
+		return "", fmt.Errorf("failed to complete upload to %s: %w", remotePath, err)
+	}
+
+	return remotePath, nil
+}
+
+// uploadParts concurrently uploads the parts missing from session, recording
+// each part in the session as it completes
+func (o *OSSStorage) uploadParts(ctx context.Context, bucket *oss.Bucket, imur oss.InitiateMultipartUploadResult, localPath string, chunks []oss.FileChunk, session *etcdguardianv1alpha1.UploadSession, checkpoint func(*etcdguardianv1alpha1.UploadSession) error) error {
+	completed := map[int]bool{}
+	for _, part := range session.CompletedParts {
+		completed[int(part.PartNumber)] = true
+	}
+
+	pending := make(chan oss.FileChunk, len(chunks))
+	for _, chunk := range chunks {
+		if !completed[chunk.Number] {
+			pending <- chunk
+		}
+	}
+	close(pending)
+
+	var mu sync.Mutex
+	var firstErr error
+	var wg sync.WaitGroup
+	for i := 0; i < ossRoutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range pending {
+				part, err := bucket.UploadPartFromFile(imur, localPath, chunk.Offset, chunk.Size, chunk.Number, oss.WithContext(ctx))
+
+				mu.Lock()
+				if err == nil {
+					session.CompletedParts = append(session.CompletedParts, etcdguardianv1alpha1.CompletedPart{
+						PartNumber: int32(part.PartNumber),
+						ETag:       part.ETag,
+					})
+					if err = checkpoint(session); err != nil {
+						err = fmt.Errorf("failed to save upload session: %w", err)
+					}
+				}
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				failed := firstErr != nil
+				mu.Unlock()
+
+				if failed {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
 }
 
-// Download downloads a snapshot from OSS
+// ListUploads lists the multipart uploads in progress under the location prefix
+func (o *OSSStorage) ListUploads(ctx context.Context) ([]PendingUpload, error) {
+	bucket, err := o.getBucket(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	uploads := []PendingUpload{}
+	keyMarker, uploadIDMarker := "", ""
+	for {
+		result, err := bucket.ListMultipartUploads(
+			oss.WithContext(ctx),
+			oss.Prefix(listPrefix(o.location.Prefix, "")),
+			oss.KeyMarker(keyMarker),
+			oss.UploadIDMarker(uploadIDMarker),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list uploads in oss://%s: %w", o.location.Bucket, err)
+		}
+
+		for _, upload := range result.Uploads {
+			uploads = append(uploads, PendingUpload{
+				RemotePath: fmt.Sprintf("oss://%s/%s", o.location.Bucket, upload.Key),
+				UploadID:   upload.UploadID,
+				Initiated:  upload.Initiated,
+			})
+		}
+
+		if !result.IsTruncated {
+			return uploads, nil
+		}
+		keyMarker, uploadIDMarker = result.NextKeyMarker, result.NextUploadIDMarker
+	}
+}
+
+// AbortUpload aborts a multipart upload. Uploads that no longer exist are
+// not an error.
+func (o *OSSStorage) AbortUpload(ctx context.Context, remotePath, uploadID string) error {
+	bucket, key, err := o.resolve(ctx, remotePath)
+	if err != nil {
+		return err
+	}
+
+	imur := oss.InitiateMultipartUploadResult{Bucket: bucket.BucketName, Key: key, UploadID: uploadID}
+	if err := bucket.AbortMultipartUpload(imur, oss.WithContext(ctx)); err != nil {
+		var serviceErr oss.ServiceError
+		if errors.As(err, &serviceErr) && serviceErr.Code == "NoSuchUpload" {
+			return nil
+		}
+		return fmt.Errorf("failed to abort upload %s of %s: %w", uploadID, remotePath, err)
+	}
+	return nil
+}
+
+// Download downloads a snapshot from OSS using concurrent ranged requests
 func (o *OSSStorage) Download(ctx context.Context, remotePath, localPath string) error {
-	// TODO: Implement actual OSS download
-	return fmt.Errorf("download not yet implemented")
+	bucket, key, err := o.resolve(ctx, remotePath)
+	if err != nil {
+		return err
+	}
+
+	err = bucket.DownloadFile(key, localPath, o.partSize,
+		oss.WithContext(ctx),
+		oss.Routines(ossRoutines),
+		oss.Checkpoint(true, localPath+ossCheckpointSuffix),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", remotePath, err)
+	}
+	return nil
 }
 
 // List lists snapshots in OSS
 func (o *OSSStorage) List(ctx context.Context, prefix string) ([]SnapshotMetadata, error) {
-	// TODO: Implement actual OSS list
-	return []SnapshotMetadata{}, nil
+	return listSnapshots(ctx, o, o.location.Prefix, prefix)
 }
 
 // Delete deletes a snapshot from OSS
 func (o *OSSStorage) Delete(ctx context.Context, remotePath string) error {
-	// TODO: Implement actual OSS delete
-	return nil
+	return deleteObject(ctx, o, remotePath)
 }
 
 // GetMetadata gets snapshot metadata from OSS
 func (o *OSSStorage) GetMetadata(ctx context.Context, remotePath string) (*SnapshotMetadata, error) {
-	// TODO: Implement actual OSS metadata retrieval
-	return &SnapshotMetadata{}, nil
+	return statSnapshot(ctx, o, remotePath)
+}
+
+// PutObject streams an object to OSS. Content known to fit in one part is
+// sent in a single request, anything else as a multipart upload that is
+// aborted when the content cannot be read completely.
+func (o *OSSStorage) PutObject(ctx context.Context, key string, r io.Reader, opts PutOptions) (*ObjectInfo, error) {
+	bucket, err := o.getBucket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	remotePath := o.remotePath(key)
+
+	options := o.objectOptions(ctx, opts.objectMetadata())
+	content := newStreamReader(ctx, r, opts)
+
+	if opts.Size > 0 && opts.Size <= o.partSize {
+		if err := bucket.PutObject(key, content, options...); err != nil {
+			return nil, fmt.Errorf("failed to upload to %s: %w", remotePath, err)
+		}
+		return o.StatObject(ctx, key)
+	}
+
+	imur, err := bucket.InitiateMultipartUpload(key, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initiate upload to %s: %w", remotePath, err)
+	}
+	parts, err := o.putParts(ctx, bucket, imur, content)
+	if err == nil {
+		_, err = bucket.CompleteMultipartUpload(imur, parts, oss.WithContext(ctx))
+	}
+	if err != nil {
+		// The context may be cancelled already
+		_ = bucket.AbortMultipartUpload(imur)
+		return nil, fmt.Errorf("failed to upload to %s: %w", remotePath, err)
+	}
+
+	return o.StatObject(ctx, key)
+}
+
+// putParts reads content part by part and uploads up to ossRoutines parts
+// concurrently, holding at most that many parts in memory
+func (o *OSSStorage) putParts(ctx context.Context, bucket *oss.Bucket, imur oss.InitiateMultipartUploadResult, content io.Reader) ([]oss.UploadPart, error) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	parts := []oss.UploadPart{}
+	slots := make(chan struct{}, ossRoutines)
+
+	for number := 1; ; number++ {
+		slots <- struct{}{}
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+
+		buffer := make([]byte, o.partSize)
+		n, err := io.ReadFull(content, buffer)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			mu.Lock()
+			firstErr = err
+			mu.Unlock()
+			break
+		}
+		// An empty object still needs one part
+		if n == 0 && number > 1 {
+			break
+		}
+
+		wg.Add(1)
+		go func(number int, data []byte) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			part, err := bucket.UploadPart(imur, bytes.NewReader(data), int64(len(data)), number, oss.WithContext(ctx))
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			parts = append(parts, part)
+		}(number, buffer[:n])
+
+		if err != nil {
+			break
+		}
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+	return parts, nil
+}
+
+// GetObject downloads an object from OSS to w using concurrent ranged requests
+func (o *OSSStorage) GetObject(ctx context.Context, key string, w io.WriterAt) (int64, error) {
+	object, err := o.StatObject(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	bucket, err := o.getBucket(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	slots := make(chan struct{}, ossRoutines)
+	for start := int64(0); start < object.Size; start += o.partSize {
+		end := start + o.partSize - 1
+		if end >= object.Size {
+			end = object.Size - 1
+		}
+
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(start, end int64) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			body, err := bucket.GetObject(key, oss.Range(start, end), oss.WithContext(ctx))
+			if err == nil {
+				_, err = io.Copy(io.NewOffsetWriter(w, start), body)
+				body.Close()
+			}
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to download %s: %w", object.Location, err)
+				}
+				mu.Unlock()
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return 0, firstErr
+	}
+	return object.Size, nil
+}
+
+// StatObject returns the attributes and user metadata of an object in OSS
+func (o *OSSStorage) StatObject(ctx context.Context, key string) (*ObjectInfo, error) {
+	bucket, err := o.getBucket(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := bucket.GetObjectDetailedMeta(key, oss.WithContext(ctx))
+	if err != nil {
+		return nil, o.objectError(key, err)
+	}
+
+	object := &ObjectInfo{
+		Key:      key,
+		Location: o.remotePath(key),
+		Metadata: map[string]string{},
+	}
+	if size, err := strconv.ParseInt(header.Get(oss.HTTPHeaderContentLength), 10, 64); err == nil {
+		object.Size = size
+	}
+	if modified, err := http.ParseTime(header.Get(oss.HTTPHeaderLastModified)); err == nil {
+		object.Created = modified
+	}
+	for name := range header {
+		if strings.HasPrefix(name, ossMetaPrefix) {
+			object.Metadata[strings.ToLower(strings.TrimPrefix(name, ossMetaPrefix))] = header.Get(name)
+		}
+	}
+	return object, nil
+}
+
+// ListObjects lists the objects in OSS whose key starts with prefix
+func (o *OSSStorage) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	bucket, err := o.getBucket(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []ObjectInfo{}
+	token := ""
+	for {
+		result, err := bucket.ListObjectsV2(
+			oss.WithContext(ctx),
+			oss.Prefix(prefix),
+			oss.ContinuationToken(token),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list oss://%s: %w", o.location.Bucket, err)
+		}
+
+		for _, object := range result.Objects {
+			objects = append(objects, ObjectInfo{
+				Key:      object.Key,
+				Location: o.remotePath(object.Key),
+				Size:     object.Size,
+				Created:  object.LastModified,
+			})
+		}
+
+		if !result.IsTruncated {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// DeleteObject deletes an object from OSS
+func (o *OSSStorage) DeleteObject(ctx context.Context, key string) error {
+	bucket, err := o.getBucket(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.DeleteObject(key, oss.WithContext(ctx)); err != nil {
+		return o.objectError(key, err)
+	}
+	return nil
+}
+
+// LockObject checks that the retention policy of the bucket retains an
+// object until retainUntil. OSS retention policies apply to every object of
+// a bucket, for a number of days after its last modification, and only in
+// compliance mode. A locked policy can never be removed or shortened, so it
+// is not created, locked or extended here: a bucket without a locked policy
+// long enough fails with ErrRetentionPolicyInsufficient.
+func (o *OSSStorage) LockObject(ctx context.Context, remotePath string, mode etcdguardianv1alpha1.ImmutabilityMode, retainUntil time.Time) error {
+	if mode != etcdguardianv1alpha1.ImmutabilityModeCompliance {
+		return fmt.Errorf("OSS retention policies only support the %s mode", etcdguardianv1alpha1.ImmutabilityModeCompliance)
+	}
+
+	key, err := o.ObjectKey(remotePath)
+	if err != nil {
+		return err
+	}
+	object, err := o.StatObject(ctx, key)
+	if err != nil {
+		return err
+	}
+	days := int(math.Ceil(retainUntil.Sub(object.Created).Hours() / 24))
+	if days < 1 {
+		days = 1
+	}
+
+	bucket, err := o.getBucket(ctx)
+	if err != nil {
+		return err
+	}
+	worm, err := bucket.Client.GetBucketWorm(o.location.Bucket, oss.WithContext(ctx))
+	var serviceErr oss.ServiceError
+	if errors.As(err, &serviceErr) && serviceErr.Code == "NoSuchWORMConfiguration" {
+		return fmt.Errorf("%w: bucket %s has no retention policy, lock one of at least %d days", ErrRetentionPolicyInsufficient, o.location.Bucket, days)
+	} else if err != nil {
+		return fmt.Errorf("failed to get retention policy of bucket %s: %w", o.location.Bucket, err)
+	}
+
+	if worm.State == ossWormInProgress {
+		return fmt.Errorf("%w: retention policy of bucket %s is not locked", ErrRetentionPolicyInsufficient, o.location.Bucket)
+	}
+	if worm.RetentionPeriodInDays < days {
+		return fmt.Errorf("%w: bucket %s retains objects for %d days, %d are required",
+			ErrRetentionPolicyInsufficient, o.location.Bucket, worm.RetentionPeriodInDays, days)
+	}
+	return nil
+}
+
+// SetLegalHold fails for holds, which OSS does not support. Releasing a hold
+// is a no-op.
+func (o *OSSStorage) SetLegalHold(ctx context.Context, remotePath string, hold bool) error {
+	if hold {
+		return fmt.Errorf("OSS does not support legal holds")
+	}
+	return nil
+}
+
+// VerifyEncryption checks that an object is encrypted with AES256 or the KMS
+// key the server-side encryption settings require
+func (o *OSSStorage) VerifyEncryption(ctx context.Context, remotePath string) error {
+	sse := o.location.ServerSideEncryption
+	if sse == nil {
+		return nil
+	}
+
+	key, err := o.ObjectKey(remotePath)
+	if err != nil {
+		return err
+	}
+	bucket, err := o.getBucket(ctx)
+	if err != nil {
+		return err
+	}
+	header, err := bucket.GetObjectDetailedMeta(key, oss.WithContext(ctx))
+	if err != nil {
+		return o.objectError(key, err)
+	}
+
+	algorithm, keyID := ossServerSideEncryption(sse)
+	if got := header.Get(oss.HTTPHeaderOssServerSideEncryption); got != algorithm {
+		return fmt.Errorf("%s is encrypted with %q instead of %s", remotePath, got, algorithm)
+	}
+	if got := header.Get(oss.HTTPHeaderOssServerSideEncryptionKeyID); keyID != "" && got != keyID {
+		return fmt.Errorf("%s is encrypted with KMS key %q instead of %s", remotePath, got, keyID)
+	}
+	return nil
+}
+
+// SetTier moves an object to the IA or Archive storage class by copying it
+// onto itself, keeping its metadata and server-side encryption
+func (o *OSSStorage) SetTier(ctx context.Context, remotePath string, tier etcdguardianv1alpha1.StorageTier) error {
+	storageClass, err := ossStorageClass(tier)
+	if err != nil {
+		return err
+	}
+	key, err := o.ObjectKey(remotePath)
+	if err != nil {
+		return err
+	}
+	bucket, err := o.getBucket(ctx)
+	if err != nil {
+		return err
+	}
+
+	options := append(o.objectOptions(ctx, nil), oss.ObjectStorageClass(storageClass), oss.MetadataDirective(oss.MetaCopy))
+	if _, err := bucket.CopyObject(key, key, options...); err != nil {
+		return fmt.Errorf("failed to change storage class to %s: %w", storageClass, o.objectError(key, err))
+	}
+	return nil
+}
+
+// Rehydrate restores an archived object for reading. The restored copy stays
+// readable for a day while the object remains in its archive storage class.
+func (o *OSSStorage) Rehydrate(ctx context.Context, remotePath string) (bool, error) {
+	key, err := o.ObjectKey(remotePath)
+	if err != nil {
+		return false, err
+	}
+	bucket, err := o.getBucket(ctx)
+	if err != nil {
+		return false, err
+	}
+	header, err := bucket.GetObjectDetailedMeta(key, oss.WithContext(ctx))
+	if err != nil {
+		return false, o.objectError(key, err)
+	}
+
+	switch oss.StorageClassType(header.Get(oss.HTTPHeaderOssStorageClass)) {
+	case oss.StorageArchive, oss.StorageColdArchive, oss.StorageDeepColdArchive:
+	default:
+		return true, nil
+	}
+	restore := header.Get(ossRestoreHeader)
+	if strings.Contains(restore, `ongoing-request="false"`) {
+		return true, nil
+	}
+	if restore == "" {
+		if err := bucket.RestoreObject(key, oss.WithContext(ctx)); err != nil {
+			return false, fmt.Errorf("failed to restore archived object: %w", o.objectError(key, err))
+		}
+	}
+	return false, nil
+}
+
+// PresignURL signs a GET request of an object with the credentials of the
+// location. Temporary credentials add their security token to the URL, which
+// then stops working when they expire.
+func (o *OSSStorage) PresignURL(ctx context.Context, remotePath string, expiry time.Duration) (string, error) {
+	key, err := o.ObjectKey(remotePath)
+	if err != nil {
+		return "", err
+	}
+	bucket, err := o.getBucket(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	signedURL, err := bucket.SignURL(key, oss.HTTPGet, int64(expiry.Seconds()))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign %s: %w", remotePath, err)
+	}
+	return signedURL, nil
+}
+
+// ossStorageClass returns the OSS storage class of a tier
+func ossStorageClass(tier etcdguardianv1alpha1.StorageTier) (oss.StorageClassType, error) {
+	switch tier {
+	case etcdguardianv1alpha1.StorageTierInfrequentAccess:
+		return oss.StorageIA, nil
+	case etcdguardianv1alpha1.StorageTierArchive:
+		return oss.StorageArchive, nil
+	}
+	return "", fmt.Errorf("unsupported storage tier %q", tier)
+}
+
+// objectOptions returns the options of a request creating an object with
+// the given user metadata and the server-side encryption of the location
+func (o *OSSStorage) objectOptions(ctx context.Context, metadata map[string]string) []oss.Option {
+	options := []oss.Option{oss.WithContext(ctx)}
+	for name, value := range metadata {
+		options = append(options, oss.Meta(name, value))
+	}
+	if sse := o.location.ServerSideEncryption; sse != nil {
+		algorithm, keyID := ossServerSideEncryption(sse)
+		options = append(options, oss.ServerSideEncryption(algorithm))
+		if keyID != "" {
+			options = append(options, oss.ServerSideEncryptionKeyID(keyID))
+		}
+	}
+	return options
+}
+
+// ossServerSideEncryption returns the OSS encryption algorithm and KMS key
+// of server-side encryption settings
+func ossServerSideEncryption(sse *etcdguardianv1alpha1.ServerSideEncryption) (string, string) {
+	if sse.Mode == etcdguardianv1alpha1.ServerSideEncryptionSSEKMS {
+		return "KMS", sse.KMSKeyID
+	}
+	return "AES256", ""
+}
+
+// ObjectKey returns the key of the object a remote path addresses, which
+// must be in the bucket of the location
+func (o *OSSStorage) ObjectKey(remotePath string) (string, error) {
+	bucket, key, err := splitRemotePath(remotePath, "oss", o.location.Bucket)
+	if err != nil {
+		return "", err
+	}
+	if bucket != o.location.Bucket {
+		return "", fmt.Errorf("remote path %q is not in bucket %s", remotePath, o.location.Bucket)
+	}
+	return key, nil
+}
+
+// objectError wraps an error of an object operation, marking missing objects
+func (o *OSSStorage) objectError(key string, err error) error {
+	var serviceErr oss.ServiceError
+	if errors.As(err, &serviceErr) && serviceErr.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s: %w", o.remotePath(key), ErrObjectNotFound)
+	}
+	return fmt.Errorf("%s: %w", o.remotePath(key), err)
+}
+
+// remotePath returns the remote path of an object key
+func (o *OSSStorage) remotePath(key string) string {
+	return fmt.Sprintf("oss://%s/%s", o.location.Bucket, key)
+}
+
+// resolve returns the bucket and object key addressed by a remote path
+func (o *OSSStorage) resolve(ctx context.Context, remotePath string) (*oss.Bucket, string, error) {
+	bucketName, key, err := splitRemotePath(remotePath, "oss", o.location.Bucket)
+	if err != nil {
+		return nil, "", err
+	}
+
+	bucket, err := o.getBucket(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	if bucketName != bucket.BucketName {
+		if bucket, err = bucket.Client.Bucket(bucketName); err != nil {
+			return nil, "", fmt.Errorf("invalid oss bucket %s: %w", bucketName, err)
+		}
+	}
+	return bucket, key, nil
+}
+
+// getBucket lazily creates the OSS client on first use
+func (o *OSSStorage) getBucket(ctx context.Context) (*oss.Bucket, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.bucket != nil {
+		return o.bucket, nil
+	}
+
+	provider, err := o.credentialsProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := o.location.Endpoint
+	if endpoint == "" {
+		if o.location.Region == "" {
+			return nil, fmt.Errorf("OSS storage location needs a region or an endpoint")
+		}
+		endpoint = fmt.Sprintf("https://oss-%s.aliyuncs.com", o.location.Region)
+	}
+
+	ossClient, err := oss.New(endpoint, "", "", oss.SetCredentialsProvider(provider))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create oss client: %w", err)
+	}
+
+	bucket, err := ossClient.Bucket(o.location.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("invalid oss bucket %s: %w", o.location.Bucket, err)
+	}
+
+	o.bucket = bucket
+	return bucket, nil
 }
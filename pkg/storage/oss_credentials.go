@@ -0,0 +1,296 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// Keys read from the OSS credentials secret
+const (
+	ossAccessKeyIDKey     = "access-key-id"
+	ossAccessKeySecretKey = "access-key-secret"
+	ossSecurityTokenKey   = "security-token"
+	ossRAMRoleNameKey     = "ram-role-name"
+)
+
+// Environment variables used for keyless authentication. The RRSA variables
+// are injected by the ACK pod identity webhook; ALIBABA_CLOUD_ECS_METADATA
+// selects the ECS RAM role (an empty value discovers the attached role).
+const (
+	ossRoleARNEnv         = "ALIBABA_CLOUD_ROLE_ARN"
+	ossOIDCProviderARNEnv = "ALIBABA_CLOUD_OIDC_PROVIDER_ARN"
+	ossOIDCTokenFileEnv   = "ALIBABA_CLOUD_OIDC_TOKEN_FILE"
+	ossECSRoleEnv         = "ALIBABA_CLOUD_ECS_METADATA"
+)
+
+var (
+	// ossECSMetadataEndpoint is the ECS instance metadata service
+	ossECSMetadataEndpoint = "http://100.100.100.200"
+
+	// ossSTSEndpoint is the STS endpoint used to exchange RRSA tokens
+	ossSTSEndpoint = "https://sts.aliyuncs.com"
+
+	// ossCredentialsRefreshWindow is how long before expiry temporary
+	// credentials are refreshed
+	ossCredentialsRefreshWindow = 5 * time.Minute
+)
+
+// credentialsProvider selects how to authenticate against OSS. AK/SK from the
+// credentials secret take precedence, followed by a RAM role named in the
+// secret, RRSA and finally the ECS RAM role of the node.
+func (o *OSSStorage) credentialsProvider(ctx context.Context) (oss.CredentialsProvider, error) {
+	if o.location.CredentialsSecret != "" {
+		data, err := loadCredentials(ctx, o.client, o.namespace, o.location.CredentialsSecret)
+		if err != nil {
+			return nil, err
+		}
+
+		if accessKeyID := string(data[ossAccessKeyIDKey]); accessKeyID != "" {
+			accessKeySecret := string(data[ossAccessKeySecretKey])
+			if accessKeySecret == "" {
+				return nil, fmt.Errorf("credentials secret %s has no %s", o.location.CredentialsSecret, ossAccessKeySecretKey)
+			}
+			return &ossStaticCredentialsProvider{credentials: ossCredentials{
+				AccessKeyID:     accessKeyID,
+				AccessKeySecret: accessKeySecret,
+				SecurityToken:   string(data[ossSecurityTokenKey]),
+			}}, nil
+		}
+
+		if roleName, ok := data[ossRAMRoleNameKey]; ok {
+			return newOSSECSRoleProvider(string(roleName)), nil
+		}
+
+		return nil, fmt.Errorf("credentials secret %s has neither %s nor %s", o.location.CredentialsSecret, ossAccessKeyIDKey, ossRAMRoleNameKey)
+	}
+
+	roleARN, providerARN, tokenFile := os.Getenv(ossRoleARNEnv), os.Getenv(ossOIDCProviderARNEnv), os.Getenv(ossOIDCTokenFileEnv)
+	if roleARN != "" && providerARN != "" && tokenFile != "" {
+		return newOSSRRSAProvider(roleARN, providerARN, tokenFile), nil
+	}
+
+	if roleName, ok := os.LookupEnv(ossECSRoleEnv); ok {
+		return newOSSECSRoleProvider(roleName), nil
+	}
+
+	return nil, fmt.Errorf("no OSS credentials: set credentialsSecret, configure RRSA or enable the ECS RAM role")
+}
+
+// ossCredentials holds an OSS access key pair and optional STS token
+type ossCredentials struct {
+	AccessKeyID     string
+	AccessKeySecret string
+	SecurityToken   string
+	Expiration      time.Time
+}
+
+func (c ossCredentials) GetAccessKeyID() string     { return c.AccessKeyID }
+func (c ossCredentials) GetAccessKeySecret() string { return c.AccessKeySecret }
+func (c ossCredentials) GetSecurityToken() string   { return c.SecurityToken }
+
+// ossStaticCredentialsProvider returns fixed credentials
+type ossStaticCredentialsProvider struct {
+	credentials ossCredentials
+}
+
+func (p *ossStaticCredentialsProvider) GetCredentials() oss.Credentials {
+	return p.credentials
+}
+
+// ossRefreshingProvider caches temporary credentials and fetches new ones
+// shortly before they expire
+type ossRefreshingProvider struct {
+	fetch func(ctx context.Context) (ossCredentials, error)
+
+	mu          sync.Mutex
+	credentials *ossCredentials
+}
+
+func (p *ossRefreshingProvider) GetCredentials() oss.Credentials {
+	credentials, err := p.GetCredentialsE()
+	if err != nil {
+		return ossCredentials{}
+	}
+	return credentials
+}
+
+func (p *ossRefreshingProvider) GetCredentialsE() (oss.Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.credentials != nil && time.Until(p.credentials.Expiration) > ossCredentialsRefreshWindow {
+		return *p.credentials, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	credentials, err := p.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.credentials = &credentials
+	return credentials, nil
+}
+
+// ossSTSCredentials is the credential document returned by both the ECS
+// metadata service and STS
+type ossSTSCredentials struct {
+	AccessKeyID     string `json:"AccessKeyId"`
+	AccessKeySecret string `json:"AccessKeySecret"`
+	SecurityToken   string `json:"SecurityToken"`
+	Expiration      string `json:"Expiration"`
+}
+
+func (c ossSTSCredentials) toCredentials() (ossCredentials, error) {
+	if c.AccessKeyID == "" || c.AccessKeySecret == "" {
+		return ossCredentials{}, fmt.Errorf("response contains no access key")
+	}
+	expiration, err := time.Parse(time.RFC3339, c.Expiration)
+	if err != nil {
+		return ossCredentials{}, fmt.Errorf("invalid expiration %q: %w", c.Expiration, err)
+	}
+	return ossCredentials{
+		AccessKeyID:     c.AccessKeyID,
+		AccessKeySecret: c.AccessKeySecret,
+		SecurityToken:   c.SecurityToken,
+		Expiration:      expiration,
+	}, nil
+}
+
+// newOSSECSRoleProvider returns credentials of an ECS RAM role from the
+// instance metadata service. An empty role name uses the role attached to
+// the instance.
+func newOSSECSRoleProvider(roleName string) *ossRefreshingProvider {
+	return &ossRefreshingProvider{
+		fetch: func(ctx context.Context) (ossCredentials, error) {
+			base := ossECSMetadataEndpoint + "/latest/meta-data/ram/security-credentials/"
+
+			role := roleName
+			if role == "" {
+				body, err := httpGet(ctx, base)
+				if err != nil {
+					return ossCredentials{}, fmt.Errorf("failed to discover ECS RAM role: %w", err)
+				}
+				role = strings.TrimSpace(string(body))
+				if role == "" {
+					return ossCredentials{}, fmt.Errorf("no RAM role attached to the ECS instance")
+				}
+			}
+
+			body, err := httpGet(ctx, base+url.PathEscape(role))
+			if err != nil {
+				return ossCredentials{}, fmt.Errorf("failed to get credentials of ECS RAM role %s: %w", role, err)
+			}
+
+			var document struct {
+				ossSTSCredentials
+				Code string `json:"Code"`
+			}
+			if err := json.Unmarshal(body, &document); err != nil {
+				return ossCredentials{}, fmt.Errorf("failed to decode credentials of ECS RAM role %s: %w", role, err)
+			}
+			if document.Code != "" && document.Code != "Success" {
+				return ossCredentials{}, fmt.Errorf("ECS metadata service returned %s for RAM role %s", document.Code, role)
+			}
+			return document.toCredentials()
+		},
+	}
+}
+
+// newOSSRRSAProvider exchanges the projected service account token for STS
+// credentials of a RAM role (RAM Roles for Service Accounts)
+func newOSSRRSAProvider(roleARN, providerARN, tokenFile string) *ossRefreshingProvider {
+	return &ossRefreshingProvider{
+		fetch: func(ctx context.Context) (ossCredentials, error) {
+			token, err := os.ReadFile(tokenFile)
+			if err != nil {
+				return ossCredentials{}, fmt.Errorf("failed to read OIDC token: %w", err)
+			}
+
+			query := url.Values{}
+			query.Set("Action", "AssumeRoleWithOIDC")
+			query.Set("Format", "JSON")
+			query.Set("Version", "2015-04-01")
+			query.Set("Timestamp", time.Now().UTC().Format(time.RFC3339))
+			query.Set("RoleArn", roleARN)
+			query.Set("OIDCProviderArn", providerARN)
+			query.Set("RoleSessionName", "etcdguardian")
+
+			form := url.Values{}
+			form.Set("OIDCToken", strings.TrimSpace(string(token)))
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, ossSTSEndpoint+"/?"+query.Encode(), strings.NewReader(form.Encode()))
+			if err != nil {
+				return ossCredentials{}, err
+			}
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			body, err := doHTTP(req)
+			if err != nil {
+				return ossCredentials{}, fmt.Errorf("AssumeRoleWithOIDC for %s failed: %w", roleARN, err)
+			}
+
+			var response struct {
+				Credentials ossSTSCredentials `json:"Credentials"`
+			}
+			if err := json.Unmarshal(body, &response); err != nil {
+				return ossCredentials{}, fmt.Errorf("failed to decode AssumeRoleWithOIDC response: %w", err)
+			}
+			return response.Credentials.toCredentials()
+		},
+	}
+}
+
+// httpGet performs a GET request and returns the body of a successful response
+func httpGet(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	return doHTTP(req)
+}
+
+// doHTTP sends a request and returns the body of a successful response
+func doHTTP(req *http.Request) ([]byte, error) {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
@@ -0,0 +1,894 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeOSSObject is an object stored by fakeOSSServer
+type fakeOSSObject struct {
+	data     []byte
+	meta     http.Header
+	modified time.Time
+	// storageClass is empty for Standard
+	storageClass string
+	// restore is the X-Oss-Restore header of an archived object
+	restore string
+}
+
+// fakeOSSUpload is an in-progress multipart upload
+type fakeOSSUpload struct {
+	key       string
+	meta      http.Header
+	parts     map[int][]byte
+	initiated time.Time
+}
+
+// fakeOSSServer implements the subset of the OSS REST API used by OSSStorage
+// with path-style addressing
+type fakeOSSServer struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	objects       map[string]*fakeOSSObject
+	uploads       map[string]*fakeOSSUpload
+	nextUploadID  int
+	pageSize      int
+	authorization []string
+	tokens        []string
+	uploadedParts int
+	rangeRequests int
+	// failParts lists part numbers whose upload is rejected
+	failParts map[int]bool
+	// worm is the retention policy of the bucket
+	worm *oss.WormConfiguration
+	// restores counts the restore requests of archived objects
+	restores int
+}
+
+func newFakeOSSServer(t *testing.T) *fakeOSSServer {
+	f := &fakeOSSServer{
+		objects:  map[string]*fakeOSSObject{},
+		uploads:  map[string]*fakeOSSUpload{},
+		pageSize: 1000,
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeOSSServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.authorization = append(f.authorization, r.Header.Get("Authorization"))
+	f.tokens = append(f.tokens, r.Header.Get("X-Oss-Security-Token"))
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket == "" {
+		f.writeError(w, http.StatusBadRequest, "InvalidBucketName")
+		return
+	}
+	query := r.URL.Query()
+
+	switch {
+	case key == "" && (query.Has("worm") || query.Has("wormId")):
+		f.serveWorm(w, r)
+	case r.Method == http.MethodGet && key == "" && query.Has("uploads"):
+		f.listUploads(w, query)
+	case r.Method == http.MethodGet && key == "":
+		f.listObjects(w, query)
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		f.nextUploadID++
+		uploadID := fmt.Sprintf("upload-%d", f.nextUploadID)
+		f.uploads[uploadID] = &fakeOSSUpload{key: key, meta: userMeta(r.Header), parts: map[int][]byte{}, initiated: time.Now()}
+		writeXML(w, struct {
+			XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+			Bucket   string
+			Key      string
+			UploadID string `xml:"UploadId"`
+		}{Bucket: bucket, Key: key, UploadID: uploadID})
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		upload, ok := f.uploads[query.Get("uploadId")]
+		if !ok {
+			f.writeError(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		number, _ := strconv.Atoi(query.Get("partNumber"))
+		if f.failParts[number] {
+			f.writeError(w, http.StatusForbidden, "AccessDenied")
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		upload.parts[number] = data
+		f.uploadedParts++
+		w.Header().Set("ETag", fmt.Sprintf("\"part-%d\"", number))
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		upload, ok := f.uploads[query.Get("uploadId")]
+		if !ok {
+			f.writeError(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		numbers := []int{}
+		for number := range upload.parts {
+			numbers = append(numbers, number)
+		}
+		sort.Ints(numbers)
+		var data bytes.Buffer
+		for _, number := range numbers {
+			data.Write(upload.parts[number])
+		}
+		f.objects[upload.key] = &fakeOSSObject{data: data.Bytes(), meta: upload.meta, modified: time.Now()}
+		delete(f.uploads, query.Get("uploadId"))
+		writeXML(w, struct {
+			XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+			Bucket  string
+			Key     string
+			ETag    string
+		}{Bucket: bucket, Key: upload.key, ETag: "\"complete\""})
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		if _, ok := f.uploads[query.Get("uploadId")]; !ok {
+			f.writeError(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		delete(f.uploads, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut && r.Header.Get("X-Oss-Copy-Source") != "":
+		sourceKey, _ := url.QueryUnescape(strings.TrimPrefix(r.Header.Get("X-Oss-Copy-Source"), "/"+bucket+"/"))
+		source, ok := f.objects[sourceKey]
+		if !ok {
+			f.writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		copied := *source
+		copied.storageClass = r.Header.Get(oss.HTTPHeaderOssStorageClass)
+		copied.restore = ""
+		f.objects[key] = &copied
+		writeXML(w, struct {
+			XMLName xml.Name `xml:"CopyObjectResult"`
+			ETag    string
+		}{ETag: "\"copy\""})
+	case r.Method == http.MethodPost && query.Has("restore"):
+		object, ok := f.objects[key]
+		if !ok {
+			f.writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		if object.storageClass != string(oss.StorageArchive) {
+			f.writeError(w, http.StatusBadRequest, "OperationNotSupported")
+			return
+		}
+		f.restores++
+		object.restore = `ongoing-request="true"`
+		w.WriteHeader(http.StatusAccepted)
+	case r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[key] = &fakeOSSObject{data: data, meta: userMeta(r.Header), modified: time.Now()}
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodHead || r.Method == http.MethodGet:
+		object, ok := f.objects[key]
+		if !ok {
+			f.writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		for name, values := range object.meta {
+			w.Header()[name] = values
+		}
+		if object.storageClass != "" {
+			w.Header().Set(oss.HTTPHeaderOssStorageClass, object.storageClass)
+		}
+		if object.restore != "" {
+			w.Header().Set(ossRestoreHeader, object.restore)
+		}
+		w.Header().Set("ETag", "\"etag\"")
+		if r.Header.Get("Range") != "" {
+			f.rangeRequests++
+		}
+		http.ServeContent(w, r, key, object.modified, bytes.NewReader(object.data))
+	default:
+		f.writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+func (f *fakeOSSServer) listObjects(w http.ResponseWriter, query map[string][]string) {
+	prefix := first(query["prefix"])
+	keys := []string{}
+	for key := range f.objects {
+		if strings.HasPrefix(key, prefix) && key > first(query["continuation-token"]) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	type content struct {
+		Key          string
+		LastModified string
+		ETag         string
+		Size         int64
+	}
+	result := struct {
+		XMLName               xml.Name `xml:"ListBucketResult"`
+		Prefix                string
+		IsTruncated           bool
+		NextContinuationToken string
+		Contents              []content
+	}{Prefix: prefix}
+
+	if len(keys) > f.pageSize {
+		keys = keys[:f.pageSize]
+		result.IsTruncated = true
+		result.NextContinuationToken = keys[len(keys)-1]
+	}
+	for _, key := range keys {
+		object := f.objects[key]
+		result.Contents = append(result.Contents, content{
+			Key:          key,
+			LastModified: object.modified.UTC().Format(time.RFC3339),
+			ETag:         "\"etag\"",
+			Size:         int64(len(object.data)),
+		})
+	}
+	writeXML(w, result)
+}
+
+// serveWorm implements reading the bucket retention policy. Writes to it
+// are rejected, as storage never changes the governance of buckets.
+func (f *fakeOSSServer) serveWorm(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		f.writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+		return
+	}
+	if f.worm == nil {
+		f.writeError(w, http.StatusNotFound, "NoSuchWORMConfiguration")
+		return
+	}
+	writeXML(w, f.worm)
+}
+
+func (f *fakeOSSServer) listUploads(w http.ResponseWriter, query map[string][]string) {
+	prefix := first(query["prefix"])
+	type upload struct {
+		Key       string
+		UploadID  string `xml:"UploadId"`
+		Initiated string
+	}
+	result := struct {
+		XMLName     xml.Name `xml:"ListMultipartUploadsResult"`
+		Prefix      string
+		IsTruncated bool
+		Uploads     []upload `xml:"Upload"`
+	}{Prefix: prefix}
+
+	for uploadID, pending := range f.uploads {
+		if strings.HasPrefix(pending.key, prefix) {
+			result.Uploads = append(result.Uploads, upload{
+				Key:       pending.key,
+				UploadID:  uploadID,
+				Initiated: pending.initiated.UTC().Format(time.RFC3339),
+			})
+		}
+	}
+	writeXML(w, result)
+}
+
+func (f *fakeOSSServer) writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
+
+// userMeta returns the request headers stored with an object: the user
+// metadata and the server-side encryption
+func userMeta(header http.Header) http.Header {
+	meta := http.Header{}
+	for name, values := range header {
+		if strings.HasPrefix(name, ossMetaPrefix) || strings.HasPrefix(name, oss.HTTPHeaderOssServerSideEncryption) {
+			meta[name] = values
+		}
+	}
+	return meta
+}
+
+func writeXML(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(v)
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func newFakeClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func credentialsSecret(name string, data map[string]string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Data:       map[string][]byte{},
+	}
+	for key, value := range data {
+		secret.Data[key] = []byte(value)
+	}
+	return secret
+}
+
+func testBackup(name string) *etcdguardianv1alpha1.EtcdBackup {
+	return &etcdguardianv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: etcdguardianv1alpha1.EtcdBackupSpec{
+			BackupMode: etcdguardianv1alpha1.BackupModeFull,
+		},
+		Status: etcdguardianv1alpha1.EtcdBackupStatus{EtcdRevision: 42},
+	}
+}
+
+func writeSnapshot(t *testing.T, size int) (string, []byte) {
+	t.Helper()
+
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	snapshotPath := filepath.Join(t.TempDir(), "etcd-snapshot.db")
+	if err := os.WriteFile(snapshotPath, data, 0o600); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+	return snapshotPath, data
+}
+
+func newTestOSSStorage(t *testing.T, server *fakeOSSServer, k8sClient client.Client, secret string) *OSSStorage {
+	t.Helper()
+
+	storage, err := NewOSSStorage(etcdguardianv1alpha1.StorageLocation{
+		Provider:          etcdguardianv1alpha1.StorageProviderOSS,
+		Bucket:            "backups",
+		Prefix:            "etcd",
+		Region:            "cn-hangzhou",
+		Endpoint:          server.URL,
+		CredentialsSecret: secret,
+	}, k8sClient, "default")
+	if err != nil {
+		t.Fatalf("NewOSSStorage failed: %v", err)
+	}
+	storage.partSize = 100 * 1024
+	return storage
+}
+
+func TestOSSStorage_UploadDownload(t *testing.T) {
+	server := newFakeOSSServer(t)
+	k8sClient := newFakeClient(credentialsSecret("oss-credentials", map[string]string{
+		ossAccessKeyIDKey:     "test-ak",
+		ossAccessKeySecretKey: "test-sk",
+	}))
+	storage := newTestOSSStorage(t, server, k8sClient, "oss-credentials")
+
+	snapshotPath, data := writeSnapshot(t, 250*1024)
+	ctx := context.Background()
+
+	location, err := storage.Upload(ctx, snapshotPath, testBackup("nightly"))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if location != "oss://backups/etcd/default/nightly/etcd-snapshot.db" {
+		t.Errorf("Unexpected location %q", location)
+	}
+	if server.uploadedParts != 3 {
+		t.Errorf("Expected 3 uploaded parts, got %d", server.uploadedParts)
+	}
+	if _, err := os.Stat(snapshotPath + ossCheckpointSuffix); !os.IsNotExist(err) {
+		t.Errorf("Expected checkpoint file to be removed after upload, got %v", err)
+	}
+	if !strings.HasPrefix(server.authorization[0], "OSS test-ak:") {
+		t.Errorf("Expected request signed with access key, got %q", server.authorization[0])
+	}
+
+	metadata, err := storage.GetMetadata(ctx, location)
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if metadata.Size != int64(len(data)) {
+		t.Errorf("Expected size %d, got %d", len(data), metadata.Size)
+	}
+	if metadata.EtcdRevision != 42 {
+		t.Errorf("Expected revision 42, got %d", metadata.EtcdRevision)
+	}
+
+	downloadPath := filepath.Join(t.TempDir(), "restored.db")
+	if err := storage.Download(ctx, location, downloadPath); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	downloaded, err := os.ReadFile(downloadPath)
+	if err != nil {
+		t.Fatalf("Failed to read download: %v", err)
+	}
+	if !bytes.Equal(downloaded, data) {
+		t.Error("Downloaded snapshot does not match uploaded data")
+	}
+	if server.rangeRequests < 3 {
+		t.Errorf("Expected ranged download requests, got %d", server.rangeRequests)
+	}
+}
+
+func TestOSSStorage_ResumesUploadSession(t *testing.T) {
+	server := newFakeOSSServer(t)
+	server.failParts = map[int]bool{3: true}
+	k8sClient := newFakeClient(credentialsSecret("oss-credentials", map[string]string{
+		ossAccessKeyIDKey:     "test-ak",
+		ossAccessKeySecretKey: "test-sk",
+	}))
+	snapshotPath, data := writeSnapshot(t, 250*1024)
+	ctx := context.Background()
+
+	session := &etcdguardianv1alpha1.UploadSession{Destination: "primary"}
+	checkpoints := 0
+	checkpoint := func(*etcdguardianv1alpha1.UploadSession) error {
+		checkpoints++
+		return nil
+	}
+
+	storage := newTestOSSStorage(t, server, k8sClient, "oss-credentials")
+	if _, err := storage.UploadResumable(ctx, snapshotPath, testBackup("nightly"), session, checkpoint); err == nil {
+		t.Fatal("Expected interrupted upload to fail")
+	}
+	if session.UploadID == "" || len(session.CompletedParts) != 2 {
+		t.Fatalf("Expected session with 2 completed parts, got %+v", session)
+	}
+	if checkpoints != 3 {
+		t.Errorf("Expected a checkpoint per initiated upload and part, got %d", checkpoints)
+	}
+
+	// A new backend instance stands in for a restarted operator
+	server.failParts = nil
+	server.uploadedParts = 0
+	storage = newTestOSSStorage(t, server, k8sClient, "oss-credentials")
+	location, err := storage.UploadResumable(ctx, snapshotPath, testBackup("nightly"), session, checkpoint)
+	if err != nil {
+		t.Fatalf("Resumed upload failed: %v", err)
+	}
+	if server.uploadedParts != 1 {
+		t.Errorf("Expected only the missing part to be uploaded, got %d", server.uploadedParts)
+	}
+	if object := server.objects["etcd/default/nightly/etcd-snapshot.db"]; object == nil || !bytes.Equal(object.data, data) {
+		t.Error("Uploaded snapshot does not match local data")
+	}
+	if location != session.RemotePath {
+		t.Errorf("Expected location %s, got %s", session.RemotePath, location)
+	}
+}
+
+func TestOSSStorage_ListAndAbortUploads(t *testing.T) {
+	server := newFakeOSSServer(t)
+	server.failParts = map[int]bool{1: true}
+	k8sClient := newFakeClient(credentialsSecret("oss-credentials", map[string]string{
+		ossAccessKeyIDKey:     "test-ak",
+		ossAccessKeySecretKey: "test-sk",
+	}))
+	storage := newTestOSSStorage(t, server, k8sClient, "oss-credentials")
+	snapshotPath, _ := writeSnapshot(t, 1024)
+	ctx := context.Background()
+
+	session := &etcdguardianv1alpha1.UploadSession{}
+	if _, err := storage.UploadResumable(ctx, snapshotPath, testBackup("nightly"), session, nil); err == nil {
+		t.Fatal("Expected upload to fail")
+	}
+
+	uploads, err := storage.ListUploads(ctx)
+	if err != nil {
+		t.Fatalf("ListUploads failed: %v", err)
+	}
+	if len(uploads) != 1 || uploads[0].UploadID != session.UploadID || uploads[0].RemotePath != session.RemotePath {
+		t.Fatalf("Unexpected uploads %+v for session %+v", uploads, session)
+	}
+	if uploads[0].Initiated.IsZero() {
+		t.Error("Expected initiation time")
+	}
+
+	// Retaking the snapshot under another name replaces the stale upload
+	server.failParts = nil
+	renamedPath := filepath.Join(filepath.Dir(snapshotPath), "etcd-snapshot-2.db")
+	if err := os.Rename(snapshotPath, renamedPath); err != nil {
+		t.Fatalf("Failed to rename snapshot: %v", err)
+	}
+	staleUploadID := session.UploadID
+	if _, err := storage.UploadResumable(ctx, renamedPath, testBackup("nightly"), session, nil); err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if session.UploadID == staleUploadID {
+		t.Error("Expected a new upload for the retaken snapshot")
+	}
+	if _, ok := server.uploads[staleUploadID]; ok {
+		t.Error("Expected stale upload to be aborted")
+	}
+
+	if err := storage.AbortUpload(ctx, session.RemotePath, staleUploadID); err != nil {
+		t.Errorf("Expected aborting a missing upload to succeed, got %v", err)
+	}
+}
+
+func TestOSSStorage_ListAndDelete(t *testing.T) {
+	server := newFakeOSSServer(t)
+	server.pageSize = 2
+	k8sClient := newFakeClient(credentialsSecret("oss-credentials", map[string]string{
+		ossAccessKeyIDKey:     "test-ak",
+		ossAccessKeySecretKey: "test-sk",
+	}))
+	storage := newTestOSSStorage(t, server, k8sClient, "oss-credentials")
+	ctx := context.Background()
+
+	snapshotPath, _ := writeSnapshot(t, 1024)
+	for _, name := range []string{"daily", "daily-2", "hourly"} {
+		if _, err := storage.Upload(ctx, snapshotPath, testBackup(name)); err != nil {
+			t.Fatalf("Upload of %s failed: %v", name, err)
+		}
+	}
+	server.objects["other/unrelated.db"] = &fakeOSSObject{modified: time.Now()}
+
+	snapshots, err := storage.List(ctx, "")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(snapshots) != 3 {
+		t.Fatalf("Expected 3 snapshots across pages, got %d", len(snapshots))
+	}
+
+	snapshots, err = storage.List(ctx, "default/daily/")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].Path != "oss://backups/etcd/default/daily/etcd-snapshot.db" {
+		t.Fatalf("Unexpected snapshots for prefix: %+v", snapshots)
+	}
+
+	if err := storage.Delete(ctx, snapshots[0].Path); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := storage.GetMetadata(ctx, snapshots[0].Path); err == nil {
+		t.Error("Expected error for deleted snapshot")
+	}
+}
+
+func TestOSSStorage_LockObject(t *testing.T) {
+	server := newFakeOSSServer(t)
+	k8sClient := newFakeClient(credentialsSecret("oss-credentials", map[string]string{
+		ossAccessKeyIDKey:     "test-ak",
+		ossAccessKeySecretKey: "test-sk",
+	}))
+	storage := newTestOSSStorage(t, server, k8sClient, "oss-credentials")
+	ctx := context.Background()
+
+	snapshotPath, _ := writeSnapshot(t, 1024)
+	location, err := storage.Upload(ctx, snapshotPath, testBackup("nightly"))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	days := func(n int) time.Time { return time.Now().Add(time.Duration(n)*24*time.Hour - time.Hour) }
+
+	if err := storage.LockObject(ctx, location, etcdguardianv1alpha1.ImmutabilityModeGovernance, days(30)); err == nil {
+		t.Error("Expected governance mode to be rejected")
+	}
+	if server.worm != nil {
+		t.Fatalf("Expected no retention policy, got %+v", server.worm)
+	}
+
+	// Bucket policies are never created, locked or extended by uploads
+	insufficient := func(retainUntil time.Time) {
+		t.Helper()
+		err := storage.LockObject(ctx, location, etcdguardianv1alpha1.ImmutabilityModeCompliance, retainUntil)
+		if !errors.Is(err, ErrRetentionPolicyInsufficient) || Classify(err).Reason() != "RetentionPolicyInsufficient" {
+			t.Errorf("Expected an insufficient retention policy, got %v", err)
+		}
+	}
+	insufficient(days(30))
+	if server.worm != nil {
+		t.Fatalf("Expected no retention policy to be created, got %+v", server.worm)
+	}
+
+	server.worm = &oss.WormConfiguration{WormId: "worm-1", State: ossWormInProgress, RetentionPeriodInDays: 30}
+	insufficient(days(30))
+	if server.worm.State != ossWormInProgress {
+		t.Fatalf("Expected the retention policy to be left unlocked, got %+v", server.worm)
+	}
+
+	server.worm.State = "Locked"
+	if err := storage.LockObject(ctx, location, etcdguardianv1alpha1.ImmutabilityModeCompliance, days(30)); err != nil {
+		t.Fatalf("LockObject failed: %v", err)
+	}
+	if err := storage.LockObject(ctx, location, etcdguardianv1alpha1.ImmutabilityModeCompliance, days(10)); err != nil {
+		t.Fatalf("LockObject failed: %v", err)
+	}
+	insufficient(days(60))
+	if server.worm.RetentionPeriodInDays != 30 {
+		t.Errorf("Expected the retention policy to be left at 30 days, got %d", server.worm.RetentionPeriodInDays)
+	}
+
+	if err := storage.SetLegalHold(ctx, location, true); err == nil {
+		t.Error("Expected legal holds to be unsupported")
+	}
+}
+
+func TestOSSStorage_Tiering(t *testing.T) {
+	server := newFakeOSSServer(t)
+	k8sClient := newFakeClient(credentialsSecret("oss-credentials", map[string]string{
+		ossAccessKeyIDKey:     "test-ak",
+		ossAccessKeySecretKey: "test-sk",
+	}))
+	storage := newTestOSSStorage(t, server, k8sClient, "oss-credentials")
+	ctx := context.Background()
+
+	snapshotPath, data := writeSnapshot(t, 1024)
+	location, err := storage.Upload(ctx, snapshotPath, testBackup("nightly"))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	key, _ := storage.ObjectKey(location)
+
+	if err := storage.SetTier(ctx, location, etcdguardianv1alpha1.StorageTierInfrequentAccess); err != nil {
+		t.Fatalf("SetTier failed: %v", err)
+	}
+	object := server.objects[key]
+	if object.storageClass != "IA" || !bytes.Equal(object.data, data) || object.meta.Get(ossMetaPrefix+metadataBackupName) != "nightly" {
+		t.Fatalf("Expected the snapshot in IA with its content and metadata, got class %q and metadata %v", object.storageClass, object.meta)
+	}
+	if ready, err := storage.Rehydrate(ctx, location); err != nil || !ready {
+		t.Fatalf("Expected an IA snapshot to be readable, got %v, %v", ready, err)
+	}
+
+	if err := storage.SetTier(ctx, location, etcdguardianv1alpha1.StorageTierArchive); err != nil {
+		t.Fatalf("SetTier failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if ready, err := storage.Rehydrate(ctx, location); err != nil || ready {
+			t.Fatalf("Expected the archived snapshot to be restoring, got %v, %v", ready, err)
+		}
+	}
+	if server.restores != 1 {
+		t.Errorf("Expected a single restore request, got %d", server.restores)
+	}
+
+	server.objects[key].restore = `ongoing-request="false", expiry-date="Sun, 16 Apr 2028 08:12:33 GMT"`
+	if ready, err := storage.Rehydrate(ctx, location); err != nil || !ready {
+		t.Fatalf("Expected the restored snapshot to be readable, got %v, %v", ready, err)
+	}
+
+	if err := storage.SetTier(ctx, "oss://backups/etcd/missing", etcdguardianv1alpha1.StorageTierArchive); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("Expected ErrObjectNotFound for a missing object, got %v", err)
+	}
+}
+
+func TestOSSStorage_PresignURL(t *testing.T) {
+	server := newFakeOSSServer(t)
+	k8sClient := newFakeClient(credentialsSecret("oss-credentials", map[string]string{
+		ossAccessKeyIDKey:     "test-ak",
+		ossAccessKeySecretKey: "test-sk",
+	}))
+	storage := newTestOSSStorage(t, server, k8sClient, "oss-credentials")
+	ctx := context.Background()
+
+	snapshotPath, data := writeSnapshot(t, 1024)
+	location, err := storage.Upload(ctx, snapshotPath, testBackup("nightly"))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+
+	signedURL, err := storage.PresignURL(ctx, location, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("PresignURL failed: %v", err)
+	}
+	parsed, err := url.Parse(signedURL)
+	if err != nil {
+		t.Fatalf("Invalid presigned URL %q: %v", signedURL, err)
+	}
+	query := parsed.Query()
+	if query.Get("OSSAccessKeyId") != "test-ak" || query.Get("Signature") == "" || query.Get("Expires") == "" {
+		t.Fatalf("Expected a signed URL, got %q", signedURL)
+	}
+
+	response, err := http.Get(signedURL)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer response.Body.Close()
+	downloaded, _ := io.ReadAll(response.Body)
+	if response.StatusCode != http.StatusOK || !bytes.Equal(downloaded, data) {
+		t.Errorf("Expected the snapshot from the presigned URL, got status %d and %d bytes", response.StatusCode, len(downloaded))
+	}
+}
+
+func TestOSSStorage_ServerSideEncryption(t *testing.T) {
+	server := newFakeOSSServer(t)
+	k8sClient := newFakeClient(credentialsSecret("oss-credentials", map[string]string{
+		ossAccessKeyIDKey:     "test-ak",
+		ossAccessKeySecretKey: "test-sk",
+	}))
+	ctx := context.Background()
+
+	plain := newTestOSSStorage(t, server, k8sClient, "oss-credentials")
+	kms := newTestOSSStorage(t, server, k8sClient, "oss-credentials")
+	kms.location.ServerSideEncryption = &etcdguardianv1alpha1.ServerSideEncryption{
+		Mode:     etcdguardianv1alpha1.ServerSideEncryptionSSEKMS,
+		KMSKeyID: "cmk-1",
+	}
+
+	// Larger than a part, so that the multipart upload is encrypted too
+	snapshotPath, _ := writeSnapshot(t, 250*1024)
+	unencrypted, err := plain.Upload(ctx, snapshotPath, testBackup("plain"))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	location, err := kms.Upload(ctx, snapshotPath, testBackup("kms"))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if _, err := kms.PutObject(ctx, "etcd/small", strings.NewReader("small"), PutOptions{Size: 5}); err != nil {
+		t.Fatalf("PutObject failed: %v", err)
+	}
+
+	for _, path := range []string{location, "oss://backups/etcd/small"} {
+		if err := kms.VerifyEncryption(ctx, path); err != nil {
+			t.Errorf("Expected %s to be encrypted with the KMS key: %v", path, err)
+		}
+	}
+	if err := kms.VerifyEncryption(ctx, unencrypted); err == nil {
+		t.Error("Expected an unencrypted snapshot to fail verification")
+	}
+
+	other := newTestOSSStorage(t, server, k8sClient, "oss-credentials")
+	other.location.ServerSideEncryption = &etcdguardianv1alpha1.ServerSideEncryption{
+		Mode:     etcdguardianv1alpha1.ServerSideEncryptionSSEKMS,
+		KMSKeyID: "cmk-2",
+	}
+	if err := other.VerifyEncryption(ctx, location); err == nil {
+		t.Error("Expected a snapshot encrypted with another KMS key to fail verification")
+	}
+
+	aes := newTestOSSStorage(t, server, k8sClient, "oss-credentials")
+	aes.location.ServerSideEncryption = &etcdguardianv1alpha1.ServerSideEncryption{Mode: etcdguardianv1alpha1.ServerSideEncryptionSSES3}
+	if err := aes.VerifyEncryption(ctx, location); err == nil {
+		t.Error("Expected a KMS encrypted snapshot to fail the AES256 verification")
+	}
+}
+
+func TestOSSStorage_SecurityToken(t *testing.T) {
+	server := newFakeOSSServer(t)
+	k8sClient := newFakeClient(credentialsSecret("oss-sts", map[string]string{
+		ossAccessKeyIDKey:     "sts-ak",
+		ossAccessKeySecretKey: "sts-sk",
+		ossSecurityTokenKey:   "sts-token",
+	}))
+	storage := newTestOSSStorage(t, server, k8sClient, "oss-sts")
+
+	if _, err := storage.List(context.Background(), ""); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if server.tokens[0] != "sts-token" {
+		t.Errorf("Expected security token header, got %q", server.tokens[0])
+	}
+}
+
+func TestOSSStorage_ECSRAMRole(t *testing.T) {
+	expiration := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	metadataServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/latest/meta-data/ram/security-credentials/":
+			fmt.Fprint(w, "etcd-backup-role")
+		case "/latest/meta-data/ram/security-credentials/etcd-backup-role":
+			fmt.Fprintf(w, `{"Code":"Success","AccessKeyId":"role-ak","AccessKeySecret":"role-sk","SecurityToken":"role-token","Expiration":%q}`, expiration)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer metadataServer.Close()
+
+	previous := ossECSMetadataEndpoint
+	ossECSMetadataEndpoint = metadataServer.URL
+	defer func() { ossECSMetadataEndpoint = previous }()
+	t.Setenv(ossECSRoleEnv, "")
+
+	server := newFakeOSSServer(t)
+	storage := newTestOSSStorage(t, server, nil, "")
+
+	if _, err := storage.List(context.Background(), ""); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if !strings.HasPrefix(server.authorization[0], "OSS role-ak:") {
+		t.Errorf("Expected request signed with role credentials, got %q", server.authorization[0])
+	}
+	if server.tokens[0] != "role-token" {
+		t.Errorf("Expected role security token, got %q", server.tokens[0])
+	}
+}
+
+func TestOSSStorage_RRSA(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("oidc-token\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write token: %v", err)
+	}
+
+	expiration := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	stsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("Action") != "AssumeRoleWithOIDC" ||
+			r.URL.Query().Get("RoleArn") != "acs:ram::123:role/etcd" ||
+			r.FormValue("OIDCToken") != "oidc-token" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"Credentials":{"AccessKeyId":"rrsa-ak","AccessKeySecret":"rrsa-sk","SecurityToken":"rrsa-token","Expiration":%q}}`, expiration)
+	}))
+	defer stsServer.Close()
+
+	previous := ossSTSEndpoint
+	ossSTSEndpoint = stsServer.URL
+	defer func() { ossSTSEndpoint = previous }()
+	t.Setenv(ossRoleARNEnv, "acs:ram::123:role/etcd")
+	t.Setenv(ossOIDCProviderARNEnv, "acs:ram::123:oidc-provider/ack")
+	t.Setenv(ossOIDCTokenFileEnv, tokenFile)
+
+	server := newFakeOSSServer(t)
+	storage := newTestOSSStorage(t, server, nil, "")
+
+	if _, err := storage.List(context.Background(), ""); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if !strings.HasPrefix(server.authorization[0], "OSS rrsa-ak:") {
+		t.Errorf("Expected request signed with RRSA credentials, got %q", server.authorization[0])
+	}
+}
+
+func TestOSSStorage_NoCredentials(t *testing.T) {
+	t.Setenv(ossECSRoleEnv, "")
+	os.Unsetenv(ossECSRoleEnv)
+	server := newFakeOSSServer(t)
+	storage := newTestOSSStorage(t, server, nil, "")
+
+	if _, err := storage.List(context.Background(), ""); err == nil {
+		t.Error("Expected error without credentials")
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Operation names a storage operation in the recorded attempts
+type Operation string
+
+const (
+	OperationUpload           Operation = "Upload"
+	OperationUploadManifest   Operation = "UploadManifest"
+	OperationDownload         Operation = "Download"
+	OperationLock             Operation = "Lock"
+	OperationLegalHold        Operation = "LegalHold"
+	OperationVerifyEncryption Operation = "VerifyEncryption"
+	OperationTier             Operation = "Tier"
+	OperationRehydrate        Operation = "Rehydrate"
+)
+
+// RetryPolicy defines how often and how fast failed storage operations are
+// retried. Only errors classified as retryable are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts, including the first
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry, doubled for
+	// every further retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts
+	MaxBackoff time.Duration
+	// Timeout limits a single attempt of operations not in Timeouts
+	Timeout time.Duration
+	// Timeouts limits a single attempt of the given operations
+	Timeouts map[Operation]time.Duration
+	// Attempts overrides MaxAttempts for the given operations
+	Attempts map[Operation]int
+}
+
+// DefaultRetryPolicy is the retry policy of storage operations run outside
+// the reconcile loop, such as in backup Jobs. Transfers get an hour per
+// attempt, other operations two minutes.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 2 * time.Second,
+	MaxBackoff:     time.Minute,
+	Timeout:        2 * time.Minute,
+	Timeouts: map[Operation]time.Duration{
+		OperationUpload:         time.Hour,
+		OperationUploadManifest: 10 * time.Minute,
+		OperationDownload:       time.Hour,
+	},
+}
+
+// ReconcileRetryPolicy is the retry policy of storage operations run by
+// reconcilers. Short operations are retried with backoffs of a few seconds;
+// transfers, which may take up to an hour, get a single attempt and are
+// retried by requeueing the object instead of blocking a worker.
+var ReconcileRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Second,
+	MaxBackoff:     5 * time.Second,
+	Timeout:        2 * time.Minute,
+	Timeouts:       DefaultRetryPolicy.Timeouts,
+	Attempts: map[Operation]int{
+		OperationUpload:         1,
+		OperationUploadManifest: 1,
+		OperationDownload:       1,
+	},
+}
+
+// timeout returns the time limit of a single attempt of an operation, zero
+// for none
+func (p RetryPolicy) timeout(operation Operation) time.Duration {
+	if timeout, ok := p.Timeouts[operation]; ok {
+		return timeout
+	}
+	return p.Timeout
+}
+
+// maxAttempts returns the number of attempts of an operation, at least one
+func (p RetryPolicy) maxAttempts(operation Operation) int {
+	attempts := p.MaxAttempts
+	if override, ok := p.Attempts[operation]; ok {
+		attempts = override
+	}
+	return max(attempts, 1)
+}
+
+// Backoff returns the delay before the given retry, counted from 1. The
+// exponential delay is jittered by up to half so that operators retrying
+// against the same provider spread out.
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < retry && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(backoff-half)+1))
+}
+
+// Attempt is the outcome of one attempt of a storage operation
+type Attempt struct {
+	Operation Operation
+	// Number of the attempt, counted from 1
+	Number   int
+	Start    time.Time
+	Duration time.Duration
+	// Err is nil for a successful attempt
+	Err   error
+	Class ErrorClass
+}
+
+// OperationError is the error of a storage operation that failed for good,
+// either with an error that is not retryable or on its last attempt
+type OperationError struct {
+	Operation Operation
+	Class     ErrorClass
+	Attempts  int
+	Err       error
+}
+
+func (e *OperationError) Error() string {
+	if e.Attempts > 1 {
+		return fmt.Sprintf("%v (%s, %d attempts)", e.Err, e.Class, e.Attempts)
+	}
+	return fmt.Sprintf("%v (%s)", e.Err, e.Class)
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// Retry runs a storage operation until it succeeds, fails with an error
+// that is not retryable or runs out of attempts, waiting the backoff of the
+// policy between attempts. Every attempt runs with the timeout of the
+// operation and is passed to record, which may be nil. The error returned is
+// an *OperationError.
+func Retry(ctx context.Context, policy RetryPolicy, operation Operation, record func(Attempt), fn func(ctx context.Context) error) error {
+	maxAttempts := policy.maxAttempts(operation)
+
+	for number := 1; ; number++ {
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout := policy.timeout(operation); timeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		start := time.Now()
+		err := fn(attemptCtx)
+		cancel()
+
+		attempt := Attempt{Operation: operation, Number: number, Start: start, Duration: time.Since(start), Err: err}
+		if err != nil {
+			attempt.Class = Classify(err)
+		}
+		if record != nil {
+			record(attempt)
+		}
+		if err == nil {
+			return nil
+		}
+
+		opErr := &OperationError{Operation: operation, Class: attempt.Class, Attempts: number, Err: err}
+		if !attempt.Class.Retryable() || number >= maxAttempts || ctx.Err() != nil {
+			return opErr
+		}
+
+		timer := time.NewTimer(policy.Backoff(number))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return opErr
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// fastRetryPolicy retries without noticeable delays
+var fastRetryPolicy = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond, Timeout: time.Minute}
+
+func TestRetry_RetriesTransientErrors(t *testing.T) {
+	attempts := []Attempt{}
+	calls := 0
+	err := Retry(context.Background(), fastRetryPolicy, OperationUpload, func(attempt Attempt) {
+		attempts = append(attempts, attempt)
+	}, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return oss.ServiceError{StatusCode: 503, Code: "ServiceUnavailable"}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Retry failed: %v", err)
+	}
+
+	if len(attempts) != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", len(attempts))
+	}
+	for i, attempt := range attempts {
+		if attempt.Number != i+1 || attempt.Operation != OperationUpload {
+			t.Errorf("Unexpected attempt %+v", attempt)
+		}
+	}
+	if attempts[0].Class != ErrorClassRetryable || attempts[2].Err != nil || attempts[2].Class != "" {
+		t.Errorf("Unexpected attempts %+v", attempts)
+	}
+}
+
+func TestRetry_FailsFastOnPermanentErrors(t *testing.T) {
+	calls := 0
+	err := Retry(context.Background(), fastRetryPolicy, OperationLock, nil, func(context.Context) error {
+		calls++
+		return oss.ServiceError{StatusCode: 403, Code: "AccessDenied"}
+	})
+
+	var opErr *OperationError
+	if !errors.As(err, &opErr) || opErr.Class != ErrorClassAuth || opErr.Attempts != 1 || opErr.Operation != OperationLock {
+		t.Fatalf("Expected an auth OperationError, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single attempt, got %d", calls)
+	}
+	if opErr.Class.Reason() != "StorageAuthFailed" {
+		t.Errorf("Unexpected reason %s", opErr.Class.Reason())
+	}
+}
+
+func TestRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	calls := 0
+	err := Retry(context.Background(), fastRetryPolicy, OperationUpload, nil, func(context.Context) error {
+		calls++
+		return fmt.Errorf("write: %w", syscall.ECONNRESET)
+	})
+
+	if calls != fastRetryPolicy.MaxAttempts {
+		t.Errorf("Expected %d attempts, got %d", fastRetryPolicy.MaxAttempts, calls)
+	}
+	if Classify(err) != ErrorClassRetryable || !errors.Is(err, syscall.ECONNRESET) {
+		t.Errorf("Expected the retryable error of the last attempt, got %v", err)
+	}
+}
+
+func TestRetry_OperationAttempts(t *testing.T) {
+	policy := fastRetryPolicy
+	policy.Attempts = map[Operation]int{OperationUpload: 1}
+
+	calls := map[Operation]int{}
+	for _, operation := range []Operation{OperationUpload, OperationLock} {
+		err := Retry(context.Background(), policy, operation, nil, func(context.Context) error {
+			calls[operation]++
+			return fmt.Errorf("write: %w", syscall.ECONNRESET)
+		})
+		if Classify(err) != ErrorClassRetryable {
+			t.Errorf("Expected the retryable error of %s, got %v", operation, err)
+		}
+	}
+	if calls[OperationUpload] != 1 || calls[OperationLock] != fastRetryPolicy.MaxAttempts {
+		t.Errorf("Expected a single upload attempt and %d lock attempts, got %v", fastRetryPolicy.MaxAttempts, calls)
+	}
+}
+
+func TestRetry_TimesOutAttempts(t *testing.T) {
+	policy := fastRetryPolicy
+	policy.MaxAttempts = 2
+	policy.Timeouts = map[Operation]time.Duration{OperationDownload: 10 * time.Millisecond}
+
+	calls := 0
+	err := Retry(context.Background(), policy, OperationDownload, nil, func(ctx context.Context) error {
+		calls++
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if calls != 2 || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected 2 attempts that time out, got %d: %v", calls, err)
+	}
+}
+
+func TestRetry_StopsOnCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := fastRetryPolicy
+	policy.InitialBackoff, policy.MaxBackoff = time.Hour, time.Hour
+
+	calls := 0
+	err := Retry(ctx, policy, OperationUpload, nil, func(context.Context) error {
+		calls++
+		cancel()
+		return fmt.Errorf("read: %w", syscall.ECONNRESET)
+	})
+	if calls != 1 || err == nil {
+		t.Errorf("Expected a single attempt, got %d: %v", calls, err)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 10 * time.Second}
+	cases := map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 8 * time.Second, 5: 10 * time.Second, 20: 10 * time.Second}
+
+	for retry, max := range cases {
+		for i := 0; i < 20; i++ {
+			if backoff := policy.Backoff(retry); backoff < max/2 || backoff > max {
+				t.Errorf("Backoff of retry %d is %s, expected between %s and %s", retry, backoff, max/2, max)
+			}
+		}
+	}
+}
@@ -0,0 +1,529 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Keys read from the SFTP credentials secret. ssh-privatekey matches the
+// kubernetes.io/ssh-auth secret type.
+const (
+	sftpUsernameKey   = "username"
+	sftpPasswordKey   = "password"
+	sftpPrivateKeyKey = "ssh-privatekey"
+	sftpPassphraseKey = "passphrase"
+	sftpHostKeyKey    = "host-key"
+)
+
+const (
+	// sftpDefaultPort is used when the endpoint has no port
+	sftpDefaultPort = "22"
+
+	// sftpPartialSuffix marks a partially transferred snapshot. The partial
+	// file name also carries a digest of the local snapshot so that only a
+	// transfer of the same content is resumed.
+	sftpPartialSuffix = ".partial"
+
+	// sftpDialTimeout bounds establishing the SSH connection
+	sftpDialTimeout = 30 * time.Second
+)
+
+// SFTPStorage stores snapshots on an SFTP server. The endpoint of the storage
+// location is the server address and the bucket is the root directory on
+// the server.
+type SFTPStorage struct {
+	location  etcdguardianv1alpha1.StorageLocation
+	client    client.Client
+	namespace string
+
+	mu         sync.Mutex
+	sshClient  *ssh.Client
+	sftpClient *sftp.Client
+}
+
+// NewSFTPStorage creates a new SFTP storage backend
+func NewSFTPStorage(location etcdguardianv1alpha1.StorageLocation, k8sClient client.Client, namespace string) (*SFTPStorage, error) {
+	if location.Endpoint == "" {
+		return nil, fmt.Errorf("SFTP storage location needs the server address as endpoint")
+	}
+	return &SFTPStorage{
+		location:  location,
+		client:    k8sClient,
+		namespace: namespace,
+	}, nil
+}
+
+// Upload uploads a snapshot to the server, resuming an earlier interrupted
+// transfer of the same snapshot
+func (s *SFTPStorage) Upload(ctx context.Context, localPath string, backup *etcdguardianv1alpha1.EtcdBackup) (string, error) {
+	return putFile(ctx, s, s.location.Prefix, localPath, backup)
+}
+
+// Download downloads a snapshot from the server
+func (s *SFTPStorage) Download(ctx context.Context, remotePath, localPath string) error {
+	return getFile(ctx, s, remotePath, localPath)
+}
+
+// List lists snapshots below the root directory on the server
+func (s *SFTPStorage) List(ctx context.Context, prefix string) ([]SnapshotMetadata, error) {
+	return listSnapshots(ctx, s, s.location.Prefix, prefix)
+}
+
+// Delete deletes a snapshot and its metadata from the server
+func (s *SFTPStorage) Delete(ctx context.Context, remotePath string) error {
+	return deleteObject(ctx, s, remotePath)
+}
+
+// GetMetadata gets snapshot metadata from the file and its sidecar
+func (s *SFTPStorage) GetMetadata(ctx context.Context, remotePath string) (*SnapshotMetadata, error) {
+	return statSnapshot(ctx, s, remotePath)
+}
+
+// PutObject uploads an object to a partial file on the server and renames it
+// into place once complete. Seekable content, such as a local file, resumes
+// an earlier interrupted transfer of the same content.
+func (s *SFTPStorage) PutObject(ctx context.Context, key string, r io.Reader, opts PutOptions) (*ObjectInfo, error) {
+	sftpClient, err := s.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	target := s.remoteFile(key)
+	remotePath := s.remotePath(key)
+	if err := sftpClient.MkdirAll(path.Dir(target)); err != nil {
+		return nil, fmt.Errorf("failed to create directory for %s: %w", remotePath, err)
+	}
+
+	seeker, resumable := r.(io.ReadSeeker)
+	if resumable {
+		err = s.putResumable(ctx, sftpClient, target, seeker, opts)
+	} else {
+		partial := fmt.Sprintf("%s.%d%s", target, time.Now().UnixNano(), sftpPartialSuffix)
+		err = s.putPartial(sftpClient, target, partial, newStreamReader(ctx, r, opts), 0, opts)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload to %s: %w", remotePath, err)
+	}
+
+	// Partial transfers of other content are never resumed
+	stale, _ := sftpClient.Glob(target + ".*" + sftpPartialSuffix)
+	for _, name := range stale {
+		_ = sftpClient.Remove(name)
+	}
+
+	return s.StatObject(ctx, key)
+}
+
+// putResumable uploads seekable content to a partial file named after its
+// digest, continuing after the bytes a partial file of the same content
+// already holds
+func (s *SFTPStorage) putResumable(ctx context.Context, sftpClient *sftp.Client, target string, content io.ReadSeeker, opts PutOptions) error {
+	digest := sha256.New()
+	size, err := io.Copy(digest, content)
+	if err != nil {
+		return fmt.Errorf("failed to read content: %w", err)
+	}
+	sum := hex.EncodeToString(digest.Sum(nil))
+	if opts.SHA256 != "" && !strings.EqualFold(sum, opts.SHA256) {
+		return fmt.Errorf("content checksum %s does not match the expected %s", sum, opts.SHA256)
+	}
+	if opts.Size > 0 && size != opts.Size {
+		return fmt.Errorf("content size %d does not match the expected %d", size, opts.Size)
+	}
+
+	partial := fmt.Sprintf("%s.%s%s", target, sum[:16], sftpPartialSuffix)
+	offset := int64(0)
+	if info, err := sftpClient.Stat(partial); err == nil && info.Size() <= size {
+		offset = info.Size()
+	}
+	if _, err := content.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek content: %w", err)
+	}
+
+	// The content was verified above, only the remainder is sent
+	return s.putPartial(sftpClient, target, partial, newStreamReader(ctx, content, PutOptions{}), offset, opts)
+}
+
+// putPartial writes content to a partial file from offset on, then writes
+// the metadata sidecar and renames the partial file to the target
+func (s *SFTPStorage) putPartial(sftpClient *sftp.Client, target, partial string, content io.Reader, offset int64, opts PutOptions) error {
+	remote, err := sftpClient.OpenFile(partial, os.O_WRONLY|os.O_CREATE)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", partial, err)
+	}
+	defer remote.Close()
+
+	if err := remote.Truncate(offset); err != nil {
+		return fmt.Errorf("failed to truncate %s: %w", partial, err)
+	}
+	if _, err := remote.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek %s: %w", partial, err)
+	}
+	if _, err := io.Copy(remote, content); err != nil {
+		return err
+	}
+	if err := remote.Close(); err != nil {
+		return err
+	}
+
+	metadata, err := json.Marshal(opts.objectMetadata())
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+	if err := s.writeFile(sftpClient, target+metadataSidecarSuffix, metadata); err != nil {
+		return err
+	}
+	if err := s.rename(sftpClient, partial, target); err != nil {
+		return fmt.Errorf("failed to rename %s to %s: %w", partial, target, err)
+	}
+	return nil
+}
+
+// GetObject downloads a file from the server to w
+func (s *SFTPStorage) GetObject(ctx context.Context, key string, w io.WriterAt) (int64, error) {
+	sftpClient, err := s.getClient(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	remote, err := sftpClient.Open(s.remoteFile(key))
+	if err != nil {
+		return 0, s.objectError(key, err)
+	}
+	defer remote.Close()
+
+	return io.Copy(io.NewOffsetWriter(w, 0), newStreamReader(ctx, remote, PutOptions{}))
+}
+
+// StatObject returns the attributes of a file and the metadata in its sidecar
+func (s *SFTPStorage) StatObject(ctx context.Context, key string) (*ObjectInfo, error) {
+	sftpClient, err := s.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := sftpClient.Stat(s.remoteFile(key))
+	if err != nil {
+		return nil, s.objectError(key, err)
+	}
+	return s.objectInfo(sftpClient, key, info)
+}
+
+// ListObjects lists the files below the root directory on the server whose
+// key starts with prefix
+func (s *SFTPStorage) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	sftpClient, err := s.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only walk the deepest directory that can contain matching keys
+	walkRoot := s.remoteFile("")
+	if dir := path.Dir(prefix + "x"); dir != "." {
+		walkRoot = s.remoteFile(dir)
+	}
+
+	objects := []ObjectInfo{}
+	walker := sftpClient.Walk(walkRoot)
+	for walker.Step() {
+		if err := walker.Err(); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to list %s: %w", walkRoot, err)
+		}
+		name := path.Base(walker.Path())
+		if walker.Stat().IsDir() || strings.HasSuffix(name, metadataSidecarSuffix) || strings.HasSuffix(name, sftpPartialSuffix) {
+			continue
+		}
+
+		key := strings.TrimPrefix(strings.TrimPrefix(walker.Path(), s.remoteFile("")), "/")
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		object, err := s.objectInfo(sftpClient, key, walker.Stat())
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, *object)
+	}
+	return objects, nil
+}
+
+// DeleteObject deletes a file and its metadata from the server
+func (s *SFTPStorage) DeleteObject(ctx context.Context, key string) error {
+	sftpClient, err := s.getClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	target := s.remoteFile(key)
+	if err := sftpClient.Remove(target); err != nil {
+		return s.objectError(key, err)
+	}
+	if err := sftpClient.Remove(target + metadataSidecarSuffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete metadata of %s: %w", s.remotePath(key), err)
+	}
+	return nil
+}
+
+// ObjectKey returns the key of the file a remote path addresses, which must
+// point at the configured server
+func (s *SFTPStorage) ObjectKey(remotePath string) (string, error) {
+	host, key, err := splitRemotePath(remotePath, "sftp", s.address())
+	if err != nil {
+		return "", err
+	}
+	if host != s.address() {
+		return "", fmt.Errorf("remote path %q is not on %s", remotePath, s.address())
+	}
+	return strings.TrimPrefix(path.Clean("/"+key), "/"), nil
+}
+
+// objectInfo builds the object attributes of a file
+func (s *SFTPStorage) objectInfo(sftpClient *sftp.Client, key string, info fs.FileInfo) (*ObjectInfo, error) {
+	object := &ObjectInfo{
+		Key:      key,
+		Location: s.remotePath(key),
+		Size:     info.Size(),
+		Created:  info.ModTime(),
+		Metadata: map[string]string{},
+	}
+
+	sidecar, err := sftpClient.Open(s.remoteFile(key) + metadataSidecarSuffix)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return object, nil
+		}
+		return nil, err
+	}
+	defer sidecar.Close()
+
+	if err := json.NewDecoder(sidecar).Decode(&object.Metadata); err != nil {
+		return nil, fmt.Errorf("invalid metadata sidecar of %s: %w", key, err)
+	}
+	return object, nil
+}
+
+// objectError wraps an error of a file operation, marking missing files
+func (s *SFTPStorage) objectError(key string, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%s: %w", s.remotePath(key), ErrObjectNotFound)
+	}
+	return fmt.Errorf("%s: %w", s.remotePath(key), err)
+}
+
+// writeFile writes a small file on the server through a temporary file
+func (s *SFTPStorage) writeFile(sftpClient *sftp.Client, name string, data []byte) error {
+	tmp := name + sftpPartialSuffix
+	remote, err := sftpClient.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", tmp, err)
+	}
+	if _, err := remote.ReadFrom(bytes.NewReader(data)); err != nil {
+		remote.Close()
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if err := remote.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if err := s.rename(sftpClient, tmp, name); err != nil {
+		return fmt.Errorf("failed to rename %s to %s: %w", tmp, name, err)
+	}
+	return nil
+}
+
+// rename replaces newname with oldname. Plain SFTP rename fails when the
+// target exists, so the OpenSSH posix-rename extension is preferred.
+func (s *SFTPStorage) rename(sftpClient *sftp.Client, oldname, newname string) error {
+	if _, ok := sftpClient.HasExtension("posix-rename@openssh.com"); ok {
+		return sftpClient.PosixRename(oldname, newname)
+	}
+	if err := sftpClient.Remove(newname); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return sftpClient.Rename(oldname, newname)
+}
+
+// remoteFile returns the file on the server of an object key
+func (s *SFTPStorage) remoteFile(key string) string {
+	root := s.location.Bucket
+	if root == "" {
+		root = "."
+	}
+	return path.Join(root, path.Clean("/"+key))
+}
+
+// remotePath returns the remote path of an object key
+func (s *SFTPStorage) remotePath(key string) string {
+	return fmt.Sprintf("sftp://%s/%s", s.address(), key)
+}
+
+// address returns the host:port of the server
+func (s *SFTPStorage) address() string {
+	address := strings.TrimSuffix(strings.TrimPrefix(s.location.Endpoint, "sftp://"), "/")
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, sftpDefaultPort)
+	}
+	return address
+}
+
+// getClient returns the SFTP client, connecting on first use and again when
+// the connection was lost
+func (s *SFTPStorage) getClient(ctx context.Context) (*sftp.Client, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sftpClient != nil {
+		if _, err := s.sftpClient.Getwd(); err == nil {
+			return s.sftpClient, nil
+		}
+		s.sftpClient.Close()
+		s.sshClient.Close()
+		s.sftpClient, s.sshClient = nil, nil
+	}
+
+	config, err := s.clientConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := net.Dialer{Timeout: sftpDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.address())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", s.address(), err)
+	}
+	sshConn, channels, requests, err := ssh.NewClientConn(conn, s.address(), config)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("SSH handshake with %s failed: %w", s.address(), err)
+	}
+	sshClient := ssh.NewClient(sshConn, channels, requests)
+
+	sftpClient, err := sftp.NewClient(sshClient)
+	if err != nil {
+		sshClient.Close()
+		return nil, fmt.Errorf("failed to start SFTP session on %s: %w", s.address(), err)
+	}
+
+	s.sshClient, s.sftpClient = sshClient, sftpClient
+	return sftpClient, nil
+}
+
+// clientConfig builds the SSH client configuration from the credentials
+// secret, which must pin the host key of the server
+func (s *SFTPStorage) clientConfig(ctx context.Context) (*ssh.ClientConfig, error) {
+	if s.location.CredentialsSecret == "" {
+		return nil, fmt.Errorf("SFTP storage location needs a credentials secret")
+	}
+	data, err := loadCredentials(ctx, s.client, s.namespace, s.location.CredentialsSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	username := string(data[sftpUsernameKey])
+	if username == "" {
+		return nil, fmt.Errorf("credentials secret %s has no %s", s.location.CredentialsSecret, sftpUsernameKey)
+	}
+
+	hostKeys, err := parseSFTPHostKeys(data[sftpHostKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s in credentials secret %s: %w", sftpHostKeyKey, s.location.CredentialsSecret, err)
+	}
+
+	auth := []ssh.AuthMethod{}
+	if privateKey := data[sftpPrivateKeyKey]; len(privateKey) > 0 {
+		var signer ssh.Signer
+		if passphrase := data[sftpPassphraseKey]; len(passphrase) > 0 {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase(privateKey, passphrase)
+		} else {
+			signer, err = ssh.ParsePrivateKey(privateKey)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in credentials secret %s: %w", sftpPrivateKeyKey, s.location.CredentialsSecret, err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if password := data[sftpPasswordKey]; len(password) > 0 {
+		auth = append(auth, ssh.Password(string(password)))
+	}
+	if len(auth) == 0 {
+		return nil, fmt.Errorf("credentials secret %s has neither %s nor %s", s.location.CredentialsSecret, sftpPrivateKeyKey, sftpPasswordKey)
+	}
+
+	return &ssh.ClientConfig{
+		User: username,
+		Auth: auth,
+		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			for _, hostKey := range hostKeys {
+				if bytes.Equal(hostKey.Marshal(), key.Marshal()) {
+					return nil
+				}
+			}
+			return fmt.Errorf("host key %s of %s is not trusted", ssh.FingerprintSHA256(key), hostname)
+		},
+		Timeout: sftpDialTimeout,
+	}, nil
+}
+
+// parseSFTPHostKeys parses trusted host keys given one per line, either in
+// authorized_keys format or as known_hosts entries
+func parseSFTPHostKeys(data []byte) ([]ssh.PublicKey, error) {
+	keys := []ssh.PublicKey{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		if key, _, _, _, err := ssh.ParseAuthorizedKey(line); err == nil {
+			keys = append(keys, key)
+			continue
+		}
+		_, _, key, _, _, err := ssh.ParseKnownHosts(line)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no host key")
+	}
+	return keys, nil
+}
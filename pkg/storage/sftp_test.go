@@ -0,0 +1,344 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fakeSFTPServer is an in-process SSH server with the SFTP subsystem serving
+// a temporary directory
+type fakeSFTPServer struct {
+	listener   net.Listener
+	root       string
+	hostKey    ssh.Signer
+	userKey    ssh.Signer
+	userKeyPEM string
+	password   string
+
+	// received counts the bytes received over SFTP sessions
+	received atomic.Int64
+}
+
+func newFakeSFTPServer(t *testing.T) *fakeSFTPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	f := &fakeSFTPServer{
+		listener: listener,
+		root:     t.TempDir(),
+		hostKey:  newTestSigner(t),
+		password: "secret",
+	}
+	f.userKey, f.userKeyPEM = newTestKey(t)
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() == "backup" && string(password) == f.password {
+				return nil, nil
+			}
+			return nil, ssh.ErrNoAuth
+		},
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() == "backup" && bytes.Equal(key.Marshal(), f.userKey.PublicKey().Marshal()) {
+				return nil, nil
+			}
+			return nil, ssh.ErrNoAuth
+		},
+	}
+	config.AddHostKey(f.hostKey)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.serveConn(conn, config)
+		}
+	}()
+	t.Cleanup(func() {
+		listener.Close()
+	})
+	return f
+}
+
+func (f *fakeSFTPServer) serveConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unsupported channel")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go func() {
+			for req := range requests {
+				ok := req.Type == "subsystem" && string(req.Payload[4:]) == "sftp"
+				_ = req.Reply(ok, nil)
+				if ok {
+					server, err := sftp.NewServer(&countingChannel{Channel: channel, received: &f.received})
+					if err == nil {
+						_ = server.Serve()
+						server.Close()
+					}
+					channel.Close()
+				}
+			}
+		}()
+	}
+}
+
+// countingChannel counts the bytes read from an SSH channel
+type countingChannel struct {
+	ssh.Channel
+	received *atomic.Int64
+}
+
+func (c *countingChannel) Read(p []byte) (int, error) {
+	n, err := c.Channel.Read(p)
+	c.received.Add(int64(n))
+	return n, err
+}
+
+func newTestSigner(t *testing.T) ssh.Signer {
+	signer, _ := newTestKey(t)
+	return signer
+}
+
+// newTestKey generates an ed25519 key and returns it as signer and in
+// OpenSSH PEM format
+func newTestKey(t *testing.T) (ssh.Signer, string) {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+	block, err := ssh.MarshalPrivateKey(key, "")
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	return signer, string(pem.EncodeToMemory(block))
+}
+
+func newTestSFTPStorage(t *testing.T, server *fakeSFTPServer, k8sClient client.Client) *SFTPStorage {
+	t.Helper()
+
+	storage, err := NewStorage(etcdguardianv1alpha1.StorageProviderSFTP, etcdguardianv1alpha1.StorageLocation{
+		Provider:          etcdguardianv1alpha1.StorageProviderSFTP,
+		Bucket:            server.root,
+		Prefix:            "etcd",
+		Endpoint:          server.listener.Addr().String(),
+		CredentialsSecret: "sftp-credentials",
+	}, k8sClient, "default")
+	if err != nil {
+		t.Fatalf("NewStorage failed: %v", err)
+	}
+	return storage.(*SFTPStorage)
+}
+
+func sftpPasswordSecret(server *fakeSFTPServer) client.Object {
+	return credentialsSecret("sftp-credentials", map[string]string{
+		sftpUsernameKey: "backup",
+		sftpPasswordKey: server.password,
+		sftpHostKeyKey:  string(ssh.MarshalAuthorizedKey(server.hostKey.PublicKey())),
+	})
+}
+
+func TestSFTPStorage_UploadDownload(t *testing.T) {
+	server := newFakeSFTPServer(t)
+	storage := newTestSFTPStorage(t, server, newFakeClient(sftpPasswordSecret(server)))
+
+	snapshotPath, data := writeSnapshot(t, 256*1024)
+	ctx := context.Background()
+
+	location, err := storage.Upload(ctx, snapshotPath, testBackup("nightly"))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if location != "sftp://"+server.listener.Addr().String()+"/etcd/default/nightly/etcd-snapshot.db" {
+		t.Errorf("Unexpected location %q", location)
+	}
+
+	dir := filepath.Join(server.root, "etcd", "default", "nightly")
+	entries, _ := os.ReadDir(dir)
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if strings.Join(names, ",") != "etcd-snapshot.db,etcd-snapshot.db"+metadataSidecarSuffix {
+		t.Errorf("Unexpected files on server: %v", names)
+	}
+
+	metadata, err := storage.GetMetadata(ctx, location)
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if metadata.Size != int64(len(data)) {
+		t.Errorf("Expected size %d, got %d", len(data), metadata.Size)
+	}
+	if metadata.EtcdRevision != 42 {
+		t.Errorf("Expected revision 42, got %d", metadata.EtcdRevision)
+	}
+
+	downloadPath := filepath.Join(t.TempDir(), "restored.db")
+	if err := storage.Download(ctx, location, downloadPath); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	downloaded, err := os.ReadFile(downloadPath)
+	if err != nil {
+		t.Fatalf("Failed to read download: %v", err)
+	}
+	if !bytes.Equal(downloaded, data) {
+		t.Error("Downloaded snapshot does not match uploaded data")
+	}
+}
+
+func TestSFTPStorage_ResumesPartialUpload(t *testing.T) {
+	server := newFakeSFTPServer(t)
+	storage := newTestSFTPStorage(t, server, newFakeClient(sftpPasswordSecret(server)))
+	snapshotPath, data := writeSnapshot(t, 1024*1024)
+
+	// Leave the first 900KiB of an interrupted transfer of the same snapshot
+	// behind, next to a partial file of different content
+	dir := filepath.Join(server.root, "etcd", "default", "nightly")
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	target := filepath.Join(dir, "etcd-snapshot.db")
+	digest := sha256.Sum256(data)
+	partial := target + "." + hex.EncodeToString(digest[:])[:16] + sftpPartialSuffix
+	if err := os.WriteFile(partial, data[:900*1024], 0o600); err != nil {
+		t.Fatalf("Failed to write partial file: %v", err)
+	}
+	if err := os.WriteFile(target+".0000000000000000"+sftpPartialSuffix, []byte("stale"), 0o600); err != nil {
+		t.Fatalf("Failed to write stale partial file: %v", err)
+	}
+
+	if _, err := storage.Upload(context.Background(), snapshotPath, testBackup("nightly")); err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if received := server.received.Load(); received >= int64(len(data))/2 {
+		t.Errorf("Expected only the remainder to be sent, server received %d bytes", received)
+	}
+
+	uploaded, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatalf("Failed to read snapshot: %v", err)
+	}
+	if !bytes.Equal(uploaded, data) {
+		t.Error("Resumed snapshot does not match local snapshot")
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(dir, "*"+sftpPartialSuffix)); len(leftovers) != 0 {
+		t.Errorf("Expected partial files to be removed, got %v", leftovers)
+	}
+}
+
+func TestSFTPStorage_ListAndDelete(t *testing.T) {
+	server := newFakeSFTPServer(t)
+	k8sClient := newFakeClient(credentialsSecret("sftp-credentials", map[string]string{
+		sftpUsernameKey:   "backup",
+		sftpPrivateKeyKey: server.userKeyPEM,
+		sftpHostKeyKey:    "[127.0.0.1]:22 " + strings.TrimSpace(string(ssh.MarshalAuthorizedKey(server.hostKey.PublicKey()))),
+	}))
+	storage := newTestSFTPStorage(t, server, k8sClient)
+	ctx := context.Background()
+
+	snapshotPath, _ := writeSnapshot(t, 1024)
+	for _, name := range []string{"daily", "daily-2", "hourly"} {
+		if _, err := storage.Upload(ctx, snapshotPath, testBackup(name)); err != nil {
+			t.Fatalf("Upload of %s failed: %v", name, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(server.root, "etcd", "default", "hourly", "next.db.0123456789abcdef"+sftpPartialSuffix), []byte("partial"), 0o600); err != nil {
+		t.Fatalf("Failed to write partial file: %v", err)
+	}
+
+	snapshots, err := storage.List(ctx, "")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(snapshots) != 3 {
+		t.Fatalf("Expected 3 snapshots, got %+v", snapshots)
+	}
+
+	snapshots, err = storage.List(ctx, "default/daily/")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(snapshots) != 1 || !strings.HasSuffix(snapshots[0].Path, "/etcd/default/daily/etcd-snapshot.db") || snapshots[0].EtcdRevision != 42 {
+		t.Fatalf("Unexpected snapshots for prefix: %+v", snapshots)
+	}
+
+	if err := storage.Delete(ctx, snapshots[0].Path); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := storage.GetMetadata(ctx, snapshots[0].Path); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected not found error for deleted snapshot, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(server.root, "etcd", "default", "daily", "etcd-snapshot.db"+metadataSidecarSuffix)); !os.IsNotExist(err) {
+		t.Error("Expected metadata sidecar to be deleted")
+	}
+}
+
+func TestSFTPStorage_RejectsUnknownHostKey(t *testing.T) {
+	server := newFakeSFTPServer(t)
+	k8sClient := newFakeClient(credentialsSecret("sftp-credentials", map[string]string{
+		sftpUsernameKey: "backup",
+		sftpPasswordKey: server.password,
+		sftpHostKeyKey:  string(ssh.MarshalAuthorizedKey(newTestSigner(t).PublicKey())),
+	}))
+	storage := newTestSFTPStorage(t, server, k8sClient)
+
+	_, err := storage.List(context.Background(), "")
+	if err == nil || !strings.Contains(err.Error(), "not trusted") {
+		t.Errorf("Expected untrusted host key error, got %v", err)
+	}
+}
@@ -18,18 +18,31 @@ package storage
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// Storage defines the interface for storage backends
+// Storage defines the interface for storage backends. Upload and Download
+// transfer local snapshot files, such as those staged in the snapshot
+// directory, and are implemented on top of ObjectStore by the streaming
+// backends. Content that is not a file, such as manifests, goes through
+// ObjectStore.
 type Storage interface {
-	// Upload uploads a snapshot to storage
+	// Upload uploads a local snapshot file to storage
 	Upload(ctx context.Context, localPath string, backup *etcdguardianv1alpha1.EtcdBackup) (string, error)
 
-	// Download downloads a snapshot from storage
+	// Download downloads a snapshot from storage to a local file
 	Download(ctx context.Context, remotePath, localPath string) error
 
 	// List lists snapshots in storage
@@ -42,6 +55,172 @@ type Storage interface {
 	GetMetadata(ctx context.Context, remotePath string) (*SnapshotMetadata, error)
 }
 
+// ResumableUploader is implemented by storage backends whose multipart
+// uploads can be resumed from persisted state, by another process if need be
+type ResumableUploader interface {
+	// UploadResumable uploads a snapshot as a multipart upload tracked in
+	// session. A session of an earlier attempt is resumed when it targets the
+	// same object, otherwise its upload is aborted and a new one initiated.
+	// checkpoint, if set, is called whenever the session changes so that the
+	// caller can persist it.
+	UploadResumable(ctx context.Context, localPath string, backup *etcdguardianv1alpha1.EtcdBackup, session *etcdguardianv1alpha1.UploadSession, checkpoint func(*etcdguardianv1alpha1.UploadSession) error) (string, error)
+
+	// ListUploads lists the multipart uploads in progress under the
+	// location prefix
+	ListUploads(ctx context.Context) ([]PendingUpload, error)
+
+	// AbortUpload aborts a multipart upload and discards its parts
+	AbortUpload(ctx context.Context, remotePath, uploadID string) error
+}
+
+// PendingUpload is a multipart upload that was initiated but not completed
+type PendingUpload struct {
+	RemotePath string
+	UploadID   string
+	Initiated  time.Time
+}
+
+// ObjectLocker is implemented by storage backends that can store snapshots
+// write-once (WORM)
+type ObjectLocker interface {
+	// LockObject retains an object in the given mode until retainUntil
+	LockObject(ctx context.Context, remotePath string, mode etcdguardianv1alpha1.ImmutabilityMode, retainUntil time.Time) error
+
+	// SetLegalHold places or releases the legal hold of an object
+	SetLegalHold(ctx context.Context, remotePath string, hold bool) error
+}
+
+// ValidateImmutability checks that the provider of a storage location
+// supports its immutability settings, and legal holds if requested
+func ValidateImmutability(location etcdguardianv1alpha1.StorageLocation, legalHold bool) error {
+	if location.Immutability == nil {
+		return nil
+	}
+
+	switch location.Provider {
+	case etcdguardianv1alpha1.StorageProviderAzure:
+	case etcdguardianv1alpha1.StorageProviderOSS:
+		if location.Immutability.Mode != etcdguardianv1alpha1.ImmutabilityModeCompliance {
+			return fmt.Errorf("OSS retention policies only support the %s mode", etcdguardianv1alpha1.ImmutabilityModeCompliance)
+		}
+		if legalHold {
+			return fmt.Errorf("OSS does not support legal holds")
+		}
+	case etcdguardianv1alpha1.StorageProviderS3:
+		return fmt.Errorf("S3 Object Lock is not implemented yet")
+	default:
+		return fmt.Errorf("storage provider %s does not support immutability", location.Provider)
+	}
+	return nil
+}
+
+// EncryptionVerifier is implemented by storage backends that report the
+// server-side encryption of stored objects
+type EncryptionVerifier interface {
+	// VerifyEncryption checks that an object is encrypted as the
+	// server-side encryption settings of the location require
+	VerifyEncryption(ctx context.Context, remotePath string) error
+}
+
+// Tierer is implemented by storage backends that can move stored objects
+// to colder storage classes and bring archived objects back
+type Tierer interface {
+	// SetTier moves an object to the storage class of a tier
+	SetTier(ctx context.Context, remotePath string, tier etcdguardianv1alpha1.StorageTier) error
+
+	// Rehydrate makes an archived object readable again. It requests the
+	// rehydration if none is in progress and reports whether the object
+	// can be read. Objects that are not archived are readable right away.
+	Rehydrate(ctx context.Context, remotePath string) (bool, error)
+}
+
+// ValidateTiering checks that the provider of a storage location supports
+// moving snapshots to colder storage tiers
+func ValidateTiering(location etcdguardianv1alpha1.StorageLocation) error {
+	switch location.Provider {
+	case etcdguardianv1alpha1.StorageProviderAzure:
+	case etcdguardianv1alpha1.StorageProviderOSS:
+		// Objects change their storage class by being copied onto
+		// themselves, which the bucket retention policy forbids
+		if location.Immutability != nil {
+			return fmt.Errorf("OSS cannot change the storage class of objects under a retention policy")
+		}
+	case etcdguardianv1alpha1.StorageProviderS3:
+		return fmt.Errorf("S3 storage tiers are not implemented yet")
+	default:
+		return fmt.Errorf("storage provider %s does not support storage tiers", location.Provider)
+	}
+	return nil
+}
+
+// MaxPresignExpiry is the longest validity of a presigned URL, the limit of
+// the V4 signatures of S3 and GCS
+const MaxPresignExpiry = 7 * 24 * time.Hour
+
+// Presigner is implemented by storage backends that can grant time-limited
+// read access to a stored object to clients without credentials
+type Presigner interface {
+	// PresignURL returns a URL that downloads an object with a plain GET
+	// request until the expiry has elapsed
+	PresignURL(ctx context.Context, remotePath string, expiry time.Duration) (string, error)
+}
+
+// sseCustomerKeyKey is the key of the SSE-C key in its secret
+const sseCustomerKeyKey = "sse-customer-key"
+
+// ValidateServerSideEncryption checks that the provider of a storage
+// location supports its server-side encryption settings
+func ValidateServerSideEncryption(location etcdguardianv1alpha1.StorageLocation) error {
+	sse := location.ServerSideEncryption
+	if sse == nil {
+		return nil
+	}
+
+	switch sse.Mode {
+	case etcdguardianv1alpha1.ServerSideEncryptionSSES3, etcdguardianv1alpha1.ServerSideEncryptionSSEKMS:
+	case etcdguardianv1alpha1.ServerSideEncryptionSSEC:
+		if sse.CustomerKeySecret == "" {
+			return fmt.Errorf("%s needs a customer key secret", sse.Mode)
+		}
+	default:
+		return fmt.Errorf("unsupported server-side encryption mode %q", sse.Mode)
+	}
+
+	switch location.Provider {
+	case etcdguardianv1alpha1.StorageProviderS3:
+		return fmt.Errorf("verifying the server-side encryption of S3 objects is not implemented yet")
+	case etcdguardianv1alpha1.StorageProviderOSS:
+		if sse.Mode == etcdguardianv1alpha1.ServerSideEncryptionSSEC {
+			return fmt.Errorf("OSS does not support %s", sse.Mode)
+		}
+	case etcdguardianv1alpha1.StorageProviderGCS:
+		if sse.Mode == etcdguardianv1alpha1.ServerSideEncryptionSSEKMS && sse.KMSKeyID == "" {
+			return fmt.Errorf("GCS needs the KMS key name for %s", sse.Mode)
+		}
+	default:
+		return fmt.Errorf("storage provider %s does not support server-side encryption settings", location.Provider)
+	}
+	return nil
+}
+
+// loadCustomerKey reads the SSE-C key of a storage location from its secret
+func loadCustomerKey(ctx context.Context, k8sClient client.Client, namespace string, location etcdguardianv1alpha1.StorageLocation) ([]byte, error) {
+	name := location.ServerSideEncryption.CustomerKeySecret
+	data, err := loadCredentials(ctx, k8sClient, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	encoded, ok := data[sseCustomerKeyKey]
+	if !ok {
+		return nil, fmt.Errorf("secret %s has no %s", name, sseCustomerKeyKey)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("%s of secret %s is not a base64 encoded 256-bit key", sseCustomerKeyKey, name)
+	}
+	return key, nil
+}
+
 // SnapshotMetadata contains snapshot metadata
 type SnapshotMetadata struct {
 	Name              string
@@ -49,8 +228,22 @@ type SnapshotMetadata struct {
 	Size              int64
 	CreationTimestamp int64
 	EtcdVersion       string
+	EtcdRevision      int64
 }
 
+// Object metadata keys attached to every uploaded snapshot
+const (
+	metadataBackupName      = "backup-name"
+	metadataBackupNamespace = "backup-namespace"
+	metadataBackupMode      = "backup-mode"
+	metadataEtcdRevision    = "etcd-revision"
+	metadataEtcdVersion     = "etcd-version"
+)
+
+// metadataSidecarSuffix is appended to a snapshot file name to form the name
+// of the JSON file holding its metadata, for providers without object metadata
+const metadataSidecarSuffix = ".metadata.json"
+
 // NewStorage creates a new storage backend based on the provider
 func NewStorage(provider etcdguardianv1alpha1.StorageProvider, location etcdguardianv1alpha1.StorageLocation, k8sClient client.Client, namespace string) (Storage, error) {
 	switch provider {
@@ -59,10 +252,126 @@ func NewStorage(provider etcdguardianv1alpha1.StorageProvider, location etcdguar
 	case etcdguardianv1alpha1.StorageProviderOSS:
 		return NewOSSStorage(location, k8sClient, namespace)
 	case etcdguardianv1alpha1.StorageProviderGCS:
-		return nil, fmt.Errorf("GCS storage not yet implemented")
+		return NewGCSStorage(location, k8sClient, namespace)
 	case etcdguardianv1alpha1.StorageProviderAzure:
-		return nil, fmt.Errorf("Azure storage not yet implemented")
+		return NewAzureStorage(location, k8sClient, namespace)
+	case etcdguardianv1alpha1.StorageProviderFilesystem:
+		return NewFilesystemStorage(location)
+	case etcdguardianv1alpha1.StorageProviderSFTP:
+		return NewSFTPStorage(location, k8sClient, namespace)
+	case ProviderMemory:
+		if memoryEnabled.Load() {
+			return newMemoryBackend(location)
+		}
+		return nil, fmt.Errorf("unsupported storage provider: %s", provider)
 	default:
 		return nil, fmt.Errorf("unsupported storage provider: %s", provider)
 	}
 }
+
+// snapshotKey returns the object key of a snapshot uploaded for a backup
+func snapshotKey(prefix string, backup *etcdguardianv1alpha1.EtcdBackup, localPath string) string {
+	return path.Join(prefix, backup.Namespace, backup.Name, filepath.Base(localPath))
+}
+
+// listPrefix joins the location prefix and a caller supplied prefix, keeping
+// a trailing slash so that "ns/backup/" does not match "ns/backup-2"
+func listPrefix(locationPrefix, prefix string) string {
+	joined := path.Join(locationPrefix, prefix)
+	if joined == "." {
+		return ""
+	}
+	if strings.HasSuffix(prefix, "/") || (prefix == "" && locationPrefix != "") {
+		joined += "/"
+	}
+	return joined
+}
+
+// splitRemotePath splits a remote path such as "oss://bucket/key" into the
+// bucket and object key. Paths without a scheme are treated as object keys
+// in the default bucket.
+func splitRemotePath(remotePath, scheme, defaultBucket string) (bucket, key string, err error) {
+	rest, ok := strings.CutPrefix(remotePath, scheme+"://")
+	if !ok {
+		if strings.Contains(remotePath, "://") {
+			return "", "", fmt.Errorf("remote path %q is not a %s path", remotePath, scheme)
+		}
+		return defaultBucket, strings.TrimPrefix(remotePath, "/"), nil
+	}
+
+	bucket, key, _ = strings.Cut(rest, "/")
+	if bucket == "" || key == "" {
+		return "", "", fmt.Errorf("invalid %s path %q", scheme, remotePath)
+	}
+	return bucket, key, nil
+}
+
+// snapshotObjectMetadata returns the user metadata stored with a snapshot object
+func snapshotObjectMetadata(backup *etcdguardianv1alpha1.EtcdBackup) map[string]string {
+	return map[string]string{
+		metadataBackupName:      backup.Name,
+		metadataBackupNamespace: backup.Namespace,
+		metadataBackupMode:      string(backup.Spec.BackupMode),
+		metadataEtcdRevision:    strconv.FormatInt(backup.Status.EtcdRevision, 10),
+	}
+}
+
+// applyObjectMetadata fills snapshot metadata from object user metadata
+func (m *SnapshotMetadata) applyObjectMetadata(meta map[string]string) {
+	if version, ok := meta[metadataEtcdVersion]; ok {
+		m.EtcdVersion = version
+	}
+	if revision, err := strconv.ParseInt(meta[metadataEtcdRevision], 10, 64); err == nil {
+		m.EtcdRevision = revision
+	}
+}
+
+// writeFileAtomic writes r to a temporary file next to localPath and renames
+// it into place once the content is complete
+func writeFileAtomic(localPath string, r io.Reader) error {
+	return writeFileAtomicWith(localPath, func(file *os.File) error {
+		if _, err := io.Copy(file, r); err != nil {
+			return fmt.Errorf("failed to write %s: %w", localPath, err)
+		}
+		return nil
+	})
+}
+
+// writeFileAtomicWith lets write fill a temporary file next to localPath and
+// renames it into place once write succeeds
+func writeFileAtomicWith(localPath string, write func(*os.File) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(localPath), filepath.Base(localPath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync %s: %w", localPath, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", localPath, err)
+	}
+	if err := os.Rename(tmp.Name(), localPath); err != nil {
+		return fmt.Errorf("failed to rename %s: %w", tmp.Name(), err)
+	}
+	return nil
+}
+
+// loadCredentials reads the data of the credentials secret of a storage location
+func loadCredentials(ctx context.Context, k8sClient client.Client, namespace, name string) (map[string][]byte, error) {
+	if k8sClient == nil {
+		return nil, fmt.Errorf("no kubernetes client to read credentials secret %s", name)
+	}
+
+	secret := &corev1.Secret{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get credentials secret %s/%s: %w", namespace, name, err)
+	}
+	return secret.Data, nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TombstoneSuffix is appended to the snapshot file name to name the
+// tombstone that marks the snapshot of a deleted backup
+const TombstoneSuffix = ".deleted"
+
+// TombstonePath returns the path of the tombstone of a snapshot
+func TombstonePath(snapshotPath string) string {
+	return snapshotPath + TombstoneSuffix
+}
+
+// isTombstoneKey reports whether an object key names a tombstone rather
+// than a snapshot
+func isTombstoneKey(key string) bool {
+	return strings.HasSuffix(key, TombstoneSuffix)
+}
+
+// WriteTombstone marks a stored snapshot as belonging to a deleted backup.
+// Listings leave out marked snapshots, so that they are not synced back
+// into the cluster, while the snapshot itself is kept, as it may be under
+// retention. Backends that cannot be listed need no tombstone.
+func WriteTombstone(ctx context.Context, backend Storage, remotePath string) error {
+	store, ok := backend.(ObjectStore)
+	if !ok {
+		return nil
+	}
+	key, err := store.ObjectKey(remotePath)
+	if err != nil {
+		return err
+	}
+
+	content := []byte(time.Now().UTC().Format(time.RFC3339))
+	if _, err := store.PutObject(ctx, TombstonePath(key), bytes.NewReader(content), PutOptions{Size: int64(len(content))}); err != nil {
+		return fmt.Errorf("failed to write tombstone of %s: %w", remotePath, err)
+	}
+	return nil
+}
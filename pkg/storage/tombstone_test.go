@@ -0,0 +1,52 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWriteTombstone_HidesSnapshotFromListing(t *testing.T) {
+	storage, _ := newTestFilesystemStorage(t)
+	ctx := context.Background()
+
+	snapshotPath, _ := writeSnapshot(t, 1024)
+	deleted, err := storage.Upload(ctx, snapshotPath, testBackup("nightly"))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if _, err := storage.Upload(ctx, snapshotPath, testBackup("weekly")); err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+
+	if err := WriteTombstone(ctx, storage, deleted); err != nil {
+		t.Fatalf("WriteTombstone failed: %v", err)
+	}
+	snapshots, err := storage.List(ctx, "")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].Path == deleted {
+		t.Errorf("Expected only the snapshot of the remaining backup to be listed, got %+v", snapshots)
+	}
+
+	// The snapshot itself is kept
+	if _, err := storage.GetMetadata(ctx, deleted); err != nil {
+		t.Errorf("Expected the marked snapshot to be kept, got %v", err)
+	}
+}
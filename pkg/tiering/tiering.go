@@ -0,0 +1,226 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tiering moves the stored snapshots of completed backups to colder
+// storage tiers as they age, following the tiering rules of their retention
+// policy, and rehydrates archived snapshots for restores.
+package tiering
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/replication"
+	"github.com/etcdguardian/etcdguardian/pkg/storage"
+)
+
+// Mover moves the snapshots of backups between storage tiers. Storage
+// operations run with the retry policy of its replicator, which records
+// them in the backup status.
+type Mover struct {
+	replicator *replication.Replicator
+	log        logr.Logger
+}
+
+// NewMover creates a new mover
+func NewMover(replicator *replication.Replicator, log logr.Logger) *Mover {
+	return &Mover{replicator: replicator, log: log}
+}
+
+// Validate checks the tiering rules of a backup: their ages must
+// increase with the tiers getting colder, stay below the MaxAge of the
+// retention policy and every destination must support storage tiers
+func Validate(backup *etcdguardianv1alpha1.EtcdBackup, targets []replication.Target) error {
+	if backup.Spec.RetentionPolicy == nil || len(backup.Spec.RetentionPolicy.Tiering) == 0 {
+		return nil
+	}
+	policy := backup.Spec.RetentionPolicy
+
+	var previous *etcdguardianv1alpha1.TieringRule
+	for i := range policy.Tiering {
+		rule := &policy.Tiering[i]
+		if tierRank(rule.Tier) == 0 {
+			return fmt.Errorf("unsupported storage tier %q", rule.Tier)
+		}
+		if rule.After.Duration <= 0 {
+			return fmt.Errorf("tier %s needs a positive age", rule.Tier)
+		}
+		if previous != nil && (rule.After.Duration <= previous.After.Duration || tierRank(rule.Tier) <= tierRank(previous.Tier)) {
+			return fmt.Errorf("tier %s after %s must follow tier %s after %s with a longer age and a colder tier",
+				rule.Tier, rule.After.Duration, previous.Tier, previous.After.Duration)
+		}
+		if policy.MaxAge != nil && rule.After.Duration >= policy.MaxAge.Duration {
+			return fmt.Errorf("tier %s after %s is not before the maxAge of %s", rule.Tier, rule.After.Duration, policy.MaxAge.Duration)
+		}
+		previous = rule
+	}
+
+	for _, target := range targets {
+		if err := storage.ValidateTiering(target.Location); err != nil {
+			return fmt.Errorf("destination %s: %w", target.Name, err)
+		}
+	}
+	return nil
+}
+
+// tierRank orders storage tiers from the default storage class, 0, to the
+// coldest tier
+func tierRank(tier etcdguardianv1alpha1.StorageTier) int {
+	switch tier {
+	case "":
+		return 0
+	case etcdguardianv1alpha1.StorageTierInfrequentAccess:
+		return 1
+	case etcdguardianv1alpha1.StorageTierArchive:
+		return 2
+	}
+	return 0
+}
+
+// DueTier returns the tier the snapshots of a completed backup belong in at
+// the given time, empty for none yet, and when the next tiering rule becomes
+// due, zero for none. Ages count from the completion of the backup.
+func DueTier(backup *etcdguardianv1alpha1.EtcdBackup, now time.Time) (etcdguardianv1alpha1.StorageTier, time.Time) {
+	if backup.Status.Phase != etcdguardianv1alpha1.BackupPhaseCompleted || backup.Status.CompletionTime == nil ||
+		backup.Spec.RetentionPolicy == nil {
+		return "", time.Time{}
+	}
+
+	var tier etcdguardianv1alpha1.StorageTier
+	for _, rule := range backup.Spec.RetentionPolicy.Tiering {
+		due := backup.Status.CompletionTime.Add(rule.After.Duration)
+		if due.After(now) {
+			return tier, due
+		}
+		tier = rule.Tier
+	}
+	return tier, time.Time{}
+}
+
+// Pending reports whether a stored snapshot of a backup is in a
+// warmer tier than its tiering rules require at the given time
+func Pending(backup *etcdguardianv1alpha1.EtcdBackup, now time.Time) bool {
+	due, _ := DueTier(backup, now)
+	for _, status := range backup.Status.Replicas {
+		if status.Phase == etcdguardianv1alpha1.ReplicaPhaseCompleted && tierRank(status.Tier) < tierRank(due) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply moves the snapshot in every destination holding it to the
+// tier its tiering rules require at the given time, and records the tier in
+// the destination status. Manifests stay in the default storage class so
+// that storage locations can be scanned without rehydration.
+func (m *Mover) Apply(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup, now time.Time) error {
+	due, _ := DueTier(backup, now)
+	if due == "" {
+		return nil
+	}
+	targets, err := m.replicator.SyncTargets(ctx, backup)
+	if err != nil {
+		return err
+	}
+
+	failures := []string{}
+	for i, target := range targets {
+		status := &backup.Status.Replicas[i]
+		if status.Phase != etcdguardianv1alpha1.ReplicaPhaseCompleted || tierRank(status.Tier) >= tierRank(due) {
+			continue
+		}
+
+		backend, err := m.replicator.Backend(backup, target)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", target.Name, err))
+			continue
+		}
+		tierer, ok := backend.(storage.Tierer)
+		if !ok {
+			failures = append(failures, fmt.Sprintf("%s: storage provider %s does not support storage tiers", target.Name, target.Location.Provider))
+			continue
+		}
+
+		err = m.replicator.Retry(ctx, backup, target.Name, storage.OperationTier, func(ctx context.Context) error {
+			return tierer.SetTier(ctx, status.Location, due)
+		})
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", target.Name, err))
+			continue
+		}
+
+		m.log.Info("Moved snapshot to storage tier", "destination", target.Name, "tier", due)
+		status.Tier = due
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to move snapshots to tier %s: %s", due, strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// Rehydrate makes a snapshot of a backup readable for a restore. A
+// destination whose snapshot is not archived is used right away. Otherwise
+// rehydration of the snapshot in the first destination holding it is
+// requested, or polled when already requested. It returns the location of
+// the snapshot and whether it can be read.
+func (m *Mover) Rehydrate(ctx context.Context, backup *etcdguardianv1alpha1.EtcdBackup) (string, bool, error) {
+	targets, err := m.replicator.SyncTargets(ctx, backup)
+	if err != nil {
+		return "", false, err
+	}
+
+	var archived *replication.Target
+	var archivedStatus etcdguardianv1alpha1.ReplicaStatus
+	for i := range targets {
+		status := backup.Status.Replicas[i]
+		if status.Phase != etcdguardianv1alpha1.ReplicaPhaseCompleted {
+			continue
+		}
+		if status.Tier != etcdguardianv1alpha1.StorageTierArchive {
+			return status.Location, true, nil
+		}
+		if archived == nil {
+			archived, archivedStatus = &targets[i], status
+		}
+	}
+	if archived == nil {
+		return "", false, fmt.Errorf("no destination holds the snapshot of backup %s", backup.Name)
+	}
+
+	backend, err := m.replicator.Backend(backup, *archived)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create storage backend: %w", err)
+	}
+	tierer, ok := backend.(storage.Tierer)
+	if !ok {
+		return "", false, fmt.Errorf("storage provider %s does not support storage tiers", archived.Location.Provider)
+	}
+
+	var ready bool
+	err = m.replicator.Retry(ctx, backup, archived.Name, storage.OperationRehydrate, func(ctx context.Context) (err error) {
+		ready, err = tierer.Rehydrate(ctx, archivedStatus.Location)
+		return err
+	})
+	if err != nil {
+		return "", false, err
+	}
+	return archivedStatus.Location, ready, nil
+}
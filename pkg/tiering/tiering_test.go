@@ -0,0 +1,259 @@
+/*
+Copyright 2026 EtcdGuardian Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiering
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdguardianv1alpha1 "github.com/etcdguardian/etcdguardian/api/v1alpha1"
+	"github.com/etcdguardian/etcdguardian/pkg/replication"
+	"github.com/etcdguardian/etcdguardian/pkg/storage"
+)
+
+// tieringStorage records the tiers of the objects of the Filesystem
+// provider. Archived objects listed in rehydrated are readable, others
+// start rehydrating when asked.
+type tieringStorage struct {
+	storage.Storage
+
+	tiers      map[string]etcdguardianv1alpha1.StorageTier
+	rehydrated map[string]bool
+	requested  map[string]bool
+}
+
+func (s *tieringStorage) SetTier(_ context.Context, remotePath string, tier etcdguardianv1alpha1.StorageTier) error {
+	s.tiers[remotePath] = tier
+	return nil
+}
+
+func (s *tieringStorage) Rehydrate(_ context.Context, remotePath string) (bool, error) {
+	if s.tiers[remotePath] != etcdguardianv1alpha1.StorageTierArchive || s.rehydrated[remotePath] {
+		return true, nil
+	}
+	s.requested[remotePath] = true
+	return false, nil
+}
+
+// newTestMover returns a replicator wrapping every backend in tieringStorage
+// and a mover using it
+func newTestMover(tierer *tieringStorage) (*replication.Replicator, *Mover) {
+	replicator := replication.NewReplicator(nil, logr.Discard()).WithStorage(func(provider etcdguardianv1alpha1.StorageProvider, location etcdguardianv1alpha1.StorageLocation, k8sClient client.Client, namespace string) (storage.Storage, error) {
+		backend, err := storage.NewStorage(provider, location, k8sClient, namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &tieringStorage{Storage: backend, tiers: tierer.tiers, rehydrated: tierer.rehydrated, requested: tierer.requested}, nil
+	})
+	return replicator, NewMover(replicator, logr.Discard())
+}
+
+// testTargets returns the destinations of a backup with inline locations
+func testTargets(t *testing.T, backup *etcdguardianv1alpha1.EtcdBackup) []replication.Target {
+	t.Helper()
+	targets, err := replication.Targets(context.Background(), nil, backup)
+	if err != nil {
+		t.Fatalf("Failed to resolve destinations: %v", err)
+	}
+	return targets
+}
+
+func writeTestSnapshot(t *testing.T) string {
+	t.Helper()
+
+	snapshotPath := filepath.Join(t.TempDir(), "etcd-snapshot.db")
+	if err := os.WriteFile(snapshotPath, []byte("snapshot data"), 0o600); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+	return snapshotPath
+}
+
+// newTieredBackup returns a completed backup stored in two Filesystem
+// destinations, moving to InfrequentAccess after a week and to Archive
+// after a month
+func newTieredBackup(t *testing.T, completed time.Time) *etcdguardianv1alpha1.EtcdBackup {
+	return &etcdguardianv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+		Spec: etcdguardianv1alpha1.EtcdBackupSpec{
+			BackupMode: etcdguardianv1alpha1.BackupModeFull,
+			StorageLocation: &etcdguardianv1alpha1.StorageLocation{
+				Provider: etcdguardianv1alpha1.StorageProviderFilesystem,
+				Bucket:   t.TempDir(),
+			},
+			Replicas: []etcdguardianv1alpha1.ReplicaLocation{{
+				Name: "dr-west",
+				StorageLocation: &etcdguardianv1alpha1.StorageLocation{
+					Provider: etcdguardianv1alpha1.StorageProviderFilesystem,
+					Bucket:   t.TempDir(),
+				},
+			}},
+			RetentionPolicy: &etcdguardianv1alpha1.RetentionPolicy{
+				MaxAge: &metav1.Duration{Duration: 365 * 24 * time.Hour},
+				Tiering: []etcdguardianv1alpha1.TieringRule{
+					{After: metav1.Duration{Duration: 7 * 24 * time.Hour}, Tier: etcdguardianv1alpha1.StorageTierInfrequentAccess},
+					{After: metav1.Duration{Duration: 30 * 24 * time.Hour}, Tier: etcdguardianv1alpha1.StorageTierArchive},
+				},
+			},
+		},
+		Status: etcdguardianv1alpha1.EtcdBackupStatus{
+			Phase:          etcdguardianv1alpha1.BackupPhaseCompleted,
+			CompletionTime: &metav1.Time{Time: completed},
+		},
+	}
+}
+
+func TestDueTier(t *testing.T) {
+	completed := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	backup := newTieredBackup(t, completed)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		age  time.Duration
+		tier etcdguardianv1alpha1.StorageTier
+		next time.Time
+	}{
+		{age: day, tier: "", next: completed.Add(7 * day)},
+		{age: 7 * day, tier: etcdguardianv1alpha1.StorageTierInfrequentAccess, next: completed.Add(30 * day)},
+		{age: 100 * day, tier: etcdguardianv1alpha1.StorageTierArchive},
+	}
+	for _, tt := range tests {
+		tier, next := DueTier(backup, completed.Add(tt.age))
+		if tier != tt.tier || !next.Equal(tt.next) {
+			t.Errorf("At age %s expected tier %q until %s, got %q until %s", tt.age, tt.tier, tt.next, tier, next)
+		}
+	}
+
+	backup.Status.Phase = etcdguardianv1alpha1.BackupPhaseFailed
+	if tier, next := DueTier(backup, completed.Add(100*day)); tier != "" || !next.IsZero() {
+		t.Errorf("Expected no tiering of a failed backup, got %q until %s", tier, next)
+	}
+}
+
+func TestMover_Apply(t *testing.T) {
+	completed := time.Now()
+	backup := newTieredBackup(t, completed)
+	tierer := &tieringStorage{tiers: map[string]etcdguardianv1alpha1.StorageTier{}, rehydrated: map[string]bool{}, requested: map[string]bool{}}
+	replicator, mover := newTestMover(tierer)
+
+	if err := replicator.Replicate(context.Background(), backup, writeTestSnapshot(t)); err != nil {
+		t.Fatalf("Replicate failed: %v", err)
+	}
+	if Pending(backup, completed) {
+		t.Fatal("Expected no tiering of a new backup")
+	}
+
+	week := completed.Add(8 * 24 * time.Hour)
+	if !Pending(backup, week) {
+		t.Fatal("Expected tiering to be pending after a week")
+	}
+	if err := mover.Apply(context.Background(), backup, week); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	for _, status := range backup.Status.Replicas {
+		if status.Tier != etcdguardianv1alpha1.StorageTierInfrequentAccess || tierer.tiers[status.Location] != etcdguardianv1alpha1.StorageTierInfrequentAccess {
+			t.Errorf("Expected destination %s in InfrequentAccess, got %q", status.Name, status.Tier)
+		}
+		if _, ok := tierer.tiers[status.ManifestLocation]; ok {
+			t.Errorf("Expected the manifest of destination %s to keep its storage class", status.Name)
+		}
+	}
+	if Pending(backup, week) {
+		t.Error("Expected no tiering pending once applied")
+	}
+
+	month := completed.Add(31 * 24 * time.Hour)
+	if err := mover.Apply(context.Background(), backup, month); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if backup.Status.Replicas[1].Tier != etcdguardianv1alpha1.StorageTierArchive {
+		t.Errorf("Expected the replica in Archive, got %q", backup.Status.Replicas[1].Tier)
+	}
+}
+
+func TestMover_Rehydrate(t *testing.T) {
+	backup := newTieredBackup(t, time.Now().Add(-31*24*time.Hour))
+	tierer := &tieringStorage{tiers: map[string]etcdguardianv1alpha1.StorageTier{}, rehydrated: map[string]bool{}, requested: map[string]bool{}}
+	replicator, mover := newTestMover(tierer)
+
+	if err := replicator.Replicate(context.Background(), backup, writeTestSnapshot(t)); err != nil {
+		t.Fatalf("Replicate failed: %v", err)
+	}
+	if err := mover.Apply(context.Background(), backup, time.Now()); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	primary := backup.Status.Replicas[0].Location
+	location, ready, err := mover.Rehydrate(context.Background(), backup)
+	if err != nil || ready || location != primary || !tierer.requested[primary] {
+		t.Fatalf("Expected rehydration of the primary to be requested, got %s, %v, %v", location, ready, err)
+	}
+
+	tierer.rehydrated[primary] = true
+	if location, ready, err := mover.Rehydrate(context.Background(), backup); err != nil || !ready || location != primary {
+		t.Fatalf("Expected the rehydrated primary to be readable, got %s, %v, %v", location, ready, err)
+	}
+
+	// A destination that is not archived is used without rehydration
+	backup.Status.Replicas[1].Tier = etcdguardianv1alpha1.StorageTierInfrequentAccess
+	if location, ready, err := mover.Rehydrate(context.Background(), backup); err != nil || !ready || location != backup.Status.Replicas[1].Location {
+		t.Errorf("Expected the replica in InfrequentAccess to be used, got %s, %v, %v", location, ready, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	backup := newTieredBackup(t, time.Now())
+	if err := Validate(backup, testTargets(t, backup)); err == nil {
+		t.Error("Expected error for a Filesystem location")
+	}
+
+	backup.Spec.StorageLocation.Provider = etcdguardianv1alpha1.StorageProviderOSS
+	backup.Spec.Replicas[0].StorageLocation.Provider = etcdguardianv1alpha1.StorageProviderAzure
+	if err := Validate(backup, testTargets(t, backup)); err != nil {
+		t.Errorf("Expected valid tiering rules: %v", err)
+	}
+
+	backup.Spec.StorageLocation.Immutability = &etcdguardianv1alpha1.ImmutabilityConfig{Mode: etcdguardianv1alpha1.ImmutabilityModeCompliance}
+	if err := Validate(backup, testTargets(t, backup)); err == nil {
+		t.Error("Expected error for an immutable OSS location")
+	}
+	backup.Spec.StorageLocation.Immutability = nil
+
+	backup.Spec.Replicas[0].StorageLocation.Provider = etcdguardianv1alpha1.StorageProviderS3
+	if err := Validate(backup, testTargets(t, backup)); err == nil {
+		t.Error("Expected error for an S3 location")
+	}
+	backup.Spec.Replicas[0].StorageLocation.Provider = etcdguardianv1alpha1.StorageProviderAzure
+
+	rules := backup.Spec.RetentionPolicy.Tiering
+	backup.Spec.RetentionPolicy.Tiering = []etcdguardianv1alpha1.TieringRule{rules[1], rules[0]}
+	if err := Validate(backup, testTargets(t, backup)); err == nil {
+		t.Error("Expected error for rules out of order")
+	}
+
+	backup.Spec.RetentionPolicy.Tiering = rules
+	backup.Spec.RetentionPolicy.MaxAge = &metav1.Duration{Duration: 30 * 24 * time.Hour}
+	if err := Validate(backup, testTargets(t, backup)); err == nil {
+		t.Error("Expected error for a rule not before maxAge")
+	}
+}